@@ -11,16 +11,19 @@ import (
 	"time"
 
 	"github.com/juzu400/avito-internship/internal/config"
+	"github.com/juzu400/avito-internship/internal/domain"
 	"github.com/juzu400/avito-internship/internal/logger"
 	"github.com/juzu400/avito-internship/internal/migrations"
 	"github.com/juzu400/avito-internship/internal/repository"
 	"github.com/juzu400/avito-internship/internal/service"
 	httptransport "github.com/juzu400/avito-internship/internal/transport/http"
+	"github.com/juzu400/avito-internship/internal/worker"
 )
 
 // Command avito-internship starts HTTP API server for the internship task.
 // It loads configuration, initializes logger and database connection,
-// applies database migrations and runs the HTTP server with graceful shutdown.
+// applies database migrations, starts background workers and runs the HTTP server
+// with graceful shutdown.
 func main() {
 	cfg := config.MustLoad()
 	log := logger.New(logger.Config{Level: cfg.LogLevel})
@@ -43,14 +46,39 @@ func main() {
 	log.Info("migrations applied")
 
 	repos := repository.NewRepositories(db)
-	services := service.NewServices(log, repos)
-	router := httptransport.NewRouter(log, services)
+	services := service.NewServices(log, repos, service.Config{
+		ReviewerStrategy:  domain.ReviewerStrategy(cfg.ReviewerStrategy),
+		SeedByPullRequest: cfg.SeedAssignmentByPR,
+		StaleReviewAge:    cfg.StaleReviewAge,
+		IdempotencyTTL:    cfg.IdempotencyTTL,
+	})
+	router := httptransport.NewRouter(log, services, cfg.AdminToken)
 
 	srv := &http.Server{
 		Addr:    cfg.HTTPAddr,
 		Handler: router,
 	}
 
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	go worker.Run(workersCtx, log, "reviewer_top_up", cfg.TopUpInterval, func(ctx context.Context) error {
+		_, err := services.PullRequests.TopUpReviewers(ctx)
+		return err
+	})
+	go worker.Run(workersCtx, log, "review_sla", cfg.ReviewSLAInterval, func(ctx context.Context) error {
+		_, err := services.PullRequests.CheckReviewSLA(ctx)
+		return err
+	})
+	go worker.Run(workersCtx, log, "review_reminders", cfg.ReminderInterval, func(ctx context.Context) error {
+		_, err := services.Digests.SendReminders(ctx)
+		return err
+	})
+	go worker.Run(workersCtx, log, "idempotency_cleanup", cfg.IdempotencyCleanupInterval, func(ctx context.Context) error {
+		_, err := services.Idempotency.DeleteExpired(ctx)
+		return err
+	})
+
 	go func() {
 		log.Info("server starting", slog.String("addr", cfg.HTTPAddr))
 		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -63,6 +91,7 @@ func main() {
 	<-stop
 
 	log.Info("server shutting down")
+	stopWorkers()
 
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer shutdownCancel()
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+type AbsenceID int64
+
+// Absence is a scheduled out-of-office window of a user. While it is active
+// the user is not picked as a reviewer regardless of the is_active flag.
+type Absence struct {
+	ID       AbsenceID
+	UserID   UserID
+	StartsAt time.Time
+	EndsAt   time.Time
+	// DelegateID is an optional user who covers reviews during the absence. It is
+	// stored as a delegation over the same window.
+	DelegateID  UserID
+	CreatedAt   time.Time
+	CancelledAt *time.Time
+}
+
+// IsCancelled reports whether the absence was cancelled.
+func (a *Absence) IsCancelled() bool {
+	return a.CancelledAt != nil
+}
+
+// ActiveAt reports whether the absence covers the given moment.
+func (a *Absence) ActiveAt(t time.Time) bool {
+	return !a.IsCancelled() && !t.Before(a.StartsAt) && t.Before(a.EndsAt)
+}
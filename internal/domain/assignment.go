@@ -0,0 +1,27 @@
+package domain
+
+// AssignmentReason tells why a candidate was or was not picked as a reviewer.
+type AssignmentReason string
+
+const (
+	AssignmentReasonSelected        AssignmentReason = "selected"
+	AssignmentReasonDelegate        AssignmentReason = "delegate"
+	AssignmentReasonNotSelected     AssignmentReason = "not_selected"
+	AssignmentReasonInactive        AssignmentReason = "inactive"
+	AssignmentReasonAuthor          AssignmentReason = "author"
+	AssignmentReasonAlreadyAssigned AssignmentReason = "already_assigned"
+	AssignmentReasonAtCapacity      AssignmentReason = "at_capacity"
+	AssignmentReasonOutOfOffice     AssignmentReason = "out_of_office"
+)
+
+// CandidateExplanation describes the decision made about one reviewer candidate.
+type CandidateExplanation struct {
+	UserID   UserID
+	TeamName string
+	Picked   bool
+	Reason   AssignmentReason
+	// DelegateID is the user standing in for an out-of-office candidate.
+	DelegateID UserID
+	// DelegatedFrom is the absent user a picked delegate replaces.
+	DelegatedFrom UserID
+}
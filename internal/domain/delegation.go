@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+type DelegationID int64
+
+// Delegation hands the reviews of a user over to a named substitute for a period.
+// While it is in effect the user is not picked as a reviewer; the delegate takes
+// their place when eligible.
+type Delegation struct {
+	ID         DelegationID
+	FromUserID UserID
+	ToUserID   UserID
+	StartsAt   time.Time
+	EndsAt     time.Time
+	// AbsenceID is set when the delegation was requested together with an absence
+	// and is cancelled with it.
+	AbsenceID   *AbsenceID
+	CreatedAt   time.Time
+	CancelledAt *time.Time
+}
+
+// IsCancelled reports whether the delegation was cancelled.
+func (d *Delegation) IsCancelled() bool {
+	return d.CancelledAt != nil
+}
+
+// ActiveAt reports whether the delegation is in effect at the given moment.
+func (d *Delegation) ActiveAt(t time.Time) bool {
+	return !d.IsCancelled() && !t.Before(d.StartsAt) && t.Before(d.EndsAt)
+}
@@ -0,0 +1,30 @@
+package domain
+
+// DependencyNode is a pull request in a dependency graph.
+type DependencyNode struct {
+	ID     PullRequestID
+	Name   string
+	Status PullRequestStatus
+}
+
+// DependencyEdge says that PullRequestID depends on DependsOnID and cannot be
+// merged while DependsOnID blocks its dependents.
+type DependencyEdge struct {
+	PullRequestID PullRequestID
+	DependsOnID   PullRequestID
+}
+
+// DependencyGraph is the stack a pull request belongs to: every pull request it
+// depends on and every pull request that depends on it, directly or transitively,
+// with the dependencies between them.
+type DependencyGraph struct {
+	Nodes []DependencyNode
+	Edges []DependencyEdge
+}
+
+// BlocksDependents reports whether a pull request in this status keeps the pull
+// requests that depend on it from being merged. Closed pull requests are
+// abandoned and do not block.
+func (s PullRequestStatus) BlocksDependents() bool {
+	return s == PRStatusDraft || s == PRStatusOpen
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// StalePullRequest is an OPEN pull request that has been waiting for reviews
+// longer than the stale threshold.
+type StalePullRequest struct {
+	ID       PullRequestID
+	Name     string
+	AuthorID UserID
+	// TeamName is the author's team; empty if the author is not in a team.
+	TeamName  string
+	CreatedAt time.Time
+	// WaitingSince is when the longest waiting of PendingReviewers was assigned.
+	WaitingSince time.Time
+	// Age is how long the review has been waited for, since WaitingSince, when
+	// the digest was built.
+	Age time.Duration
+	// PendingReviewers are assigned reviewers who have not submitted a review yet.
+	PendingReviewers []UserID
+}
+
+// ReviewReminder asks a reviewer to look at their stale pull requests.
+type ReviewReminder struct {
+	ReviewerID   UserID
+	PullRequests []StalePullRequest
+}
+
+// TeamDigest lists stale pull requests authored by members of a team, oldest first.
+type TeamDigest struct {
+	TeamName     string
+	GeneratedAt  time.Time
+	StaleAfter   time.Duration
+	PullRequests []StalePullRequest
+}
+
+// StaleReviewReport is the outcome of one reminder run.
+type StaleReviewReport struct {
+	Reminders []ReviewReminder
+	Digests   []TeamDigest
+}
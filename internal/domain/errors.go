@@ -8,8 +8,18 @@ import "errors"
 var (
 	ErrNotFound                 = errors.New("resource not found")
 	ErrPullRequestAlreadyMerged = errors.New("pull request already merged")
+	ErrPullRequestNotOpen       = errors.New("pull request is not open")
+	ErrInvalidStatusTransition  = errors.New("invalid pull request status transition")
 	ErrReviewerNotAssigned      = errors.New("reviewer not assigned to pull request")
+	ErrReviewerAlreadyAssigned  = errors.New("reviewer already assigned to pull request")
 	ErrNoReviewerCandidates     = errors.New("no reviewer candidates available")
+	ErrReviewerAtCapacity       = errors.New("reviewer is at capacity")
+	ErrNotApproved              = errors.New("pull request does not have enough approvals")
+	ErrDependencyNotMerged      = errors.New("pull request depends on pull requests that are not merged")
+	ErrDependencyCycle          = errors.New("pull request dependency would create a cycle")
+	ErrVersionConflict          = errors.New("pull request was changed concurrently")
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was used for another request")
+	ErrIdempotencyInProgress    = errors.New("request with this idempotency key is still in progress")
 	ErrPullRequestAlreadyExists = errors.New("pull request already exists")
 	ErrTeamAlreadyExists        = errors.New("team already exists")
 	ErrValidation               = errors.New("validation error")
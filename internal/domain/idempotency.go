@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// MaxIdempotencyKeyLength limits the length of a client-supplied idempotency key.
+const MaxIdempotencyKeyLength = 255
+
+// IdempotencyKey identifies a request sent with an idempotency key. The key
+// chosen by the client only applies to the method, path and actor it was sent
+// with, so different operations and different users never share a key.
+type IdempotencyKey struct {
+	Method  string
+	Path    string
+	ActorID UserID
+	Key     string
+}
+
+// IdempotentRequest is a request sent with an idempotency key.
+type IdempotentRequest struct {
+	Key IdempotencyKey
+	// RequestHash fingerprints the query, If-Match header and body of the request,
+	// so that a retry can be told apart from another request that reuses the key.
+	RequestHash string
+	// Response is nil while the first request with the key is still being handled.
+	Response *IdempotentResponse
+	// LockedUntil ends the lease of the request being handled. A key without a
+	// response whose lease ended is taken over by the next request with it.
+	LockedUntil time.Time
+	ExpiresAt   time.Time
+}
+
+// IdempotentResponse is the stored response that is replayed to retries.
+type IdempotentResponse struct {
+	StatusCode int
+	// Header keeps the response headers that are replayed, such as Content-Type.
+	Header map[string]string
+	Body   []byte
+}
@@ -1,28 +1,215 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
 
 type PullRequestID string
 
 type PullRequestStatus string
 
 const (
+	// PRStatusDraft is a work in progress: no reviewers are assigned until it is marked ready.
+	PRStatusDraft  PullRequestStatus = "DRAFT"
 	PRStatusOpen   PullRequestStatus = "OPEN"
 	PRStatusMerged PullRequestStatus = "MERGED"
+	// PRStatusClosed is an abandoned pull request. Its reviewers are released; it can be reopened.
+	PRStatusClosed PullRequestStatus = "CLOSED"
+)
+
+// PullRequestPriority tells how urgent a pull request is.
+type PullRequestPriority string
+
+const (
+	PriorityLow      PullRequestPriority = "LOW"
+	PriorityNormal   PullRequestPriority = "NORMAL"
+	PriorityHigh     PullRequestPriority = "HIGH"
+	PriorityCritical PullRequestPriority = "CRITICAL"
 )
 
+// IsValid reports whether p is one of the known priorities.
+func (p PullRequestPriority) IsValid() bool {
+	switch p {
+	case PriorityLow, PriorityNormal, PriorityHigh, PriorityCritical:
+		return true
+	default:
+		return false
+	}
+}
+
+// MaxLabels and MaxLabelLength limit the labels of a single pull request.
+const (
+	MaxLabels      = 20
+	MaxLabelLength = 50
+)
+
+// NormalizeLabels trims and lower-cases labels, drops duplicates and sorts them.
+// Empty, too long or too many labels are reported as ErrValidation.
+func NormalizeLabels(labels []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(labels))
+	res := make([]string, 0, len(labels))
+	for _, l := range labels {
+		l = strings.ToLower(strings.TrimSpace(l))
+		switch {
+		case l == "":
+			return nil, fmt.Errorf("%w: empty label", ErrValidation)
+		case len(l) > MaxLabelLength:
+			return nil, fmt.Errorf("%w: label %q is longer than %d characters", ErrValidation, l, MaxLabelLength)
+		}
+		if _, ok := seen[l]; ok {
+			continue
+		}
+		seen[l] = struct{}{}
+		res = append(res, l)
+	}
+	if len(res) > MaxLabels {
+		return nil, fmt.Errorf("%w: at most %d labels are allowed", ErrValidation, MaxLabels)
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+// PullRequestAttributes are optional properties of a new pull request.
+// An empty priority means PriorityNormal.
+type PullRequestAttributes struct {
+	Labels   []string
+	Priority PullRequestPriority
+}
+
+// Normalize returns a copy of a with normalized labels and the default priority
+// filled in. Invalid labels or an unknown priority are reported as ErrValidation.
+func (a PullRequestAttributes) Normalize() (PullRequestAttributes, error) {
+	labels, err := NormalizeLabels(a.Labels)
+	if err != nil {
+		return PullRequestAttributes{}, err
+	}
+	priority := a.Priority
+	if priority == "" {
+		priority = PriorityNormal
+	}
+	if !priority.IsValid() {
+		return PullRequestAttributes{}, fmt.Errorf("%w: unknown priority %q", ErrValidation, priority)
+	}
+	return PullRequestAttributes{Labels: labels, Priority: priority}, nil
+}
+
+// pullRequestTransitions lists the statuses a pull request may move to from each status.
+// MERGED is final.
+var pullRequestTransitions = map[PullRequestStatus][]PullRequestStatus{
+	PRStatusDraft:  {PRStatusOpen, PRStatusClosed},
+	PRStatusOpen:   {PRStatusMerged, PRStatusClosed},
+	PRStatusClosed: {PRStatusOpen},
+}
+
+// IsValid reports whether s is one of the known pull request statuses.
+func (s PullRequestStatus) IsValid() bool {
+	switch s {
+	case PRStatusDraft, PRStatusOpen, PRStatusMerged, PRStatusClosed:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanTransitionTo reports whether a pull request in status s may move to next.
+func (s PullRequestStatus) CanTransitionTo(next PullRequestStatus) bool {
+	for _, allowed := range pullRequestTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 // PullRequest represents a simplified pull request with author, status
 // and assigned reviewers.
 type PullRequest struct {
-	ID                PullRequestID
-	Name              string
-	AuthorID          UserID
-	Status            PullRequestStatus
+	ID       PullRequestID
+	Name     string
+	AuthorID UserID
+	Status   PullRequestStatus
+	// Version grows with every change of the pull request, its reviewers,
+	// reviews or dependencies. It starts at 1.
+	Version int64
+	// Labels are normalized with NormalizeLabels.
+	Labels            []string
+	Priority          PullRequestPriority
 	AssignedReviewers []UserID
-	CreatedAt         time.Time
-	MergedAt          *time.Time
+	// Dependencies is only filled when the pull request is read on its own.
+	Dependencies *DependencyGraph
+	// FallbackReviewers maps reviewers taken from a fallback team to that team's name.
+	FallbackReviewers map[UserID]string
+	// DelegatedReviewers maps reviewers assigned as delegates to the absent users they stand in for.
+	DelegatedReviewers map[UserID]UserID
+	// ReviewDueAt maps reviewers to their review deadline under the team's SLA.
+	ReviewDueAt map[UserID]time.Time
+	// OverdueReviewers lists reviewers who missed their review deadline.
+	OverdueReviewers []UserID
+	// AssignmentExplanation lists every candidate considered by the last automatic
+	// assignment and why they were picked or skipped. It is not persisted.
+	AssignmentExplanation []CandidateExplanation
+	CreatedAt             time.Time
+	MergedAt              *time.Time
+	ClosedAt              *time.Time
+}
+
+// PullRequestUpdate lists mutable metadata of a pull request.
+// Nil fields are left unchanged.
+type PullRequestUpdate struct {
+	Name *string
+	// Labels, when set, replace all labels of the pull request.
+	Labels   *[]string
+	Priority *PullRequestPriority
+}
+
+// IsEmpty reports whether the update changes nothing.
+func (u PullRequestUpdate) IsEmpty() bool {
+	return u.Name == nil && u.Labels == nil && u.Priority == nil
 }
 
 func (p *PullRequest) IsMerged() bool {
 	return p.Status == PRStatusMerged
 }
+
+// TransitionTo moves the pull request to the next status at the given time.
+// It returns ErrInvalidStatusTransition if the state machine does not allow the move.
+// Closing releases all reviewers; reopening clears the close time.
+func (p *PullRequest) TransitionTo(next PullRequestStatus, at time.Time) error {
+	if !p.Status.CanTransitionTo(next) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, p.Status, next)
+	}
+
+	switch next {
+	case PRStatusMerged:
+		p.MergedAt = &at
+	case PRStatusClosed:
+		p.ClosedAt = &at
+		p.AssignedReviewers = []UserID{}
+		p.FallbackReviewers = nil
+		p.DelegatedReviewers = nil
+		p.ReviewDueAt = nil
+		p.OverdueReviewers = nil
+	case PRStatusOpen:
+		p.ClosedAt = nil
+	}
+	p.Status = next
+	return nil
+}
+
+type expectedVersionKey struct{}
+
+// WithExpectedVersion returns a context whose changes of a pull request only apply
+// while the pull request is still at the given version.
+func WithExpectedVersion(ctx context.Context, version int64) context.Context {
+	return context.WithValue(ctx, expectedVersionKey{}, version)
+}
+
+// ExpectedVersion returns the pull request version that ctx expects, if any.
+func ExpectedVersion(ctx context.Context) (int64, bool) {
+	v, ok := ctx.Value(expectedVersionKey{}).(int64)
+	return v, ok
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type PullRequestEventID int64
+
+type PullRequestEventType string
+
+const (
+	PREventCreated            PullRequestEventType = "CREATED"
+	PREventUpdated            PullRequestEventType = "UPDATED"
+	PREventStatusChanged      PullRequestEventType = "STATUS_CHANGED"
+	PREventReviewerAssigned   PullRequestEventType = "REVIEWER_ASSIGNED"
+	PREventReviewerRemoved    PullRequestEventType = "REVIEWER_REMOVED"
+	PREventReviewerReassigned PullRequestEventType = "REVIEWER_REASSIGNED"
+	PREventReviewSubmitted    PullRequestEventType = "REVIEW_SUBMITTED"
+	// PREventReviewOverdue marks a reviewer (OldReviewerID) who missed the review SLA.
+	PREventReviewOverdue PullRequestEventType = "REVIEW_OVERDUE"
+	// PREventDependencyAdded and PREventDependencyRemoved keep the other pull request in Details.
+	PREventDependencyAdded   PullRequestEventType = "DEPENDENCY_ADDED"
+	PREventDependencyRemoved PullRequestEventType = "DEPENDENCY_REMOVED"
+)
+
+// EventReason explains why a change was made.
+type EventReason string
+
+const (
+	// ReasonManual marks reviewers chosen or removed explicitly by the caller.
+	ReasonManual EventReason = "manual"
+	// ReasonAutomatic marks reviewers picked by the team's assignment strategy.
+	ReasonAutomatic EventReason = "automatic"
+	// ReasonTopUp marks reviewers added by the background top-up job.
+	ReasonTopUp EventReason = "top_up"
+	// ReasonDeactivation marks reviews handed over or dropped because the reviewer was deactivated.
+	ReasonDeactivation EventReason = "deactivation"
+	// ReasonClosed marks reviewers released when the pull request was closed.
+	ReasonClosed EventReason = "closed"
+	// ReasonForced marks merges done by an admin without enough approvals.
+	ReasonForced EventReason = "forced"
+	// ReasonSLA marks missed review deadlines and the reassignments they trigger.
+	ReasonSLA EventReason = "sla"
+)
+
+// PullRequestEvent is an append-only record of one change to a pull request.
+// Fields that do not apply to the event type are empty.
+type PullRequestEvent struct {
+	ID            PullRequestEventID
+	PullRequestID PullRequestID
+	Type          PullRequestEventType
+	// ActorID is the user who made the change; empty for system actions.
+	ActorID       UserID
+	OldReviewerID UserID
+	NewReviewerID UserID
+	OldStatus     PullRequestStatus
+	NewStatus     PullRequestStatus
+	Decision      ReviewDecision
+	Reason        EventReason
+	// Details is a human-readable note, e.g. whom a delegate stands in for.
+	Details   string
+	CreatedAt time.Time
+}
+
+// Audit describes who makes a change and why. It travels in the request context
+// so that repositories can record it with the events they write.
+type Audit struct {
+	ActorID UserID
+	Reason  EventReason
+}
+
+type auditKey struct{}
+
+// AuditFromContext returns the audit information attached to ctx, if any.
+func AuditFromContext(ctx context.Context) Audit {
+	a, _ := ctx.Value(auditKey{}).(Audit)
+	return a
+}
+
+// WithActor returns a context that attributes changes to the given user.
+func WithActor(ctx context.Context, actorID UserID) context.Context {
+	a := AuditFromContext(ctx)
+	a.ActorID = actorID
+	return context.WithValue(ctx, auditKey{}, a)
+}
+
+// WithDefaultActor attributes changes to actorID unless ctx already names an actor.
+func WithDefaultActor(ctx context.Context, actorID UserID) context.Context {
+	if AuditFromContext(ctx).ActorID != "" {
+		return ctx
+	}
+	return WithActor(ctx, actorID)
+}
+
+// WithDefaultReason records reason with changes unless ctx already carries one.
+func WithDefaultReason(ctx context.Context, reason EventReason) context.Context {
+	if AuditFromContext(ctx).Reason != "" {
+		return ctx
+	}
+	return WithReason(ctx, reason)
+}
+
+// WithReason returns a context that records the given reason with changes.
+func WithReason(ctx context.Context, reason EventReason) context.Context {
+	a := AuditFromContext(ctx)
+	a.Reason = reason
+	return context.WithValue(ctx, auditKey{}, a)
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// PullRequestSortField is the key pull request listings are ordered by.
+// Ties are always broken by pull request ID in the same direction.
+type PullRequestSortField string
+
+const (
+	PRSortCreatedAt PullRequestSortField = "created_at"
+	PRSortName      PullRequestSortField = "name"
+)
+
+// IsValid reports whether f is a supported sort field.
+func (f PullRequestSortField) IsValid() bool {
+	return f == PRSortCreatedAt || f == PRSortName
+}
+
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// IsValid reports whether o is a supported sort order.
+func (o SortOrder) IsValid() bool {
+	return o == SortAsc || o == SortDesc
+}
+
+// PullRequestFilter narrows a pull request listing. Zero fields do not filter.
+// Time ranges include the lower bound and exclude the upper one.
+type PullRequestFilter struct {
+	Statuses   []PullRequestStatus
+	AuthorID   UserID
+	ReviewerID UserID
+	// TeamName matches pull requests whose author is a member of the team.
+	TeamName     string
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+	MergedFrom   *time.Time
+	MergedTo     *time.Time
+	NameContains string
+	// Labels keeps pull requests that carry all of the given labels.
+	Labels     []string
+	Priorities []PullRequestPriority
+}
+
+// PullRequestListQuery describes one page of a pull request listing.
+type PullRequestListQuery struct {
+	Filter PullRequestFilter
+	SortBy PullRequestSortField
+	Order  SortOrder
+	Limit  int
+	// After continues the listing right after the item the cursor was taken from.
+	After *PullRequestCursor
+}
+
+// ReviewListQuery describes one page of the pull requests a user reviews.
+// Such listings are always ordered by creation time, newest first.
+// A zero Limit returns every matching pull request on a single page.
+type ReviewListQuery struct {
+	Statuses []PullRequestStatus
+	Limit    int
+	After    *PullRequestCursor
+}
+
+// PullRequestPage is one page of a pull request listing.
+// NextCursor is nil on the last page.
+type PullRequestPage struct {
+	Items      []*PullRequest
+	NextCursor *PullRequestCursor
+}
+
+// PullRequestCursor points at the last item of a page. It keeps the sort key of
+// that item, so pages stay stable when pull requests are added in between.
+type PullRequestCursor struct {
+	SortBy    PullRequestSortField `json:"s"`
+	Order     SortOrder            `json:"o"`
+	CreatedAt time.Time            `json:"c"`
+	Name      string               `json:"n,omitempty"`
+	ID        PullRequestID        `json:"i"`
+}
+
+// NewPullRequestCursor returns the cursor positioned at pr in a listing
+// ordered by sortBy and order.
+func NewPullRequestCursor(sortBy PullRequestSortField, order SortOrder, pr *PullRequest) *PullRequestCursor {
+	c := &PullRequestCursor{SortBy: sortBy, Order: order, ID: pr.ID}
+	switch sortBy {
+	case PRSortName:
+		c.Name = pr.Name
+	default:
+		c.CreatedAt = pr.CreatedAt
+	}
+	return c
+}
+
+// Encode returns the opaque string form of the cursor handed out to clients.
+func (c *PullRequestCursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodePullRequestCursor parses a cursor produced by Encode.
+// Malformed cursors are reported as ErrValidation.
+func DecodePullRequestCursor(s string) (*PullRequestCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrValidation)
+	}
+	var c PullRequestCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == "" || !c.SortBy.IsValid() || !c.Order.IsValid() {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrValidation)
+	}
+	return &c, nil
+}
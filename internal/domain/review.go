@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// ReviewDecision is the verdict a reviewer gives on a pull request.
+type ReviewDecision string
+
+const (
+	ReviewDecisionApproved         ReviewDecision = "APPROVED"
+	ReviewDecisionChangesRequested ReviewDecision = "CHANGES_REQUESTED"
+	ReviewDecisionCommented        ReviewDecision = "COMMENTED"
+)
+
+// IsValid reports whether d is one of the known review decisions.
+func (d ReviewDecision) IsValid() bool {
+	switch d {
+	case ReviewDecisionApproved, ReviewDecisionChangesRequested, ReviewDecisionCommented:
+		return true
+	default:
+		return false
+	}
+}
+
+// Review is the latest decision of an assigned reviewer on a pull request.
+// A new submission by the same reviewer replaces the previous one.
+type Review struct {
+	PullRequestID PullRequestID
+	ReviewerID    UserID
+	Decision      ReviewDecision
+	SubmittedAt   time.Time
+	// PullRequestVersion is the version of the pull request once the review was saved.
+	PullRequestVersion int64
+}
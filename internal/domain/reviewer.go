@@ -0,0 +1,19 @@
+package domain
+
+// ReviewerStrategy names an algorithm used to choose reviewers among eligible candidates.
+type ReviewerStrategy string
+
+const (
+	ReviewerStrategyRandom      ReviewerStrategy = "random"
+	ReviewerStrategyRoundRobin  ReviewerStrategy = "round_robin"
+	ReviewerStrategyLeastLoaded ReviewerStrategy = "least_loaded"
+)
+
+// IsValid reports whether s is one of the known reviewer strategies.
+func (s ReviewerStrategy) IsValid() bool {
+	switch s {
+	case ReviewerStrategyRandom, ReviewerStrategyRoundRobin, ReviewerStrategyLeastLoaded:
+		return true
+	}
+	return false
+}
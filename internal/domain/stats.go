@@ -1,9 +1,13 @@
 package domain
 
+import "time"
+
 // ReviewerAssignmentStat represents statistics about the number of pull request
 type ReviewerAssignmentStat struct {
 	ReviewerID       UserID
 	AssignmentsCount int
+	// OverdueCount is the number of those assignments that missed the review SLA.
+	OverdueCount int
 }
 
 // PullRequestReviewersStat represents statistics about the number of reviewers
@@ -11,3 +15,31 @@ type PullRequestReviewersStat struct {
 	PullRequestID  PullRequestID
 	ReviewersCount int
 }
+
+// UnderstaffedPullRequest is an OPEN pull request that has fewer reviewers than
+// its author's team wants.
+type UnderstaffedPullRequest struct {
+	PullRequestID  PullRequestID
+	AuthorID       UserID
+	ReviewersCount int
+	// ReviewersTarget is how many reviewers the pull request should have under the
+	// settings of the author's team, including its review rules.
+	ReviewersTarget int
+}
+
+// OverdueReview is a review assignment that missed its SLA deadline.
+type OverdueReview struct {
+	PullRequestID PullRequestID
+	ReviewerID    UserID
+	DueAt         time.Time
+	// AutoReassign reports whether the author's team wants overdue reviews handed over.
+	AutoReassign bool
+	// ReassignedTo is the reviewer who took the review over, if it was reassigned.
+	ReassignedTo UserID
+}
+
+// ReviewerTopUp describes reviewers added to an under-staffed pull request.
+type ReviewerTopUp struct {
+	PullRequestID PullRequestID
+	Added         []UserID
+}
@@ -1,7 +1,202 @@
 package domain
 
+import "time"
+
+// DefaultReviewersCount is the number of reviewers assigned to a pull request
+// when the team has no explicit settings.
+const DefaultReviewersCount = 2
+
+// TeamSettings holds per-team reviewer assignment rules.
+type TeamSettings struct {
+	// ReviewersCount is the desired number of reviewers per pull request.
+	ReviewersCount int
+	// MinReviewers is the minimum number of reviewers a pull request must get on creation.
+	MinReviewers int
+	// Strategy overrides the deployment-wide reviewer strategy when not empty.
+	Strategy ReviewerStrategy
+	// RequiredApprovals is the number of assigned reviewers that must approve a pull
+	// request before it can be merged. Zero disables the check.
+	RequiredApprovals int
+	// FallbackTeams lists teams, in order, that supply reviewers when the team runs out of candidates.
+	FallbackTeams []string
+	// ReviewSLAHours is how long a reviewer of the team's pull requests has for the
+	// first review. Zero disables SLA tracking.
+	ReviewSLAHours int
+	// ReviewSLABusinessDays makes the SLA clock stop on Saturdays and Sundays (UTC).
+	ReviewSLABusinessDays bool
+	// ReviewSLAAutoReassign hands overdue reviews over to another reviewer.
+	ReviewSLAAutoReassign bool
+	// ReviewRules override ReviewersCount and RequiredApprovals for pull requests
+	// with matching labels or priority.
+	ReviewRules []ReviewRule
+}
+
+// ReviewRule sets review requirements for pull requests that carry Label and/or
+// have Priority. Empty fields match any pull request; at least one must be set.
+type ReviewRule struct {
+	Label             string
+	Priority          PullRequestPriority
+	ReviewersCount    int
+	RequiredApprovals int
+}
+
+// Matches reports whether the rule applies to a pull request with the given
+// labels and priority.
+func (r ReviewRule) Matches(labels []string, priority PullRequestPriority) bool {
+	if r.Priority != "" && r.Priority != priority {
+		return false
+	}
+	if r.Label == "" {
+		return true
+	}
+	for _, l := range labels {
+		if l == r.Label {
+			return true
+		}
+	}
+	return false
+}
+
+// ReviewDueAt returns when a review assigned at the given moment is due under the
+// team's SLA, or nil if the team has no SLA.
+func (s TeamSettings) ReviewDueAt(assignedAt time.Time) *time.Time {
+	if s.ReviewSLAHours <= 0 {
+		return nil
+	}
+	sla := time.Duration(s.ReviewSLAHours) * time.Hour
+	due := assignedAt.UTC().Add(sla)
+	if s.ReviewSLABusinessDays {
+		due = addBusinessTime(assignedAt.UTC(), sla)
+	}
+	return &due
+}
+
+// addBusinessTime adds d to start counting only Monday to Friday. A start on a
+// weekend counts from the following Monday midnight.
+func addBusinessTime(start time.Time, d time.Duration) time.Time {
+	t := start
+	for isWeekend(t) {
+		t = nextMidnight(t)
+	}
+	for {
+		dayEnd := nextMidnight(t)
+		if left := dayEnd.Sub(t); d <= left {
+			return t.Add(d)
+		}
+		d -= dayEnd.Sub(t)
+		t = dayEnd
+		for isWeekend(t) {
+			t = nextMidnight(t)
+		}
+	}
+}
+
+func isWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
+
+func nextMidnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+}
+
+// DefaultTeamSettings returns settings applied to teams that never configured them.
+func DefaultTeamSettings() TeamSettings {
+	return TeamSettings{ReviewersCount: DefaultReviewersCount}
+}
+
+// TeamSettingsUpdate lists changes to team settings. Nil fields are left unchanged.
+type TeamSettingsUpdate struct {
+	ReviewersCount    *int
+	MinReviewers      *int
+	Strategy          *ReviewerStrategy
+	RequiredApprovals *int
+	// FallbackTeams, when set, replace all fallback teams of the team.
+	FallbackTeams         *[]string
+	ReviewSLAHours        *int
+	ReviewSLABusinessDays *bool
+	ReviewSLAAutoReassign *bool
+	// ReviewRules, when set, replace all review rules of the team.
+	ReviewRules *[]ReviewRule
+}
+
+// Apply returns s with the fields set in u replaced.
+func (u TeamSettingsUpdate) Apply(s TeamSettings) TeamSettings {
+	if u.ReviewersCount != nil {
+		s.ReviewersCount = *u.ReviewersCount
+	}
+	if u.MinReviewers != nil {
+		s.MinReviewers = *u.MinReviewers
+	}
+	if u.Strategy != nil {
+		s.Strategy = *u.Strategy
+	}
+	if u.RequiredApprovals != nil {
+		s.RequiredApprovals = *u.RequiredApprovals
+	}
+	if u.FallbackTeams != nil {
+		s.FallbackTeams = *u.FallbackTeams
+	}
+	if u.ReviewSLAHours != nil {
+		s.ReviewSLAHours = *u.ReviewSLAHours
+	}
+	if u.ReviewSLABusinessDays != nil {
+		s.ReviewSLABusinessDays = *u.ReviewSLABusinessDays
+	}
+	if u.ReviewSLAAutoReassign != nil {
+		s.ReviewSLAAutoReassign = *u.ReviewSLAAutoReassign
+	}
+	if u.ReviewRules != nil {
+		s.ReviewRules = *u.ReviewRules
+	}
+	return s
+}
+
 // Team represents a logical group of users that can review pull requests together.
 type Team struct {
-	Name    string
-	Members []User
+	Name     string
+	Members  []User
+	Settings TeamSettings
+}
+
+// ReviewersTarget returns the desired number of reviewers for pull requests of the team,
+// falling back to DefaultReviewersCount when it is not configured.
+func (t *Team) ReviewersTarget() int {
+	if t.Settings.ReviewersCount <= 0 {
+		return DefaultReviewersCount
+	}
+	return t.Settings.ReviewersCount
+}
+
+// ReviewRequirements returns how many reviewers a pull request with the given
+// labels and priority needs and how many of them must approve it.
+func (t *Team) ReviewRequirements(labels []string, priority PullRequestPriority) (reviewers, approvals int) {
+	return t.Settings.ReviewRequirements(t.ReviewersTarget(), t.Settings.RequiredApprovals, labels, priority)
+}
+
+// ReviewRequirements applies the review rules to a pull request with the given
+// labels and priority. Without matching rules the defaults are returned; otherwise
+// the strictest matching rule wins for each value, even if it is below the default.
+func (s TeamSettings) ReviewRequirements(
+	defReviewers, defApprovals int,
+	labels []string,
+	priority PullRequestPriority,
+) (reviewers, approvals int) {
+	matched := false
+	for _, rule := range s.ReviewRules {
+		if !rule.Matches(labels, priority) {
+			continue
+		}
+		if !matched {
+			reviewers, approvals = rule.ReviewersCount, rule.RequiredApprovals
+			matched = true
+			continue
+		}
+		reviewers = max(reviewers, rule.ReviewersCount)
+		approvals = max(approvals, rule.RequiredApprovals)
+	}
+	if !matched {
+		return defReviewers, defApprovals
+	}
+	return reviewers, approvals
 }
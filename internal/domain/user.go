@@ -8,4 +8,33 @@ type User struct {
 	ID       UserID
 	Username string
 	IsActive bool
+	// MaxOpenReviews limits how many OPEN pull requests the user reviews at once.
+	// Nil means no limit.
+	MaxOpenReviews *int
+	// Absence is the absence window active right now, if any. It is loaded
+	// together with team members and is nil when the user is not away.
+	Absence *Absence
+	// Delegation is the delegation of the user's reviews in effect right now, if
+	// any. It is loaded together with team members.
+	Delegation *Delegation
+}
+
+// IsAvailable reports whether the user can be picked as a reviewer:
+// they are active, not away on an absence and have not delegated their reviews.
+func (u *User) IsAvailable() bool {
+	return u.IsActive && u.Absence == nil && u.Delegation == nil
+}
+
+// AtCapacity reports whether a user with the given number of open reviews
+// cannot take another one.
+func (u *User) AtCapacity(openReviews int) bool {
+	return u.MaxOpenReviews != nil && openReviews >= *u.MaxOpenReviews
+}
+
+// ReviewerReassignment records a reviewer replaced on a pull request.
+// An empty NewReviewerID means the review slot was dropped because no candidate was available.
+type ReviewerReassignment struct {
+	PullRequestID PullRequestID
+	OldReviewerID UserID
+	NewReviewerID UserID
 }
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+)
+
+type absenceRepositoryPG struct {
+	db conn
+}
+
+func NewAbsenceRepository(db *DB) *absenceRepositoryPG {
+	return &absenceRepositoryPG{db: db.Pool}
+}
+
+// currentAbsenceJoin attaches the absence active right now to every row of users u.
+// It is used by queries that load team members.
+const currentAbsenceJoin = `
+        LEFT JOIN LATERAL (
+            SELECT ab.id, ab.starts_at, ab.ends_at
+            FROM absences ab
+            WHERE ab.user_id = u.user_id
+              AND ab.cancelled_at IS NULL
+              AND ab.starts_at <= now()
+              AND ab.ends_at > now()
+            ORDER BY ab.starts_at DESC
+            LIMIT 1
+        ) a ON TRUE`
+
+// currentAbsenceColumns lists the columns selected from currentAbsenceJoin.
+const currentAbsenceColumns = `a.id, a.starts_at, a.ends_at`
+
+// currentAbsenceRow holds nullable columns of currentAbsenceJoin.
+type currentAbsenceRow struct {
+	id       *int64
+	startsAt *time.Time
+	endsAt   *time.Time
+}
+
+// toDomain returns the absence of the given user, or nil if there is none.
+func (r currentAbsenceRow) toDomain(userID domain.UserID) *domain.Absence {
+	if r.id == nil {
+		return nil
+	}
+	return &domain.Absence{
+		ID:       domain.AbsenceID(*r.id),
+		UserID:   userID,
+		StartsAt: *r.startsAt,
+		EndsAt:   *r.endsAt,
+	}
+}
+
+// Create stores a new absence and fills its ID and CreatedAt. A delegate, if set,
+// is stored as a delegation over the same window that is cancelled with the absence.
+// If the user or the delegate does not exist, domain.ErrNotFound is returned.
+func (r *absenceRepositoryPG) Create(ctx context.Context, a *domain.Absence) error {
+	var delegateID *string
+	if a.DelegateID != "" {
+		d := string(a.DelegateID)
+		delegateID = &d
+	}
+
+	err := r.db.QueryRow(ctx, `
+        WITH a AS (
+            INSERT INTO absences (user_id, starts_at, ends_at)
+            VALUES ($1, $2, $3)
+            RETURNING id, created_at
+        ), d AS (
+            INSERT INTO delegations (from_user_id, to_user_id, starts_at, ends_at, absence_id, created_at)
+            SELECT $1, $4, $2, $3, a.id, a.created_at
+            FROM a
+            WHERE $4::text IS NOT NULL
+        )
+        SELECT id, created_at FROM a
+    `, string(a.UserID), a.StartsAt, a.EndsAt, delegateID).Scan(&a.ID, &a.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			return fmt.Errorf("%w: user or delegate does not exist", domain.ErrNotFound)
+		}
+		return fmt.Errorf("insert absence: %w", err)
+	}
+
+	return nil
+}
+
+// ListByUser returns all absences of the user, including cancelled ones,
+// ordered by start time.
+func (r *absenceRepositoryPG) ListByUser(ctx context.Context, userID domain.UserID) ([]domain.Absence, error) {
+	rows, err := r.db.Query(ctx, `
+        SELECT ab.id, ab.user_id, ab.starts_at, ab.ends_at, COALESCE(de.to_user_id, ''), ab.created_at, ab.cancelled_at
+        FROM absences ab
+        LEFT JOIN delegations de ON de.absence_id = ab.id
+        WHERE ab.user_id = $1
+        ORDER BY ab.starts_at, ab.id
+    `, string(userID))
+	if err != nil {
+		return nil, fmt.Errorf("query absences of %s: %w", userID, err)
+	}
+	defer rows.Close()
+
+	res := make([]domain.Absence, 0)
+	for rows.Next() {
+		a, err := scanAbsence(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows absences: %w", err)
+	}
+
+	return res, nil
+}
+
+// Cancel marks the absence and its delegation cancelled at the given time and
+// returns the absence. Cancelling an already cancelled absence keeps the original
+// cancellation time. If the absence does not exist, domain.ErrNotFound is returned.
+func (r *absenceRepositoryPG) Cancel(ctx context.Context, id domain.AbsenceID, at time.Time) (*domain.Absence, error) {
+	row := r.db.QueryRow(ctx, `
+        WITH a AS (
+            UPDATE absences
+            SET cancelled_at = COALESCE(cancelled_at, $2)
+            WHERE id = $1
+            RETURNING id, user_id, starts_at, ends_at, created_at, cancelled_at
+        ), d AS (
+            UPDATE delegations
+            SET cancelled_at = COALESCE(cancelled_at, $2)
+            WHERE absence_id = $1
+            RETURNING absence_id, to_user_id
+        )
+        SELECT a.id, a.user_id, a.starts_at, a.ends_at, COALESCE(d.to_user_id, ''), a.created_at, a.cancelled_at
+        FROM a
+        LEFT JOIN d ON d.absence_id = a.id
+    `, int64(id), at)
+
+	a, err := scanAbsence(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// scanAbsence reads a full absences row.
+func scanAbsence(row pgx.Row) (*domain.Absence, error) {
+	var a domain.Absence
+	if err := row.Scan(&a.ID, &a.UserID, &a.StartsAt, &a.EndsAt, &a.DelegateID, &a.CreatedAt, &a.CancelledAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan absence: %w", err)
+	}
+	return &a, nil
+}
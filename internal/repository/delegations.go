@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+)
+
+type delegationRepositoryPG struct {
+	db conn
+}
+
+func NewDelegationRepository(db *DB) *delegationRepositoryPG {
+	return &delegationRepositoryPG{db: db.Pool}
+}
+
+// currentDelegationJoin attaches the delegation in effect right now to every row
+// of users u. It is used by queries that load team members.
+const currentDelegationJoin = `
+        LEFT JOIN LATERAL (
+            SELECT de.id, de.to_user_id, de.starts_at, de.ends_at
+            FROM delegations de
+            WHERE de.from_user_id = u.user_id
+              AND de.cancelled_at IS NULL
+              AND de.starts_at <= now()
+              AND de.ends_at > now()
+            ORDER BY de.starts_at DESC
+            LIMIT 1
+        ) dl ON TRUE`
+
+// currentDelegationColumns lists the columns selected from currentDelegationJoin.
+const currentDelegationColumns = `dl.id, dl.to_user_id, dl.starts_at, dl.ends_at`
+
+// currentDelegationRow holds nullable columns of currentDelegationJoin.
+type currentDelegationRow struct {
+	id       *int64
+	toUserID *string
+	startsAt *time.Time
+	endsAt   *time.Time
+}
+
+// toDomain returns the delegation of the given user, or nil if there is none.
+func (r currentDelegationRow) toDomain(userID domain.UserID) *domain.Delegation {
+	if r.id == nil {
+		return nil
+	}
+	return &domain.Delegation{
+		ID:         domain.DelegationID(*r.id),
+		FromUserID: userID,
+		ToUserID:   domain.UserID(*r.toUserID),
+		StartsAt:   *r.startsAt,
+		EndsAt:     *r.endsAt,
+	}
+}
+
+// Create stores a new delegation and fills its ID and CreatedAt.
+// If either user does not exist, domain.ErrNotFound is returned.
+func (r *delegationRepositoryPG) Create(ctx context.Context, d *domain.Delegation) error {
+	err := r.db.QueryRow(ctx, `
+        INSERT INTO delegations (from_user_id, to_user_id, starts_at, ends_at)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at
+    `, string(d.FromUserID), string(d.ToUserID), d.StartsAt, d.EndsAt).Scan(&d.ID, &d.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			return fmt.Errorf("%w: user or delegate does not exist", domain.ErrNotFound)
+		}
+		return fmt.Errorf("insert delegation: %w", err)
+	}
+
+	return nil
+}
+
+// ListByUser returns all delegations made by the user, including cancelled ones
+// and those created together with an absence, ordered by start time.
+func (r *delegationRepositoryPG) ListByUser(ctx context.Context, userID domain.UserID) ([]domain.Delegation, error) {
+	rows, err := r.db.Query(ctx, `
+        SELECT id, from_user_id, to_user_id, starts_at, ends_at, absence_id, created_at, cancelled_at
+        FROM delegations
+        WHERE from_user_id = $1
+        ORDER BY starts_at, id
+    `, string(userID))
+	if err != nil {
+		return nil, fmt.Errorf("query delegations of %s: %w", userID, err)
+	}
+	defer rows.Close()
+
+	res := make([]domain.Delegation, 0)
+	for rows.Next() {
+		d, err := scanDelegation(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows delegations: %w", err)
+	}
+
+	return res, nil
+}
+
+// Cancel marks the delegation cancelled at the given time and returns it.
+// Cancelling an already cancelled delegation keeps the original cancellation time.
+// If the delegation does not exist, domain.ErrNotFound is returned.
+func (r *delegationRepositoryPG) Cancel(ctx context.Context, id domain.DelegationID, at time.Time) (*domain.Delegation, error) {
+	row := r.db.QueryRow(ctx, `
+        UPDATE delegations
+        SET cancelled_at = COALESCE(cancelled_at, $2)
+        WHERE id = $1
+        RETURNING id, from_user_id, to_user_id, starts_at, ends_at, absence_id, created_at, cancelled_at
+    `, int64(id), at)
+
+	d, err := scanDelegation(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// scanDelegation reads a full delegations row.
+func scanDelegation(row pgx.Row) (*domain.Delegation, error) {
+	var d domain.Delegation
+	err := row.Scan(&d.ID, &d.FromUserID, &d.ToUserID, &d.StartsAt, &d.EndsAt, &d.AbsenceID, &d.CreatedAt, &d.CancelledAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan delegation: %w", err)
+	}
+	return &d, nil
+}
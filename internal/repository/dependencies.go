@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+)
+
+// dependencyLockKey serializes changes of pull_request_dependencies, so that two
+// concurrent additions cannot close a cycle that neither of them sees.
+const dependencyLockKey = "pull_request_dependencies"
+
+// AddDependency records that the pull request id depends on dependsOn. Adding an
+// existing dependency does nothing. It returns domain.ErrNotFound if either pull
+// request does not exist, domain.ErrPullRequestAlreadyMerged if id is merged and
+// domain.ErrDependencyCycle if dependsOn already depends on id, directly or transitively.
+func (r *pullRequestRepositoryPG) AddDependency(ctx context.Context, id, dependsOn domain.PullRequestID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, dependencyLockKey); err != nil {
+		return fmt.Errorf("lock dependencies: %w", err)
+	}
+
+	status, err := lockPullRequest(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if status == domain.PRStatusMerged {
+		return domain.ErrPullRequestAlreadyMerged
+	}
+
+	var exists, cycle bool
+	err = tx.QueryRow(ctx, `
+        WITH RECURSIVE upstream(id) AS (
+            SELECT depends_on_id FROM pull_request_dependencies WHERE pull_request_id = $2
+            UNION
+            SELECT d.depends_on_id
+            FROM pull_request_dependencies d
+            JOIN upstream u ON d.pull_request_id = u.id
+        )
+        SELECT
+            EXISTS (SELECT 1 FROM pull_requests WHERE pull_request_id = $2),
+            EXISTS (SELECT 1 FROM upstream WHERE id = $1)
+    `, string(id), string(dependsOn)).Scan(&exists, &cycle)
+	if err != nil {
+		return fmt.Errorf("check dependency %s -> %s: %w", id, dependsOn, err)
+	}
+	if !exists {
+		return fmt.Errorf("%w: pull request %s", domain.ErrNotFound, dependsOn)
+	}
+	if cycle {
+		return fmt.Errorf("%w: %s already depends on %s", domain.ErrDependencyCycle, dependsOn, id)
+	}
+
+	cmd, err := tx.Exec(ctx, `
+        INSERT INTO pull_request_dependencies (pull_request_id, depends_on_id)
+        VALUES ($1, $2)
+        ON CONFLICT DO NOTHING
+    `, string(id), string(dependsOn))
+	if err != nil {
+		return fmt.Errorf("insert dependency %s -> %s: %w", id, dependsOn, err)
+	}
+
+	if cmd.RowsAffected() > 0 {
+		if err := insertEvents(ctx, tx, domain.PullRequestEvent{
+			PullRequestID: id,
+			Type:          domain.PREventDependencyAdded,
+			Details:       "depends on " + string(dependsOn),
+		}); err != nil {
+			return err
+		}
+		if _, err := bumpVersion(ctx, tx, id); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// RemoveDependency deletes the dependency of id on dependsOn. It returns
+// domain.ErrNotFound if there is no such dependency and
+// domain.ErrPullRequestAlreadyMerged if id is merged.
+func (r *pullRequestRepositoryPG) RemoveDependency(ctx context.Context, id, dependsOn domain.PullRequestID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	status, err := lockPullRequest(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if status == domain.PRStatusMerged {
+		return domain.ErrPullRequestAlreadyMerged
+	}
+
+	cmd, err := tx.Exec(ctx, `
+        DELETE FROM pull_request_dependencies
+        WHERE pull_request_id = $1 AND depends_on_id = $2
+    `, string(id), string(dependsOn))
+	if err != nil {
+		return fmt.Errorf("delete dependency %s -> %s: %w", id, dependsOn, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %s does not depend on %s", domain.ErrNotFound, id, dependsOn)
+	}
+
+	if err := insertEvents(ctx, tx, domain.PullRequestEvent{
+		PullRequestID: id,
+		Type:          domain.PREventDependencyRemoved,
+		Details:       "depends on " + string(dependsOn),
+	}); err != nil {
+		return err
+	}
+	if _, err := bumpVersion(ctx, tx, id); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// GetDependencyGraph returns the pull requests id depends on and the ones that
+// depend on it, directly or transitively, including id itself. Nodes are ordered
+// by creation time with pull request ID as a tie-breaker.
+func (r *pullRequestRepositoryPG) GetDependencyGraph(
+	ctx context.Context,
+	id domain.PullRequestID,
+) (*domain.DependencyGraph, error) {
+	rows, err := r.db.Query(ctx, `
+        WITH RECURSIVE
+        upstream(id) AS (
+            SELECT $1::text
+            UNION
+            SELECT d.depends_on_id
+            FROM pull_request_dependencies d
+            JOIN upstream u ON d.pull_request_id = u.id
+        ),
+        downstream(id) AS (
+            SELECT $1::text
+            UNION
+            SELECT d.pull_request_id
+            FROM pull_request_dependencies d
+            JOIN downstream s ON d.depends_on_id = s.id
+        )
+        SELECT p.pull_request_id, p.pull_request_name, p.status
+        FROM pull_requests p
+        WHERE p.pull_request_id IN (SELECT id FROM upstream UNION SELECT id FROM downstream)
+        ORDER BY p.created_at, p.pull_request_id
+    `, string(id))
+	if err != nil {
+		return nil, fmt.Errorf("query dependency graph of %s: %w", id, err)
+	}
+
+	graph := &domain.DependencyGraph{
+		Nodes: make([]domain.DependencyNode, 0),
+		Edges: make([]domain.DependencyEdge, 0),
+	}
+	ids := make([]string, 0)
+	for rows.Next() {
+		var n domain.DependencyNode
+		if err := rows.Scan(&n.ID, &n.Name, &n.Status); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan dependency node: %w", err)
+		}
+		graph.Nodes = append(graph.Nodes, n)
+		ids = append(ids, string(n.ID))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows dependency graph: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	rows, err = r.db.Query(ctx, `
+        SELECT pull_request_id, depends_on_id
+        FROM pull_request_dependencies
+        WHERE pull_request_id = ANY($1)
+          AND depends_on_id = ANY($1)
+        ORDER BY pull_request_id, depends_on_id
+    `, ids)
+	if err != nil {
+		return nil, fmt.Errorf("query dependency edges of %s: %w", id, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e domain.DependencyEdge
+		if err := rows.Scan(&e.PullRequestID, &e.DependsOnID); err != nil {
+			return nil, fmt.Errorf("scan dependency edge: %w", err)
+		}
+		graph.Edges = append(graph.Edges, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows dependency edges: %w", err)
+	}
+	return graph, nil
+}
+
+// checkDependencies returns domain.ErrDependencyNotMerged if any pull request id
+// depends on still blocks it. The dependencies are locked for share until the end
+// of the transaction, so they cannot be reopened while id is being merged.
+func checkDependencies(ctx context.Context, tx pgx.Tx, id domain.PullRequestID) error {
+	rows, err := tx.Query(ctx, `
+        SELECT p.pull_request_id, p.status
+        FROM pull_request_dependencies d
+        JOIN pull_requests p ON p.pull_request_id = d.depends_on_id
+        WHERE d.pull_request_id = $1
+        ORDER BY p.pull_request_id
+        FOR SHARE OF p
+    `, string(id))
+	if err != nil {
+		return fmt.Errorf("query dependencies of %s: %w", id, err)
+	}
+	defer rows.Close()
+
+	var blocking []string
+	for rows.Next() {
+		var parentID string
+		var status domain.PullRequestStatus
+		if err := rows.Scan(&parentID, &status); err != nil {
+			return fmt.Errorf("scan dependency: %w", err)
+		}
+		if status.BlocksDependents() {
+			blocking = append(blocking, parentID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows dependencies: %w", err)
+	}
+
+	if len(blocking) > 0 {
+		return fmt.Errorf("%w: %s waits for %s", domain.ErrDependencyNotMerged, id, strings.Join(blocking, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+)
+
+type eventRepositoryPG struct {
+	db conn
+}
+
+func NewEventRepository(db *DB) *eventRepositoryPG {
+	return &eventRepositoryPG{db: db.Pool}
+}
+
+// ListByPullRequest returns all events of the pull request in the order they happened.
+func (r *eventRepositoryPG) ListByPullRequest(ctx context.Context, id domain.PullRequestID) ([]domain.PullRequestEvent, error) {
+	rows, err := r.db.Query(ctx, `
+        SELECT id, pull_request_id, event_type,
+               COALESCE(actor_id, ''), COALESCE(old_reviewer_id, ''), COALESCE(new_reviewer_id, ''),
+               COALESCE(old_status, ''), COALESCE(new_status, ''), COALESCE(decision, ''),
+               COALESCE(reason, ''), COALESCE(details, ''), created_at
+        FROM pull_request_events
+        WHERE pull_request_id = $1
+        ORDER BY id
+    `, string(id))
+	if err != nil {
+		return nil, fmt.Errorf("query events of %s: %w", id, err)
+	}
+	defer rows.Close()
+
+	events := make([]domain.PullRequestEvent, 0)
+	for rows.Next() {
+		var ev domain.PullRequestEvent
+		if err := rows.Scan(
+			&ev.ID, &ev.PullRequestID, &ev.Type,
+			&ev.ActorID, &ev.OldReviewerID, &ev.NewReviewerID,
+			&ev.OldStatus, &ev.NewStatus, &ev.Decision,
+			&ev.Reason, &ev.Details, &ev.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan event: %w", err)
+		}
+		events = append(events, ev)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows events: %w", err)
+	}
+
+	return events, nil
+}
+
+const insertEventSQL = `
+    INSERT INTO pull_request_events (
+        pull_request_id, event_type, actor_id, old_reviewer_id, new_reviewer_id,
+        old_status, new_status, decision, reason, details
+    )
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+`
+
+// eventArgs returns the arguments of insertEventSQL for ev. The actor is taken from
+// the audit information in ctx; empty fields are stored as NULL.
+func eventArgs(ctx context.Context, ev domain.PullRequestEvent) []any {
+	if ev.ActorID == "" {
+		ev.ActorID = domain.AuditFromContext(ctx).ActorID
+	}
+	return []any{
+		string(ev.PullRequestID),
+		string(ev.Type),
+		nullString(string(ev.ActorID)),
+		nullString(string(ev.OldReviewerID)),
+		nullString(string(ev.NewReviewerID)),
+		nullString(string(ev.OldStatus)),
+		nullString(string(ev.NewStatus)),
+		nullString(string(ev.Decision)),
+		nullString(string(ev.Reason)),
+		nullString(ev.Details),
+	}
+}
+
+// insertEvents appends events to the pull request history inside the transaction.
+func insertEvents(ctx context.Context, tx pgx.Tx, events ...domain.PullRequestEvent) error {
+	for _, ev := range events {
+		if _, err := tx.Exec(ctx, insertEventSQL, eventArgs(ctx, ev)...); err != nil {
+			return fmt.Errorf("insert %s event of %s: %w", ev.Type, ev.PullRequestID, err)
+		}
+	}
+	return nil
+}
+
+// auditReason returns the reason attached to ctx, or def if there is none.
+func auditReason(ctx context.Context, def domain.EventReason) domain.EventReason {
+	if reason := domain.AuditFromContext(ctx).Reason; reason != "" {
+		return reason
+	}
+	return def
+}
+
+// assignedEvents builds REVIEWER_ASSIGNED events for the given reviewers of pr,
+// noting whom delegates stand in for and which fallback team reviewers come from.
+func assignedEvents(pr *domain.PullRequest, reviewerIDs []domain.UserID, reason domain.EventReason) []domain.PullRequestEvent {
+	events := make([]domain.PullRequestEvent, 0, len(reviewerIDs))
+	for _, rid := range reviewerIDs {
+		events = append(events, domain.PullRequestEvent{
+			PullRequestID: pr.ID,
+			Type:          domain.PREventReviewerAssigned,
+			NewReviewerID: rid,
+			Reason:        reason,
+			Details:       reviewerDetails(pr, rid),
+		})
+	}
+	return events
+}
+
+// removedEvents builds REVIEWER_REMOVED events for the given reviewers of the pull request.
+func removedEvents(id domain.PullRequestID, reviewerIDs []domain.UserID, reason domain.EventReason) []domain.PullRequestEvent {
+	events := make([]domain.PullRequestEvent, 0, len(reviewerIDs))
+	for _, rid := range reviewerIDs {
+		events = append(events, domain.PullRequestEvent{
+			PullRequestID: id,
+			Type:          domain.PREventReviewerRemoved,
+			OldReviewerID: rid,
+			Reason:        reason,
+		})
+	}
+	return events
+}
+
+// reviewerDetails describes where an assigned reviewer comes from, or returns
+// an empty string for a regular member of the author's team.
+func reviewerDetails(pr *domain.PullRequest, rid domain.UserID) string {
+	var notes []string
+	if from, ok := pr.DelegatedReviewers[rid]; ok {
+		notes = append(notes, fmt.Sprintf("delegate for %s", from))
+	}
+	if team, ok := pr.FallbackReviewers[rid]; ok {
+		notes = append(notes, fmt.Sprintf("from fallback team %s", team))
+	}
+	return strings.Join(notes, "; ")
+}
+
+// nullString maps an empty string to NULL.
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+)
+
+type idempotencyRepositoryPG struct {
+	db conn
+}
+
+func NewIdempotencyRepository(db *DB) *idempotencyRepositoryPG {
+	return &idempotencyRepositoryPG{db: db.Pool}
+}
+
+// Reserve claims req.Key for a new request and returns nil. If the key is held by
+// a request that has not expired by now, nothing is changed and that request is
+// returned instead. An expired key, or one without a response whose lease ended,
+// is taken over as if it did not exist. If the holder disappears between the two
+// steps, domain.ErrIdempotencyInProgress is returned, so the client retries later.
+func (r *idempotencyRepositoryPG) Reserve(
+	ctx context.Context,
+	req *domain.IdempotentRequest,
+	now time.Time,
+) (*domain.IdempotentRequest, error) {
+	k := req.Key
+	var key string
+	err := r.db.QueryRow(ctx, `
+        INSERT INTO idempotency_keys (
+            method, path, actor_id, idempotency_key, request_hash, created_at, locked_until, expires_at
+        )
+        VALUES ($6, $7, $8, $1, $2, $3, $4, $5)
+        ON CONFLICT (method, path, actor_id, idempotency_key) DO UPDATE
+        SET request_hash = EXCLUDED.request_hash,
+            status_code = NULL,
+            response_headers = NULL,
+            response_body = NULL,
+            created_at = EXCLUDED.created_at,
+            locked_until = EXCLUDED.locked_until,
+            expires_at = EXCLUDED.expires_at
+        WHERE idempotency_keys.expires_at <= $3
+           OR (idempotency_keys.status_code IS NULL AND idempotency_keys.locked_until <= $3)
+        RETURNING idempotency_key
+    `, k.Key, req.RequestHash, now, req.LockedUntil, req.ExpiresAt, k.Method, k.Path, string(k.ActorID)).Scan(&key)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+
+	existing := domain.IdempotentRequest{Key: req.Key}
+	var status *int
+	var header map[string]string
+	var body []byte
+	err = r.db.QueryRow(ctx, `
+        SELECT request_hash, status_code, response_headers, response_body, locked_until, expires_at
+        FROM idempotency_keys
+        WHERE method = $1 AND path = $2 AND actor_id = $3 AND idempotency_key = $4
+    `, k.Method, k.Path, string(k.ActorID), k.Key).Scan(&existing.RequestHash, &status, &header, &body, &existing.LockedUntil, &existing.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrIdempotencyInProgress
+		}
+		return nil, fmt.Errorf("get idempotency key: %w", err)
+	}
+	if status != nil {
+		existing.Response = &domain.IdempotentResponse{
+			StatusCode: *status,
+			Header:     header,
+			Body:       body,
+		}
+	}
+
+	return &existing, nil
+}
+
+// Complete stores the response of the request that holds the key. If a request
+// whose lease ended completes after another one took its key over, the first
+// stored response wins.
+func (r *idempotencyRepositoryPG) Complete(ctx context.Context, key domain.IdempotencyKey, resp domain.IdempotentResponse) error {
+	if _, err := r.db.Exec(ctx, `
+        UPDATE idempotency_keys
+        SET status_code = $5,
+            response_headers = $6,
+            response_body = $7
+        WHERE method = $1 AND path = $2 AND actor_id = $3 AND idempotency_key = $4
+          AND status_code IS NULL
+    `, key.Method, key.Path, string(key.ActorID), key.Key, resp.StatusCode, resp.Header, resp.Body); err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Release frees a key whose request has no response worth replaying, so that
+// a retry is handled again. Completed keys are kept.
+func (r *idempotencyRepositoryPG) Release(ctx context.Context, key domain.IdempotencyKey) error {
+	if _, err := r.db.Exec(ctx, `
+        DELETE FROM idempotency_keys
+        WHERE method = $1 AND path = $2 AND actor_id = $3 AND idempotency_key = $4
+          AND status_code IS NULL
+    `, key.Method, key.Path, string(key.ActorID), key.Key); err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired removes keys that expired by now and returns how many were removed.
+func (r *idempotencyRepositoryPG) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	cmd, err := r.db.Exec(ctx, `
+        DELETE FROM idempotency_keys
+        WHERE expires_at <= $1
+    `, now)
+	if err != nil {
+		return 0, fmt.Errorf("delete expired idempotency keys: %w", err)
+	}
+	return cmd.RowsAffected(), nil
+}
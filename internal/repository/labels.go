@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+)
+
+// querier is implemented by both the connection pool and transactions.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// saveLabels replaces labels of the pull request inside the transaction.
+func saveLabels(ctx context.Context, tx pgx.Tx, id domain.PullRequestID, labels []string) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM pull_request_labels WHERE pull_request_id = $1`, string(id)); err != nil {
+		return fmt.Errorf("delete labels of %s: %w", id, err)
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, `
+        INSERT INTO pull_request_labels (pull_request_id, label)
+        SELECT $1, unnest($2::text[])
+    `, string(id), labels); err != nil {
+		return fmt.Errorf("insert labels of %s: %w", id, err)
+	}
+	return nil
+}
+
+// loadLabels fills labels of the given pull requests with a single query.
+// Labels of each pull request are sorted.
+func (r *pullRequestRepositoryPG) loadLabels(ctx context.Context, prs []*domain.PullRequest) error {
+	if len(prs) == 0 {
+		return nil
+	}
+
+	byID := make(map[domain.PullRequestID]*domain.PullRequest, len(prs))
+	ids := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		pr.Labels = make([]string, 0)
+		byID[pr.ID] = pr
+		ids = append(ids, string(pr.ID))
+	}
+
+	rows, err := r.db.Query(ctx, `
+        SELECT pull_request_id, label
+        FROM pull_request_labels
+        WHERE pull_request_id = ANY($1)
+        ORDER BY pull_request_id, label
+    `, ids)
+	if err != nil {
+		return fmt.Errorf("query labels: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var prID domain.PullRequestID
+		var label string
+		if err := rows.Scan(&prID, &label); err != nil {
+			return fmt.Errorf("scan label: %w", err)
+		}
+		byID[prID].Labels = append(byID[prID].Labels, label)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows labels: %w", err)
+	}
+	return nil
+}
+
+// reviewRules returns the review rules of the team in the order they were configured.
+func reviewRules(ctx context.Context, q querier, teamID int64) ([]domain.ReviewRule, error) {
+	rows, err := q.Query(ctx, `
+        SELECT COALESCE(label, ''), COALESCE(priority, ''), reviewers_count, required_approvals
+        FROM team_review_rules
+        WHERE team_id = $1
+        ORDER BY position
+    `, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("query review rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]domain.ReviewRule, 0)
+	for rows.Next() {
+		var rule domain.ReviewRule
+		if err := rows.Scan(&rule.Label, &rule.Priority, &rule.ReviewersCount, &rule.RequiredApprovals); err != nil {
+			return nil, fmt.Errorf("scan review rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows review rules: %w", err)
+	}
+	return rules, nil
+}
+
+// saveReviewRules replaces the review rules of the team inside the transaction.
+func saveReviewRules(ctx context.Context, tx pgx.Tx, teamID int64, rules []domain.ReviewRule) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM team_review_rules WHERE team_id = $1`, teamID); err != nil {
+		return fmt.Errorf("delete old review rules: %w", err)
+	}
+	for i, rule := range rules {
+		if _, err := tx.Exec(ctx, `
+            INSERT INTO team_review_rules (team_id, position, label, priority, reviewers_count, required_approvals)
+            VALUES ($1, $2, $3, $4, $5, $6)
+        `, teamID, i, nullString(rule.Label), nullString(string(rule.Priority)),
+			rule.ReviewersCount, rule.RequiredApprovals); err != nil {
+			return fmt.Errorf("insert review rule %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// requiredApprovals returns how many approvals the pull request needs under the
+// settings of the author's team, taking its labels and priority into account.
+// The number is capped at the reviewers assigned, so a pull request that got fewer
+// reviewers than required can be merged once all of them approve. Authors without
+// a team need none.
+func requiredApprovals(ctx context.Context, tx pgx.Tx, id domain.PullRequestID) (int, error) {
+	var teamID int64
+	var priority domain.PullRequestPriority
+	var settings teamSettingsRow
+	err := tx.QueryRow(ctx, `
+        SELECT tm.team_id, p.priority, `+teamSettingsColumns+`
+        FROM pull_requests p
+        JOIN team_members tm ON tm.user_id = p.author_id
+        LEFT JOIN team_settings ts ON ts.team_id = tm.team_id
+        WHERE p.pull_request_id = $1
+        LIMIT 1
+    `, string(id)).Scan(append([]any{&teamID, &priority}, settings.dest()...)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("get review settings of %s: %w", id, err)
+	}
+
+	team := domain.Team{Settings: settings.toDomain()}
+	if team.Settings.ReviewRules, err = reviewRules(ctx, tx, teamID); err != nil {
+		return 0, err
+	}
+
+	rows, err := tx.Query(ctx, `SELECT label FROM pull_request_labels WHERE pull_request_id = $1`, string(id))
+	if err != nil {
+		return 0, fmt.Errorf("query labels of %s: %w", id, err)
+	}
+	labels, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, fmt.Errorf("query labels of %s: %w", id, err)
+	}
+
+	var assigned int
+	if err := tx.QueryRow(ctx, `
+        SELECT COUNT(*)
+        FROM pull_request_reviewers
+        WHERE pull_request_id = $1
+    `, string(id)).Scan(&assigned); err != nil {
+		return 0, fmt.Errorf("count reviewers of %s: %w", id, err)
+	}
+
+	_, approvals := team.ReviewRequirements(labels, priority)
+	return min(approvals, assigned), nil
+}
@@ -11,6 +11,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	domain "github.com/juzu400/avito-internship/internal/domain"
+	repository "github.com/juzu400/avito-internship/internal/repository"
 )
 
 // MockUserRepository is a mock of UserRepository interface.
@@ -36,6 +37,21 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 	return m.recorder
 }
 
+// DeactivateUsers mocks base method.
+func (m *MockUserRepository) DeactivateUsers(ctx context.Context, ids []domain.UserID, pick repository.ReplacementPicker, at time.Time) ([]domain.ReviewerReassignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateUsers", ctx, ids, pick, at)
+	ret0, _ := ret[0].([]domain.ReviewerReassignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeactivateUsers indicates an expected call of DeactivateUsers.
+func (mr *MockUserRepositoryMockRecorder) DeactivateUsers(ctx, ids, pick, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateUsers", reflect.TypeOf((*MockUserRepository)(nil).DeactivateUsers), ctx, ids, pick, at)
+}
+
 // GetByID mocks base method.
 func (m *MockUserRepository) GetByID(ctx context.Context, id domain.UserID) (*domain.User, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +81,20 @@ func (mr *MockUserRepositoryMockRecorder) SetIsActive(ctx, id, active interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIsActive", reflect.TypeOf((*MockUserRepository)(nil).SetIsActive), ctx, id, active)
 }
 
+// SetMaxOpenReviews mocks base method.
+func (m *MockUserRepository) SetMaxOpenReviews(ctx context.Context, id domain.UserID, limit *int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMaxOpenReviews", ctx, id, limit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMaxOpenReviews indicates an expected call of SetMaxOpenReviews.
+func (mr *MockUserRepositoryMockRecorder) SetMaxOpenReviews(ctx, id, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMaxOpenReviews", reflect.TypeOf((*MockUserRepository)(nil).SetMaxOpenReviews), ctx, id, limit)
+}
+
 // MockTeamRepository is a mock of TeamRepository interface.
 type MockTeamRepository struct {
 	ctrl     *gomock.Controller
@@ -118,6 +148,21 @@ func (mr *MockTeamRepositoryMockRecorder) GetByName(ctx, name interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByName", reflect.TypeOf((*MockTeamRepository)(nil).GetByName), ctx, name)
 }
 
+// GetSettings mocks base method.
+func (m *MockTeamRepository) GetSettings(ctx context.Context, teamName string) (*domain.TeamSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettings", ctx, teamName)
+	ret0, _ := ret[0].(*domain.TeamSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSettings indicates an expected call of GetSettings.
+func (mr *MockTeamRepositoryMockRecorder) GetSettings(ctx, teamName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettings", reflect.TypeOf((*MockTeamRepository)(nil).GetSettings), ctx, teamName)
+}
+
 // GetTeamsByMemberIDs mocks base method.
 func (m *MockTeamRepository) GetTeamsByMemberIDs(ctx context.Context, userIDs []domain.UserID) (map[domain.UserID]*domain.Team, error) {
 	m.ctrl.T.Helper()
@@ -133,6 +178,21 @@ func (mr *MockTeamRepositoryMockRecorder) GetTeamsByMemberIDs(ctx, userIDs inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamsByMemberIDs", reflect.TypeOf((*MockTeamRepository)(nil).GetTeamsByMemberIDs), ctx, userIDs)
 }
 
+// UpdateSettings mocks base method.
+func (m *MockTeamRepository) UpdateSettings(ctx context.Context, teamName string, update repository.TeamSettingsUpdater) (*domain.TeamSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSettings", ctx, teamName, update)
+	ret0, _ := ret[0].(*domain.TeamSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSettings indicates an expected call of UpdateSettings.
+func (mr *MockTeamRepositoryMockRecorder) UpdateSettings(ctx, teamName, update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSettings", reflect.TypeOf((*MockTeamRepository)(nil).UpdateSettings), ctx, teamName, update)
+}
+
 // UpsertTeam mocks base method.
 func (m *MockTeamRepository) UpsertTeam(ctx context.Context, team *domain.Team) error {
 	m.ctrl.T.Helper()
@@ -170,6 +230,48 @@ func (m *MockPullRequestRepository) EXPECT() *MockPullRequestRepositoryMockRecor
 	return m.recorder
 }
 
+// AddDependency mocks base method.
+func (m *MockPullRequestRepository) AddDependency(ctx context.Context, id, dependsOn domain.PullRequestID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDependency", ctx, id, dependsOn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDependency indicates an expected call of AddDependency.
+func (mr *MockPullRequestRepositoryMockRecorder) AddDependency(ctx, id, dependsOn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDependency", reflect.TypeOf((*MockPullRequestRepository)(nil).AddDependency), ctx, id, dependsOn)
+}
+
+// AddReviewer mocks base method.
+func (m *MockPullRequestRepository) AddReviewer(ctx context.Context, pr *domain.PullRequest, reviewerID domain.UserID, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReviewer", ctx, pr, reviewerID, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddReviewer indicates an expected call of AddReviewer.
+func (mr *MockPullRequestRepositoryMockRecorder) AddReviewer(ctx, pr, reviewerID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReviewer", reflect.TypeOf((*MockPullRequestRepository)(nil).AddReviewer), ctx, pr, reviewerID, at)
+}
+
+// ChangeStatus mocks base method.
+func (m *MockPullRequestRepository) ChangeStatus(ctx context.Context, pr *domain.PullRequest, from domain.PullRequestStatus, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeStatus", ctx, pr, from, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeStatus indicates an expected call of ChangeStatus.
+func (mr *MockPullRequestRepositoryMockRecorder) ChangeStatus(ctx, pr, from, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeStatus", reflect.TypeOf((*MockPullRequestRepository)(nil).ChangeStatus), ctx, pr, from, at)
+}
+
 // Create mocks base method.
 func (m *MockPullRequestRepository) Create(ctx context.Context, pr *domain.PullRequest) error {
 	m.ctrl.T.Helper()
@@ -199,6 +301,51 @@ func (mr *MockPullRequestRepositoryMockRecorder) GetByID(ctx, id interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockPullRequestRepository)(nil).GetByID), ctx, id)
 }
 
+// GetDependencyGraph mocks base method.
+func (m *MockPullRequestRepository) GetDependencyGraph(ctx context.Context, id domain.PullRequestID) (*domain.DependencyGraph, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDependencyGraph", ctx, id)
+	ret0, _ := ret[0].(*domain.DependencyGraph)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDependencyGraph indicates an expected call of GetDependencyGraph.
+func (mr *MockPullRequestRepositoryMockRecorder) GetDependencyGraph(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDependencyGraph", reflect.TypeOf((*MockPullRequestRepository)(nil).GetDependencyGraph), ctx, id)
+}
+
+// GetLastAssignedAt mocks base method.
+func (m *MockPullRequestRepository) GetLastAssignedAt(ctx context.Context, reviewerIDs []domain.UserID) (map[domain.UserID]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastAssignedAt", ctx, reviewerIDs)
+	ret0, _ := ret[0].(map[domain.UserID]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastAssignedAt indicates an expected call of GetLastAssignedAt.
+func (mr *MockPullRequestRepositoryMockRecorder) GetLastAssignedAt(ctx, reviewerIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastAssignedAt", reflect.TypeOf((*MockPullRequestRepository)(nil).GetLastAssignedAt), ctx, reviewerIDs)
+}
+
+// GetOpenReviewCounts mocks base method.
+func (m *MockPullRequestRepository) GetOpenReviewCounts(ctx context.Context, reviewerIDs []domain.UserID) (map[domain.UserID]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenReviewCounts", ctx, reviewerIDs)
+	ret0, _ := ret[0].(map[domain.UserID]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenReviewCounts indicates an expected call of GetOpenReviewCounts.
+func (mr *MockPullRequestRepositoryMockRecorder) GetOpenReviewCounts(ctx, reviewerIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenReviewCounts", reflect.TypeOf((*MockPullRequestRepository)(nil).GetOpenReviewCounts), ctx, reviewerIDs)
+}
+
 // GetPullRequestReviewerStats mocks base method.
 func (m *MockPullRequestRepository) GetPullRequestReviewerStats(ctx context.Context) ([]domain.PullRequestReviewersStat, error) {
 	m.ctrl.T.Helper()
@@ -229,46 +376,453 @@ func (mr *MockPullRequestRepositoryMockRecorder) GetReviewerAssignmentStats(ctx
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewerAssignmentStats", reflect.TypeOf((*MockPullRequestRepository)(nil).GetReviewerAssignmentStats), ctx)
 }
 
+// List mocks base method.
+func (m *MockPullRequestRepository) List(ctx context.Context, q domain.PullRequestListQuery) (*domain.PullRequestPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, q)
+	ret0, _ := ret[0].(*domain.PullRequestPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockPullRequestRepositoryMockRecorder) List(ctx, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPullRequestRepository)(nil).List), ctx, q)
+}
+
 // ListByReviewer mocks base method.
-func (m *MockPullRequestRepository) ListByReviewer(ctx context.Context, reviewerID domain.UserID) ([]*domain.PullRequest, error) {
+func (m *MockPullRequestRepository) ListByReviewer(ctx context.Context, reviewerID domain.UserID, q domain.ReviewListQuery) (*domain.PullRequestPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListByReviewer", ctx, reviewerID)
-	ret0, _ := ret[0].([]*domain.PullRequest)
+	ret := m.ctrl.Call(m, "ListByReviewer", ctx, reviewerID, q)
+	ret0, _ := ret[0].(*domain.PullRequestPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListByReviewer indicates an expected call of ListByReviewer.
-func (mr *MockPullRequestRepositoryMockRecorder) ListByReviewer(ctx, reviewerID interface{}) *gomock.Call {
+func (mr *MockPullRequestRepositoryMockRecorder) ListByReviewer(ctx, reviewerID, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByReviewer", reflect.TypeOf((*MockPullRequestRepository)(nil).ListByReviewer), ctx, reviewerID, q)
+}
+
+// ListStale mocks base method.
+func (m *MockPullRequestRepository) ListStale(ctx context.Context, assignedBefore time.Time, teamName string) ([]domain.StalePullRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStale", ctx, assignedBefore, teamName)
+	ret0, _ := ret[0].([]domain.StalePullRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStale indicates an expected call of ListStale.
+func (mr *MockPullRequestRepositoryMockRecorder) ListStale(ctx, assignedBefore, teamName interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByReviewer", reflect.TypeOf((*MockPullRequestRepository)(nil).ListByReviewer), ctx, reviewerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStale", reflect.TypeOf((*MockPullRequestRepository)(nil).ListStale), ctx, assignedBefore, teamName)
+}
+
+// ListUnderstaffedOpen mocks base method.
+func (m *MockPullRequestRepository) ListUnderstaffedOpen(ctx context.Context) ([]domain.UnderstaffedPullRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnderstaffedOpen", ctx)
+	ret0, _ := ret[0].([]domain.UnderstaffedPullRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnderstaffedOpen indicates an expected call of ListUnderstaffedOpen.
+func (mr *MockPullRequestRepositoryMockRecorder) ListUnderstaffedOpen(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnderstaffedOpen", reflect.TypeOf((*MockPullRequestRepository)(nil).ListUnderstaffedOpen), ctx)
+}
+
+// MarkOverdueReviews mocks base method.
+func (m *MockPullRequestRepository) MarkOverdueReviews(ctx context.Context, now time.Time) ([]domain.OverdueReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOverdueReviews", ctx, now)
+	ret0, _ := ret[0].([]domain.OverdueReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkOverdueReviews indicates an expected call of MarkOverdueReviews.
+func (mr *MockPullRequestRepositoryMockRecorder) MarkOverdueReviews(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOverdueReviews", reflect.TypeOf((*MockPullRequestRepository)(nil).MarkOverdueReviews), ctx, now)
 }
 
 // Merge mocks base method.
-func (m *MockPullRequestRepository) Merge(ctx context.Context, id domain.PullRequestID, mergedAt time.Time) (*domain.PullRequest, error) {
+func (m *MockPullRequestRepository) Merge(ctx context.Context, id domain.PullRequestID, mergedAt time.Time, force bool) (*domain.PullRequest, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Merge", ctx, id, mergedAt)
+	ret := m.ctrl.Call(m, "Merge", ctx, id, mergedAt, force)
 	ret0, _ := ret[0].(*domain.PullRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Merge indicates an expected call of Merge.
-func (mr *MockPullRequestRepositoryMockRecorder) Merge(ctx, id, mergedAt interface{}) *gomock.Call {
+func (mr *MockPullRequestRepositoryMockRecorder) Merge(ctx, id, mergedAt, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockPullRequestRepository)(nil).Merge), ctx, id, mergedAt, force)
+}
+
+// RemoveDependency mocks base method.
+func (m *MockPullRequestRepository) RemoveDependency(ctx context.Context, id, dependsOn domain.PullRequestID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveDependency", ctx, id, dependsOn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveDependency indicates an expected call of RemoveDependency.
+func (mr *MockPullRequestRepositoryMockRecorder) RemoveDependency(ctx, id, dependsOn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveDependency", reflect.TypeOf((*MockPullRequestRepository)(nil).RemoveDependency), ctx, id, dependsOn)
+}
+
+// RemoveReviewer mocks base method.
+func (m *MockPullRequestRepository) RemoveReviewer(ctx context.Context, pr *domain.PullRequest, reviewerID domain.UserID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveReviewer", ctx, pr, reviewerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveReviewer indicates an expected call of RemoveReviewer.
+func (mr *MockPullRequestRepositoryMockRecorder) RemoveReviewer(ctx, pr, reviewerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveReviewer", reflect.TypeOf((*MockPullRequestRepository)(nil).RemoveReviewer), ctx, pr, reviewerID)
+}
+
+// ReplaceReviewer mocks base method.
+func (m *MockPullRequestRepository) ReplaceReviewer(ctx context.Context, pr *domain.PullRequest, oldID, newID domain.UserID, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceReviewer", ctx, pr, oldID, newID, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceReviewer indicates an expected call of ReplaceReviewer.
+func (mr *MockPullRequestRepositoryMockRecorder) ReplaceReviewer(ctx, pr, oldID, newID, at interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockPullRequestRepository)(nil).Merge), ctx, id, mergedAt)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceReviewer", reflect.TypeOf((*MockPullRequestRepository)(nil).ReplaceReviewer), ctx, pr, oldID, newID, at)
 }
 
 // Update mocks base method.
-func (m *MockPullRequestRepository) Update(ctx context.Context, pr *domain.PullRequest) error {
+func (m *MockPullRequestRepository) Update(ctx context.Context, pr *domain.PullRequest, at time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, pr)
+	ret := m.ctrl.Call(m, "Update", ctx, pr, at)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockPullRequestRepositoryMockRecorder) Update(ctx, pr interface{}) *gomock.Call {
+func (mr *MockPullRequestRepositoryMockRecorder) Update(ctx, pr, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPullRequestRepository)(nil).Update), ctx, pr, at)
+}
+
+// UpdateMetadata mocks base method.
+func (m *MockPullRequestRepository) UpdateMetadata(ctx context.Context, id domain.PullRequestID, upd domain.PullRequestUpdate) (*domain.PullRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMetadata", ctx, id, upd)
+	ret0, _ := ret[0].(*domain.PullRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMetadata indicates an expected call of UpdateMetadata.
+func (mr *MockPullRequestRepositoryMockRecorder) UpdateMetadata(ctx, id, upd interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMetadata", reflect.TypeOf((*MockPullRequestRepository)(nil).UpdateMetadata), ctx, id, upd)
+}
+
+// MockAbsenceRepository is a mock of AbsenceRepository interface.
+type MockAbsenceRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAbsenceRepositoryMockRecorder
+}
+
+// MockAbsenceRepositoryMockRecorder is the mock recorder for MockAbsenceRepository.
+type MockAbsenceRepositoryMockRecorder struct {
+	mock *MockAbsenceRepository
+}
+
+// NewMockAbsenceRepository creates a new mock instance.
+func NewMockAbsenceRepository(ctrl *gomock.Controller) *MockAbsenceRepository {
+	mock := &MockAbsenceRepository{ctrl: ctrl}
+	mock.recorder = &MockAbsenceRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAbsenceRepository) EXPECT() *MockAbsenceRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockAbsenceRepository) Cancel(ctx context.Context, id domain.AbsenceID, at time.Time) (*domain.Absence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, id, at)
+	ret0, _ := ret[0].(*domain.Absence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockAbsenceRepositoryMockRecorder) Cancel(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockAbsenceRepository)(nil).Cancel), ctx, id, at)
+}
+
+// Create mocks base method.
+func (m *MockAbsenceRepository) Create(ctx context.Context, a *domain.Absence) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, a)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAbsenceRepositoryMockRecorder) Create(ctx, a interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAbsenceRepository)(nil).Create), ctx, a)
+}
+
+// ListByUser mocks base method.
+func (m *MockAbsenceRepository) ListByUser(ctx context.Context, userID domain.UserID) ([]domain.Absence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID)
+	ret0, _ := ret[0].([]domain.Absence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockAbsenceRepositoryMockRecorder) ListByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockAbsenceRepository)(nil).ListByUser), ctx, userID)
+}
+
+// MockDelegationRepository is a mock of DelegationRepository interface.
+type MockDelegationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDelegationRepositoryMockRecorder
+}
+
+// MockDelegationRepositoryMockRecorder is the mock recorder for MockDelegationRepository.
+type MockDelegationRepositoryMockRecorder struct {
+	mock *MockDelegationRepository
+}
+
+// NewMockDelegationRepository creates a new mock instance.
+func NewMockDelegationRepository(ctrl *gomock.Controller) *MockDelegationRepository {
+	mock := &MockDelegationRepository{ctrl: ctrl}
+	mock.recorder = &MockDelegationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDelegationRepository) EXPECT() *MockDelegationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockDelegationRepository) Cancel(ctx context.Context, id domain.DelegationID, at time.Time) (*domain.Delegation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, id, at)
+	ret0, _ := ret[0].(*domain.Delegation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockDelegationRepositoryMockRecorder) Cancel(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockDelegationRepository)(nil).Cancel), ctx, id, at)
+}
+
+// Create mocks base method.
+func (m *MockDelegationRepository) Create(ctx context.Context, d *domain.Delegation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDelegationRepositoryMockRecorder) Create(ctx, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDelegationRepository)(nil).Create), ctx, d)
+}
+
+// ListByUser mocks base method.
+func (m *MockDelegationRepository) ListByUser(ctx context.Context, userID domain.UserID) ([]domain.Delegation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID)
+	ret0, _ := ret[0].([]domain.Delegation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockDelegationRepositoryMockRecorder) ListByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockDelegationRepository)(nil).ListByUser), ctx, userID)
+}
+
+// MockReviewRepository is a mock of ReviewRepository interface.
+type MockReviewRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReviewRepositoryMockRecorder
+}
+
+// MockReviewRepositoryMockRecorder is the mock recorder for MockReviewRepository.
+type MockReviewRepositoryMockRecorder struct {
+	mock *MockReviewRepository
+}
+
+// NewMockReviewRepository creates a new mock instance.
+func NewMockReviewRepository(ctrl *gomock.Controller) *MockReviewRepository {
+	mock := &MockReviewRepository{ctrl: ctrl}
+	mock.recorder = &MockReviewRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReviewRepository) EXPECT() *MockReviewRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Save mocks base method.
+func (m *MockReviewRepository) Save(ctx context.Context, review *domain.Review) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, review)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockReviewRepositoryMockRecorder) Save(ctx, review interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockReviewRepository)(nil).Save), ctx, review)
+}
+
+// MockEventRepository is a mock of EventRepository interface.
+type MockEventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventRepositoryMockRecorder
+}
+
+// MockEventRepositoryMockRecorder is the mock recorder for MockEventRepository.
+type MockEventRepositoryMockRecorder struct {
+	mock *MockEventRepository
+}
+
+// NewMockEventRepository creates a new mock instance.
+func NewMockEventRepository(ctrl *gomock.Controller) *MockEventRepository {
+	mock := &MockEventRepository{ctrl: ctrl}
+	mock.recorder = &MockEventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventRepository) EXPECT() *MockEventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListByPullRequest mocks base method.
+func (m *MockEventRepository) ListByPullRequest(ctx context.Context, id domain.PullRequestID) ([]domain.PullRequestEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByPullRequest", ctx, id)
+	ret0, _ := ret[0].([]domain.PullRequestEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByPullRequest indicates an expected call of ListByPullRequest.
+func (mr *MockEventRepositoryMockRecorder) ListByPullRequest(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByPullRequest", reflect.TypeOf((*MockEventRepository)(nil).ListByPullRequest), ctx, id)
+}
+
+// MockIdempotencyRepository is a mock of IdempotencyRepository interface.
+type MockIdempotencyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyRepositoryMockRecorder
+}
+
+// MockIdempotencyRepositoryMockRecorder is the mock recorder for MockIdempotencyRepository.
+type MockIdempotencyRepositoryMockRecorder struct {
+	mock *MockIdempotencyRepository
+}
+
+// NewMockIdempotencyRepository creates a new mock instance.
+func NewMockIdempotencyRepository(ctrl *gomock.Controller) *MockIdempotencyRepository {
+	mock := &MockIdempotencyRepository{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyRepository) EXPECT() *MockIdempotencyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Complete mocks base method.
+func (m *MockIdempotencyRepository) Complete(ctx context.Context, key domain.IdempotencyKey, resp domain.IdempotentResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, key, resp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockIdempotencyRepositoryMockRecorder) Complete(ctx, key, resp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockIdempotencyRepository)(nil).Complete), ctx, key, resp)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockIdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockIdempotencyRepositoryMockRecorder) DeleteExpired(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockIdempotencyRepository)(nil).DeleteExpired), ctx, now)
+}
+
+// Release mocks base method.
+func (m *MockIdempotencyRepository) Release(ctx context.Context, key domain.IdempotencyKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockIdempotencyRepositoryMockRecorder) Release(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockIdempotencyRepository)(nil).Release), ctx, key)
+}
+
+// Reserve mocks base method.
+func (m *MockIdempotencyRepository) Reserve(ctx context.Context, req *domain.IdempotentRequest, now time.Time) (*domain.IdempotentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, req, now)
+	ret0, _ := ret[0].(*domain.IdempotentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockIdempotencyRepositoryMockRecorder) Reserve(ctx, req, now interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPullRequestRepository)(nil).Update), ctx, pr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockIdempotencyRepository)(nil).Reserve), ctx, req, now)
 }
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -12,6 +14,16 @@ type DB struct {
 	Pool *pgxpool.Pool
 }
 
+// conn is implemented by both the connection pool and transactions, so that
+// repositories can also run inside a transaction started elsewhere. Begin on a
+// transaction starts a nested one backed by a savepoint.
+type conn interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
 // NewPostgresDB creates and initializes a PostgreSQL connection pool using the given DSN.
 // It pings the database to ensure the connection is alive before returning.
 func NewPostgresDB(ctx context.Context, dsn string) (*DB, error) {
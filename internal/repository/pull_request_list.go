@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+)
+
+// likeEscaper escapes LIKE wildcards so a substring filter matches literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// queryBuilder collects WHERE conditions together with their positional arguments.
+type queryBuilder struct {
+	conds []string
+	args  []any
+}
+
+// arg registers a query argument and returns its placeholder.
+func (b *queryBuilder) arg(v any) string {
+	b.args = append(b.args, v)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+// where adds a condition; all conditions are joined with AND.
+func (b *queryBuilder) where(cond string) {
+	b.conds = append(b.conds, cond)
+}
+
+// whereClause renders the collected conditions, or an empty string if there are none.
+func (b *queryBuilder) whereClause() string {
+	if len(b.conds) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(b.conds, "\n          AND ")
+}
+
+// List returns one page of pull requests matching the query, with their reviewers.
+// Filters only use columns covered by indexes on pull_requests and pull_request_reviewers;
+// paging is keyset-based on (sort key, pull_request_id), so deep pages cost the same as the first.
+func (r *pullRequestRepositoryPG) List(ctx context.Context, q domain.PullRequestListQuery) (*domain.PullRequestPage, error) {
+	var b queryBuilder
+	f := q.Filter
+
+	if len(f.Statuses) > 0 {
+		b.where("p.status = ANY(" + b.arg(statusStrings(f.Statuses)) + ")")
+	}
+	if f.AuthorID != "" {
+		b.where("p.author_id = " + b.arg(string(f.AuthorID)))
+	}
+	if f.ReviewerID != "" {
+		b.where(`p.pull_request_id IN (
+            SELECT prr.pull_request_id FROM pull_request_reviewers prr
+            WHERE prr.reviewer_id = ` + b.arg(string(f.ReviewerID)) + `)`)
+	}
+	if f.TeamName != "" {
+		b.where(`p.author_id IN (
+            SELECT tm.user_id FROM team_members tm
+            JOIN teams t ON t.id = tm.team_id
+            WHERE t.team_name = ` + b.arg(f.TeamName) + `)`)
+	}
+	if f.CreatedFrom != nil {
+		b.where("p.created_at >= " + b.arg(*f.CreatedFrom))
+	}
+	if f.CreatedTo != nil {
+		b.where("p.created_at < " + b.arg(*f.CreatedTo))
+	}
+	if f.MergedFrom != nil {
+		b.where("p.merged_at >= " + b.arg(*f.MergedFrom))
+	}
+	if f.MergedTo != nil {
+		b.where("p.merged_at < " + b.arg(*f.MergedTo))
+	}
+	if f.NameContains != "" {
+		b.where("p.pull_request_name ILIKE " + b.arg("%"+likeEscaper.Replace(f.NameContains)+"%"))
+	}
+	if len(f.Priorities) > 0 {
+		b.where("p.priority = ANY(" + b.arg(priorityStrings(f.Priorities)) + ")")
+	}
+	if len(f.Labels) > 0 {
+		labels := b.arg(f.Labels)
+		b.where(`p.pull_request_id IN (
+            SELECT prl.pull_request_id FROM pull_request_labels prl
+            WHERE prl.label = ANY(` + labels + `)
+            GROUP BY prl.pull_request_id
+            HAVING COUNT(*) = cardinality(` + labels + `::text[]))`)
+	}
+
+	sortColumn := "p.created_at"
+	if q.SortBy == domain.PRSortName {
+		sortColumn = "p.pull_request_name"
+	}
+	direction, cmp := "DESC", "<"
+	if q.Order == domain.SortAsc {
+		direction, cmp = "ASC", ">"
+	}
+
+	if c := q.After; c != nil {
+		var key any = c.CreatedAt
+		if q.SortBy == domain.PRSortName {
+			key = c.Name
+		}
+		b.where(fmt.Sprintf("(%s, p.pull_request_id) %s (%s, %s)", sortColumn, cmp, b.arg(key), b.arg(string(c.ID))))
+	}
+
+	query := fmt.Sprintf(`
+        SELECT p.pull_request_id, p.pull_request_name, p.author_id, p.status, p.priority, p.created_at, p.merged_at, p.closed_at
+        FROM pull_requests p
+        %s
+        ORDER BY %s %s, p.pull_request_id %s
+        LIMIT %s
+    `, b.whereClause(), sortColumn, direction, direction, b.arg(q.Limit+1))
+
+	rows, err := r.db.Query(ctx, query, b.args...)
+	if err != nil {
+		return nil, fmt.Errorf("query pull_requests list: %w", err)
+	}
+	defer rows.Close()
+
+	page := &domain.PullRequestPage{Items: make([]*domain.PullRequest, 0, q.Limit)}
+	for rows.Next() {
+		var pr domain.PullRequest
+		var status string
+		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &status, &pr.Priority, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt); err != nil {
+			return nil, fmt.Errorf("scan pull_request: %w", err)
+		}
+		pr.Status = domain.PullRequestStatus(status)
+		page.Items = append(page.Items, &pr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows pull_requests list: %w", err)
+	}
+
+	if len(page.Items) > q.Limit {
+		page.Items = page.Items[:q.Limit]
+		page.NextCursor = domain.NewPullRequestCursor(q.SortBy, q.Order, page.Items[q.Limit-1])
+	}
+
+	if err := r.loadReviewers(ctx, page.Items); err != nil {
+		return nil, err
+	}
+	if err := r.loadLabels(ctx, page.Items); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// statusStrings converts statuses to plain strings so they can be passed as a text[] parameter.
+func statusStrings(statuses []domain.PullRequestStatus) []string {
+	res := make([]string, 0, len(statuses))
+	for _, st := range statuses {
+		res = append(res, string(st))
+	}
+	return res
+}
+
+// priorityStrings converts priorities to plain strings so they can be passed as a text[] parameter.
+func priorityStrings(priorities []domain.PullRequestPriority) []string {
+	res := make([]string, 0, len(priorities))
+	for _, p := range priorities {
+		res = append(res, string(p))
+	}
+	return res
+}
+
+// loadReviewers fills reviewer assignments and their review deadlines of the given
+// pull requests with a single query.
+// Reviewers of each pull request are ordered by ID.
+func (r *pullRequestRepositoryPG) loadReviewers(ctx context.Context, prs []*domain.PullRequest) error {
+	if len(prs) == 0 {
+		return nil
+	}
+
+	byID := make(map[domain.PullRequestID]*domain.PullRequest, len(prs))
+	ids := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		pr.AssignedReviewers = make([]domain.UserID, 0)
+		byID[pr.ID] = pr
+		ids = append(ids, string(pr.ID))
+	}
+
+	rows, err := r.db.Query(ctx, `
+        SELECT pull_request_id, reviewer_id, fallback_team, delegated_from, review_due_at, overdue_at
+        FROM pull_request_reviewers
+        WHERE pull_request_id = ANY($1)
+        ORDER BY pull_request_id, reviewer_id
+    `, ids)
+	if err != nil {
+		return fmt.Errorf("query reviewers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var prID domain.PullRequestID
+		var rid domain.UserID
+		var fallbackTeam *string
+		var delegatedFrom *string
+		var deadline reviewAssignment
+		if err := rows.Scan(&prID, &rid, &fallbackTeam, &delegatedFrom, &deadline.dueAt, &deadline.overdueAt); err != nil {
+			return fmt.Errorf("scan reviewer: %w", err)
+		}
+		pr := byID[prID]
+		pr.AssignedReviewers = append(pr.AssignedReviewers, rid)
+		if fallbackTeam != nil {
+			if pr.FallbackReviewers == nil {
+				pr.FallbackReviewers = make(map[domain.UserID]string)
+			}
+			pr.FallbackReviewers[rid] = *fallbackTeam
+		}
+		if delegatedFrom != nil {
+			if pr.DelegatedReviewers == nil {
+				pr.DelegatedReviewers = make(map[domain.UserID]domain.UserID)
+			}
+			pr.DelegatedReviewers[rid] = domain.UserID(*delegatedFrom)
+		}
+		if deadline.dueAt != nil {
+			if pr.ReviewDueAt == nil {
+				pr.ReviewDueAt = make(map[domain.UserID]time.Time)
+			}
+			pr.ReviewDueAt[rid] = *deadline.dueAt
+		}
+		if deadline.overdueAt != nil {
+			pr.OverdueReviewers = append(pr.OverdueReviewers, rid)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows reviewers: %w", err)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/jackc/pgerrcode"
@@ -14,17 +15,17 @@ import (
 )
 
 type pullRequestRepositoryPG struct {
-	db *DB
+	db conn
 }
 
 func NewPullRequestRepository(db *DB) *pullRequestRepositoryPG {
-	return &pullRequestRepositoryPG{db: db}
+	return &pullRequestRepositoryPG{db: db.Pool}
 }
 
 // Create inserts a new pull request and its reviewers into the database.
 // If a pull request with the same ID already exists, ErrPullRequestAlreadyExists is returned.
 func (r *pullRequestRepositoryPG) Create(ctx context.Context, pr *domain.PullRequest) error {
-	tx, err := r.db.Pool.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
 	}
@@ -37,15 +38,17 @@ func (r *pullRequestRepositoryPG) Create(ctx context.Context, pr *domain.PullReq
 	}
 
 	_, err = tx.Exec(ctx, `
-        INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at, merged_at)
-        VALUES ($1, $2, $3, $4, $5, $6)
+        INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, priority, created_at, merged_at, closed_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
     `,
 		string(pr.ID),
 		pr.Name,
 		string(pr.AuthorID),
 		string(pr.Status),
+		string(pullRequestPriority(pr)),
 		pr.CreatedAt,
 		pr.MergedAt,
+		pr.ClosedAt,
 	)
 	if err != nil {
 		var pgErr *pgconn.PgError
@@ -55,21 +58,48 @@ func (r *pullRequestRepositoryPG) Create(ctx context.Context, pr *domain.PullReq
 		return fmt.Errorf("insert pull_request: %w", err)
 	}
 
-	if err := saveReviewers(ctx, tx, pr.ID, pr.AssignedReviewers); err != nil {
+	if err := saveLabels(ctx, tx, pr.ID, pr.Labels); err != nil {
+		return err
+	}
+
+	if err := saveReviewers(ctx, tx, pr, nil, pr.CreatedAt); err != nil {
 		return fmt.Errorf("save reviewers: %w", err)
 	}
 
+	created := domain.PullRequestEvent{
+		PullRequestID: pr.ID,
+		Type:          domain.PREventCreated,
+		NewStatus:     pr.Status,
+	}
+	if err := insertEvents(ctx, tx, created); err != nil {
+		return err
+	}
+	if err := insertEvents(ctx, tx, assignedEvents(pr, pr.AssignedReviewers, auditReason(ctx, domain.ReasonAutomatic))...); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("commit tx: %w", err)
 	}
+	pr.Version = 1
 
 	return nil
 }
 
-// Update updates pull request fields and completely replaces its reviewers.
-// If the pull request does not exist, ErrNotFound is returned.
-func (r *pullRequestRepositoryPG) Update(ctx context.Context, pr *domain.PullRequest) error {
-	tx, err := r.db.Pool.BeginTx(ctx, pgx.TxOptions{})
+// Update updates pull request fields and completely replaces its reviewers; new
+// reviewers are considered assigned at the given moment and dropped ones lose
+// their review decisions. Status changes and reviewers that were dropped or added
+// are recorded as events.
+// If the pull request does not exist, ErrNotFound is returned. If pr has a version
+// and the pull request was changed since, ErrVersionConflict is returned; otherwise
+// pr gets the new version.
+func (r *pullRequestRepositoryPG) Update(ctx context.Context, pr *domain.PullRequest, at time.Time) error {
+	ctx, err := expectVersion(ctx, pr)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
 	}
@@ -77,41 +107,75 @@ func (r *pullRequestRepositoryPG) Update(ctx context.Context, pr *domain.PullReq
 		_ = tx.Rollback(ctx)
 	}()
 
-	cmd, err := tx.Exec(ctx, `
+	oldStatus, err := lockPullRequest(ctx, tx, pr.ID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `
         UPDATE pull_requests
         SET pull_request_name = $2,
             author_id = $3,
             status = $4,
-            merged_at = $5
+            priority = $5,
+            merged_at = $6,
+            closed_at = $7
         WHERE pull_request_id = $1
     `,
 		string(pr.ID),
 		pr.Name,
 		string(pr.AuthorID),
 		string(pr.Status),
+		string(pullRequestPriority(pr)),
 		pr.MergedAt,
-	)
-	if err != nil {
+		pr.ClosedAt,
+	); err != nil {
 		return fmt.Errorf("update pull_request: %w", err)
 	}
-	if cmd.RowsAffected() == 0 {
-		return domain.ErrNotFound
+
+	if err := saveLabels(ctx, tx, pr.ID, pr.Labels); err != nil {
+		return err
 	}
 
-	if _, err := tx.Exec(ctx, `
-        DELETE FROM pull_request_reviewers
-        WHERE pull_request_id = $1
-    `, string(pr.ID)); err != nil {
-		return fmt.Errorf("delete reviewers: %w", err)
+	oldReviewers, assignments, err := deleteReviewers(ctx, tx, pr.ID)
+	if err != nil {
+		return err
 	}
 
-	if err := saveReviewers(ctx, tx, pr.ID, pr.AssignedReviewers); err != nil {
+	if err := saveReviewers(ctx, tx, pr, assignments, at); err != nil {
 		return fmt.Errorf("save reviewers: %w", err)
 	}
+	if err := deleteReviews(ctx, tx, pr.ID, subtractUserIDs(oldReviewers, pr.AssignedReviewers)); err != nil {
+		return err
+	}
+
+	if oldStatus != pr.Status {
+		if err := insertEvents(ctx, tx, domain.PullRequestEvent{
+			PullRequestID: pr.ID,
+			Type:          domain.PREventStatusChanged,
+			OldStatus:     oldStatus,
+			NewStatus:     pr.Status,
+		}); err != nil {
+			return err
+		}
+	}
+	reason := auditReason(ctx, domain.ReasonManual)
+	if err := insertEvents(ctx, tx, removedEvents(pr.ID, subtractUserIDs(oldReviewers, pr.AssignedReviewers), reason)...); err != nil {
+		return err
+	}
+	if err := insertEvents(ctx, tx, assignedEvents(pr, subtractUserIDs(pr.AssignedReviewers, oldReviewers), reason)...); err != nil {
+		return err
+	}
+
+	version, err := bumpVersion(ctx, tx, pr.ID)
+	if err != nil {
+		return err
+	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("commit tx: %w", err)
 	}
+	pr.Version = version
 
 	return nil
 }
@@ -119,8 +183,8 @@ func (r *pullRequestRepositoryPG) Update(ctx context.Context, pr *domain.PullReq
 // GetByID retrieves a pull request with its reviewers by ID.
 // If the pull request does not exist, ErrNotFound is returned.
 func (r *pullRequestRepositoryPG) GetByID(ctx context.Context, id domain.PullRequestID) (*domain.PullRequest, error) {
-	row := r.db.Pool.QueryRow(ctx, `
-        SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at
+	row := r.db.QueryRow(ctx, `
+        SELECT pull_request_id, pull_request_name, author_id, status, priority, version, created_at, merged_at, closed_at
         FROM pull_requests
         WHERE pull_request_id = $1
     `, string(id))
@@ -128,7 +192,7 @@ func (r *pullRequestRepositoryPG) GetByID(ctx context.Context, id domain.PullReq
 	var pr domain.PullRequest
 	var status string
 	var mergedAt *time.Time
-	if err := row.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &status, &pr.CreatedAt, &mergedAt); err != nil {
+	if err := row.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &status, &pr.Priority, &pr.Version, &pr.CreatedAt, &mergedAt, &pr.ClosedAt); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrNotFound
 		}
@@ -137,131 +201,782 @@ func (r *pullRequestRepositoryPG) GetByID(ctx context.Context, id domain.PullReq
 	pr.Status = domain.PullRequestStatus(status)
 	pr.MergedAt = mergedAt
 
-	rows, err := r.db.Pool.Query(ctx, `
-        SELECT reviewer_id
-        FROM pull_request_reviewers
-        WHERE pull_request_id = $1
-        ORDER BY reviewer_id
-    `, string(id))
-	if err != nil {
-		return nil, fmt.Errorf("query reviewers: %w", err)
+	if err := r.loadReviewers(ctx, []*domain.PullRequest{&pr}); err != nil {
+		return nil, err
 	}
-	defer rows.Close()
-
-	pr.AssignedReviewers = make([]domain.UserID, 0)
-	for rows.Next() {
-		var rid domain.UserID
-		if err := rows.Scan(&rid); err != nil {
-			return nil, fmt.Errorf("scan reviewer: %w", err)
-		}
-		pr.AssignedReviewers = append(pr.AssignedReviewers, rid)
+	if err := r.loadLabels(ctx, []*domain.PullRequest{&pr}); err != nil {
+		return nil, err
 	}
 
 	return &pr, nil
 }
 
-// ListByReviewer returns pull requests where the given user is assigned as a reviewer,
-// ordered by creation time in descending order.
-func (r *pullRequestRepositoryPG) ListByReviewer(ctx context.Context, reviewerID domain.UserID) ([]*domain.PullRequest, error) {
-	rows, err := r.db.Pool.Query(ctx, `
-        SELECT p.pull_request_id, p.pull_request_name, p.author_id, p.status, p.created_at, p.merged_at
+// ListByReviewer returns one page of pull requests where the given user is assigned
+// as a reviewer, ordered by creation time in descending order with pull request ID
+// as a tie-breaker. Labels are loaded; reviewers of the listed pull requests are not.
+func (r *pullRequestRepositoryPG) ListByReviewer(
+	ctx context.Context,
+	reviewerID domain.UserID,
+	q domain.ReviewListQuery,
+) (*domain.PullRequestPage, error) {
+	var b queryBuilder
+	b.where("r.reviewer_id = " + b.arg(string(reviewerID)))
+	if len(q.Statuses) > 0 {
+		b.where("p.status = ANY(" + b.arg(statusStrings(q.Statuses)) + ")")
+	}
+	if c := q.After; c != nil {
+		b.where("(p.created_at, p.pull_request_id) < (" + b.arg(c.CreatedAt) + ", " + b.arg(string(c.ID)) + ")")
+	}
+
+	limit := ""
+	if q.Limit > 0 {
+		limit = "LIMIT " + b.arg(q.Limit+1)
+	}
+
+	rows, err := r.db.Query(ctx, `
+        SELECT p.pull_request_id, p.pull_request_name, p.author_id, p.status, p.priority, p.created_at, p.merged_at, p.closed_at
         FROM pull_requests p
         JOIN pull_request_reviewers r ON r.pull_request_id = p.pull_request_id
-        WHERE r.reviewer_id = $1
-        ORDER BY p.created_at DESC
-    `, string(reviewerID))
+        `+b.whereClause()+`
+        ORDER BY p.created_at DESC, p.pull_request_id DESC
+        `+limit, b.args...)
 	if err != nil {
 		return nil, fmt.Errorf("query pull_requests by reviewer: %w", err)
 	}
 	defer rows.Close()
 
-	result := make([]*domain.PullRequest, 0)
+	page := &domain.PullRequestPage{Items: make([]*domain.PullRequest, 0, q.Limit)}
 
 	for rows.Next() {
 		var pr domain.PullRequest
 		var status string
 		var mergedAt *time.Time
 
-		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &status, &pr.CreatedAt, &mergedAt); err != nil {
+		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &status, &pr.Priority, &pr.CreatedAt, &mergedAt, &pr.ClosedAt); err != nil {
 			return nil, fmt.Errorf("scan pull_request: %w", err)
 		}
 		pr.Status = domain.PullRequestStatus(status)
 		pr.MergedAt = mergedAt
 
-		result = append(result, &pr)
+		page.Items = append(page.Items, &pr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows pull_requests by reviewer: %w", err)
+	}
+
+	if q.Limit > 0 && len(page.Items) > q.Limit {
+		page.Items = page.Items[:q.Limit]
+		page.NextCursor = domain.NewPullRequestCursor(domain.PRSortCreatedAt, domain.SortDesc, page.Items[q.Limit-1])
 	}
 
-	return result, nil
+	if err := r.loadLabels(ctx, page.Items); err != nil {
+		return nil, err
+	}
+	return page, nil
 }
 
-// saveReviewers stores reviewer assignments for the given pull request inside the transaction.
-func saveReviewers(ctx context.Context, tx pgx.Tx, prID domain.PullRequestID, reviewers []domain.UserID) error {
-	for _, rid := range reviewers {
-		if _, err := tx.Exec(ctx, `
-            INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id)
-            VALUES ($1, $2)
-        `, string(prID), string(rid)); err != nil {
-			return fmt.Errorf("insert reviewer %s: %w", rid, err)
+// pullRequestPriority returns the priority of pr, defaulting to domain.PriorityNormal.
+func pullRequestPriority(pr *domain.PullRequest) domain.PullRequestPriority {
+	if pr.Priority == "" {
+		return domain.PriorityNormal
+	}
+	return pr.Priority
+}
+
+// saveReviewers stores reviewer assignments of the given pull request inside the transaction.
+// Reviewers found in kept keep their assignment time and review deadline; others are
+// assigned at assignedAt. Reviewers without a deadline get one from the SLA of the
+// author's team, counted from assignedAt.
+func saveReviewers(
+	ctx context.Context,
+	tx pgx.Tx,
+	pr *domain.PullRequest,
+	kept map[domain.UserID]reviewAssignment,
+	assignedAt time.Time,
+) error {
+	if len(pr.AssignedReviewers) == 0 {
+		return nil
+	}
+
+	due, err := reviewDueAt(ctx, tx, pr, assignedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, rid := range pr.AssignedReviewers {
+		assignment, ok := kept[rid]
+		if !ok {
+			assignment = reviewAssignment{assignedAt: assignedAt}
+		}
+		if assignment.dueAt == nil {
+			assignment.dueAt, assignment.overdueAt = due, nil
+		}
+		if err := insertReviewer(ctx, tx, pr, rid, assignment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteReviewers removes all reviewer assignments of the pull request inside the
+// transaction and returns the IDs of the removed reviewers with their assignments.
+func deleteReviewers(
+	ctx context.Context,
+	tx pgx.Tx,
+	id domain.PullRequestID,
+) ([]domain.UserID, map[domain.UserID]reviewAssignment, error) {
+	rows, err := tx.Query(ctx, `
+        DELETE FROM pull_request_reviewers
+        WHERE pull_request_id = $1
+        RETURNING reviewer_id, assigned_at, review_due_at, overdue_at
+    `, string(id))
+	if err != nil {
+		return nil, nil, fmt.Errorf("delete reviewers: %w", err)
+	}
+	defer rows.Close()
+
+	removed := make([]domain.UserID, 0)
+	assignments := make(map[domain.UserID]reviewAssignment)
+	for rows.Next() {
+		var rid domain.UserID
+		var a reviewAssignment
+		if err := rows.Scan(&rid, &a.assignedAt, &a.dueAt, &a.overdueAt); err != nil {
+			return nil, nil, fmt.Errorf("scan removed reviewer: %w", err)
+		}
+		removed = append(removed, rid)
+		assignments[rid] = a
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("delete reviewers: %w", err)
+	}
+	return removed, assignments, nil
+}
+
+// subtractUserIDs returns the IDs of a that are not in b, keeping their order.
+func subtractUserIDs(a, b []domain.UserID) []domain.UserID {
+	res := make([]domain.UserID, 0, len(a))
+	for _, id := range a {
+		if !containsUserID(b, id) {
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+// containsUserID reports whether ids contains id.
+func containsUserID(ids []domain.UserID, id domain.UserID) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
 		}
 	}
+	return false
+}
+
+// insertReviewer stores a single reviewer assignment together with its fallback
+// team and delegation taken from the pull request, its assignment time and its
+// review deadline.
+func insertReviewer(ctx context.Context, tx pgx.Tx, pr *domain.PullRequest, rid domain.UserID, a reviewAssignment) error {
+	fallbackTeam, delegatedFrom := reviewerOrigin(pr, rid)
+
+	if _, err := tx.Exec(ctx, `
+        INSERT INTO pull_request_reviewers (
+            pull_request_id, reviewer_id, fallback_team, delegated_from, assigned_at, review_due_at, overdue_at
+        )
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+    `, string(pr.ID), string(rid), fallbackTeam, delegatedFrom, a.assignedAt, a.dueAt, a.overdueAt); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return domain.ErrReviewerAlreadyAssigned
+		}
+		return fmt.Errorf("insert reviewer %s: %w", rid, err)
+	}
 	return nil
 }
 
-// Merge atomically marks a pull request as merged.
+// reviewerOrigin returns nullable fallback_team and delegated_from values of the reviewer.
+func reviewerOrigin(pr *domain.PullRequest, rid domain.UserID) (fallbackTeam, delegatedFrom *string) {
+	if name, ok := pr.FallbackReviewers[rid]; ok {
+		fallbackTeam = &name
+	}
+	if from, ok := pr.DelegatedReviewers[rid]; ok {
+		id := string(from)
+		delegatedFrom = &id
+	}
+	return fallbackTeam, delegatedFrom
+}
+
+// lockPullRequest locks the pull request row for the rest of the transaction and
+// returns its current status. It returns domain.ErrNotFound if the pull request does not
+// exist and domain.ErrVersionConflict if ctx expects a version other than the stored one.
+func lockPullRequest(ctx context.Context, tx pgx.Tx, id domain.PullRequestID) (domain.PullRequestStatus, error) {
+	var status string
+	var version int64
+	err := tx.QueryRow(ctx, `
+        SELECT status, version
+        FROM pull_requests
+        WHERE pull_request_id = $1
+        FOR UPDATE
+    `, string(id)).Scan(&status, &version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", domain.ErrNotFound
+		}
+		return "", fmt.Errorf("lock pull_request %s: %w", id, err)
+	}
+	if want, ok := domain.ExpectedVersion(ctx); ok && want != version {
+		return "", versionConflict(id, version, want)
+	}
+	return domain.PullRequestStatus(status), nil
+}
+
+// lockOpenPullRequest locks the pull request row for the rest of the transaction.
+// It returns domain.ErrNotFound if the pull request does not exist,
+// domain.ErrPullRequestAlreadyMerged if it is merged and
+// domain.ErrPullRequestNotOpen if it is a draft or closed.
+func lockOpenPullRequest(ctx context.Context, tx pgx.Tx, id domain.PullRequestID) error {
+	status, err := lockPullRequest(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	switch status {
+	case domain.PRStatusOpen:
+		return nil
+	case domain.PRStatusMerged:
+		return domain.ErrPullRequestAlreadyMerged
+	default:
+		return fmt.Errorf("%w: status %s", domain.ErrPullRequestNotOpen, status)
+	}
+}
+
+// expectVersion returns a context under which lockPullRequest accepts pr only at
+// the version it was read at. Pull requests without a version are not checked.
+// It returns domain.ErrVersionConflict if ctx already expects another version.
+func expectVersion(ctx context.Context, pr *domain.PullRequest) (context.Context, error) {
+	if pr.Version == 0 {
+		return ctx, nil
+	}
+	if want, ok := domain.ExpectedVersion(ctx); ok && want != pr.Version {
+		return ctx, versionConflict(pr.ID, pr.Version, want)
+	}
+	return domain.WithExpectedVersion(ctx, pr.Version), nil
+}
+
+// bumpVersion increments the version of a pull request changed in tx and returns
+// the new one.
+func bumpVersion(ctx context.Context, tx pgx.Tx, id domain.PullRequestID) (int64, error) {
+	var version int64
+	if err := tx.QueryRow(ctx, `
+        UPDATE pull_requests
+        SET version = version + 1
+        WHERE pull_request_id = $1
+        RETURNING version
+    `, string(id)).Scan(&version); err != nil {
+		return 0, fmt.Errorf("bump version of %s: %w", id, err)
+	}
+	return version, nil
+}
+
+func versionConflict(id domain.PullRequestID, version, want int64) error {
+	return fmt.Errorf("%w: %s is at version %d, not %d", domain.ErrVersionConflict, id, version, want)
+}
+
+// ChangeStatus stores a status transition of pr that was made from the given status,
+// together with its reviewers: they are replaced with pr.AssignedReviewers, so
+// closing releases them and opening assigns the new ones at the given moment.
+// Released reviewers lose their review decisions, so closing drops all of them. The transition and
+// every released or assigned reviewer are recorded as events.
+// If the stored status is no longer from, domain.ErrInvalidStatusTransition is returned.
+// The version of pr is checked and bumped as in Update.
+func (r *pullRequestRepositoryPG) ChangeStatus(
+	ctx context.Context,
+	pr *domain.PullRequest,
+	from domain.PullRequestStatus,
+	at time.Time,
+) error {
+	ctx, err := expectVersion(ctx, pr)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	current, err := lockPullRequest(ctx, tx, pr.ID)
+	if err != nil {
+		return err
+	}
+	if current != from {
+		return fmt.Errorf("%w: %s -> %s", domain.ErrInvalidStatusTransition, current, pr.Status)
+	}
+
+	if _, err := tx.Exec(ctx, `
+        UPDATE pull_requests
+        SET status = $2,
+            merged_at = $3,
+            closed_at = $4
+        WHERE pull_request_id = $1
+    `, string(pr.ID), string(pr.Status), pr.MergedAt, pr.ClosedAt); err != nil {
+		return fmt.Errorf("update status of %s: %w", pr.ID, err)
+	}
+
+	released, assignments, err := deleteReviewers(ctx, tx, pr.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := saveReviewers(ctx, tx, pr, assignments, at); err != nil {
+		return fmt.Errorf("save reviewers: %w", err)
+	}
+	if err := deleteReviews(ctx, tx, pr.ID, subtractUserIDs(released, pr.AssignedReviewers)); err != nil {
+		return err
+	}
+
+	if err := insertEvents(ctx, tx, domain.PullRequestEvent{
+		PullRequestID: pr.ID,
+		Type:          domain.PREventStatusChanged,
+		OldStatus:     from,
+		NewStatus:     pr.Status,
+	}); err != nil {
+		return err
+	}
+	if err := insertEvents(ctx, tx, removedEvents(pr.ID, subtractUserIDs(released, pr.AssignedReviewers), domain.ReasonClosed)...); err != nil {
+		return err
+	}
+	if err := insertEvents(ctx, tx, assignedEvents(pr, subtractUserIDs(pr.AssignedReviewers, released), auditReason(ctx, domain.ReasonAutomatic))...); err != nil {
+		return err
+	}
+
+	version, err := bumpVersion(ctx, tx, pr.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	pr.Version = version
+
+	return nil
+}
+
+// UpdateMetadata changes mutable metadata of a pull request and returns its new state.
+// Fields of upd that are nil keep their values. It returns domain.ErrNotFound if the
+// pull request does not exist and domain.ErrPullRequestAlreadyMerged if it is merged.
+func (r *pullRequestRepositoryPG) UpdateMetadata(
+	ctx context.Context,
+	id domain.PullRequestID,
+	upd domain.PullRequestUpdate,
+) (*domain.PullRequest, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	status, err := lockPullRequest(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if status == domain.PRStatusMerged {
+		return nil, domain.ErrPullRequestAlreadyMerged
+	}
+
+	var oldName string
+	var oldPriority domain.PullRequestPriority
+	var oldLabels []string
+	if err := tx.QueryRow(ctx, `
+        SELECT
+            p.pull_request_name,
+            p.priority,
+            COALESCE(ARRAY(
+                SELECT l.label FROM pull_request_labels l
+                WHERE l.pull_request_id = p.pull_request_id
+                ORDER BY l.label
+            ), '{}')
+        FROM pull_requests p
+        WHERE p.pull_request_id = $1
+    `, string(id)).Scan(&oldName, &oldPriority, &oldLabels); err != nil {
+		return nil, fmt.Errorf("get metadata of %s: %w", id, err)
+	}
+
+	var priority *string
+	if upd.Priority != nil {
+		p := string(*upd.Priority)
+		priority = &p
+	}
+
+	if _, err := tx.Exec(ctx, `
+        UPDATE pull_requests
+        SET pull_request_name = COALESCE($2, pull_request_name),
+            priority = COALESCE($3, priority)
+        WHERE pull_request_id = $1
+    `, string(id), upd.Name, priority); err != nil {
+		return nil, fmt.Errorf("update pull_request %s: %w", id, err)
+	}
+
+	var events []domain.PullRequestEvent
+	if upd.Name != nil && *upd.Name != oldName {
+		events = append(events, domain.PullRequestEvent{
+			PullRequestID: id,
+			Type:          domain.PREventUpdated,
+			Details:       fmt.Sprintf("pull_request_name: %q -> %q", oldName, *upd.Name),
+		})
+	}
+	if upd.Priority != nil && *upd.Priority != oldPriority {
+		events = append(events, domain.PullRequestEvent{
+			PullRequestID: id,
+			Type:          domain.PREventUpdated,
+			Details:       fmt.Sprintf("priority: %q -> %q", oldPriority, *upd.Priority),
+		})
+	}
+	if upd.Labels != nil {
+		if err := saveLabels(ctx, tx, id, *upd.Labels); err != nil {
+			return nil, err
+		}
+		if !slices.Equal(oldLabels, *upd.Labels) {
+			events = append(events, domain.PullRequestEvent{
+				PullRequestID: id,
+				Type:          domain.PREventUpdated,
+				Details:       fmt.Sprintf("labels: %q -> %q", oldLabels, *upd.Labels),
+			})
+		}
+	}
+
+	if err := insertEvents(ctx, tx, events...); err != nil {
+		return nil, err
+	}
+	if len(events) > 0 {
+		if _, err := bumpVersion(ctx, tx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// AddReviewer assigns one more reviewer to an OPEN pull request at the given moment
+// without touching other assignments. The fallback team and delegation of the
+// reviewer are taken from pr. It returns domain.ErrReviewerAlreadyAssigned if the
+// user already reviews it. The version of pr is checked and bumped as in Update.
+func (r *pullRequestRepositoryPG) AddReviewer(
+	ctx context.Context,
+	pr *domain.PullRequest,
+	reviewerID domain.UserID,
+	at time.Time,
+) error {
+	ctx, err := expectVersion(ctx, pr)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := lockOpenPullRequest(ctx, tx, pr.ID); err != nil {
+		return err
+	}
+
+	due, err := reviewDueAt(ctx, tx, pr, at)
+	if err != nil {
+		return err
+	}
+
+	if err := insertReviewer(ctx, tx, pr, reviewerID, reviewAssignment{assignedAt: at, dueAt: due}); err != nil {
+		return err
+	}
+
+	reason := auditReason(ctx, domain.ReasonManual)
+	if err := insertEvents(ctx, tx, assignedEvents(pr, []domain.UserID{reviewerID}, reason)...); err != nil {
+		return err
+	}
+
+	version, err := bumpVersion(ctx, tx, pr.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	pr.Version = version
+
+	return nil
+}
+
+// RemoveReviewer removes a single reviewer and their review decision from an OPEN
+// pull request. It returns domain.ErrReviewerNotAssigned if the user does not review it.
+// The version of pr is checked and bumped as in Update.
+func (r *pullRequestRepositoryPG) RemoveReviewer(ctx context.Context, pr *domain.PullRequest, reviewerID domain.UserID) error {
+	ctx, err := expectVersion(ctx, pr)
+	if err != nil {
+		return err
+	}
+	id := pr.ID
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := lockOpenPullRequest(ctx, tx, id); err != nil {
+		return err
+	}
+
+	cmd, err := tx.Exec(ctx, `
+        DELETE FROM pull_request_reviewers
+        WHERE pull_request_id = $1 AND reviewer_id = $2
+    `, string(id), string(reviewerID))
+	if err != nil {
+		return fmt.Errorf("delete reviewer %s: %w", reviewerID, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrReviewerNotAssigned
+	}
+	if err := deleteReviews(ctx, tx, id, []domain.UserID{reviewerID}); err != nil {
+		return err
+	}
+
+	reason := auditReason(ctx, domain.ReasonManual)
+	if err := insertEvents(ctx, tx, removedEvents(id, []domain.UserID{reviewerID}, reason)...); err != nil {
+		return err
+	}
+
+	version, err := bumpVersion(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	pr.Version = version
+
+	return nil
+}
+
+// ReplaceReviewer swaps oldID for newID on an OPEN pull request in place, leaving
+// other assignments untouched. The decision of the old reviewer is dropped. The
+// fallback team and delegation of the new reviewer are taken from pr; the new
+// reviewer gets a fresh review deadline counted from at.
+// It returns domain.ErrReviewerNotAssigned if oldID does not review the pull request
+// and domain.ErrReviewerAlreadyAssigned if newID already does.
+// The version of pr is checked and bumped as in Update, so two concurrent
+// reassignments of the same pull request cannot both succeed.
+func (r *pullRequestRepositoryPG) ReplaceReviewer(
+	ctx context.Context,
+	pr *domain.PullRequest,
+	oldID, newID domain.UserID,
+	at time.Time,
+) error {
+	ctx, err := expectVersion(ctx, pr)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := lockOpenPullRequest(ctx, tx, pr.ID); err != nil {
+		return err
+	}
+
+	fallbackTeam, delegatedFrom := reviewerOrigin(pr, newID)
+
+	due, err := reviewDueAt(ctx, tx, pr, at)
+	if err != nil {
+		return err
+	}
+
+	cmd, err := tx.Exec(ctx, `
+        UPDATE pull_request_reviewers
+        SET reviewer_id = $3,
+            fallback_team = $4,
+            delegated_from = $5,
+            assigned_at = $6,
+            review_due_at = $7,
+            overdue_at = NULL
+        WHERE pull_request_id = $1 AND reviewer_id = $2
+    `, string(pr.ID), string(oldID), string(newID), fallbackTeam, delegatedFrom, at, due)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return domain.ErrReviewerAlreadyAssigned
+		}
+		return fmt.Errorf("replace reviewer %s: %w", oldID, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrReviewerNotAssigned
+	}
+	if err := deleteReviews(ctx, tx, pr.ID, []domain.UserID{oldID}); err != nil {
+		return err
+	}
+
+	if err := insertEvents(ctx, tx, domain.PullRequestEvent{
+		PullRequestID: pr.ID,
+		Type:          domain.PREventReviewerReassigned,
+		OldReviewerID: oldID,
+		NewReviewerID: newID,
+		Reason:        auditReason(ctx, domain.ReasonManual),
+		Details:       reviewerDetails(pr, newID),
+	}); err != nil {
+		return err
+	}
+
+	version, err := bumpVersion(ctx, tx, pr.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	pr.Version = version
+
+	return nil
+}
+
+// Merge atomically marks an OPEN pull request as merged. Drafts and closed pull
+// requests cannot be merged (domain.ErrInvalidStatusTransition), and neither can
+// pull requests that depend on a draft or open one (domain.ErrDependencyNotMerged),
+// even with force. Unless force is set, at least as many of the currently assigned reviewers as the
+// author's team requires must have approved it, otherwise domain.ErrNotApproved is
+// returned. A forced merge that skips missing approvals is recorded in forced_merges.
+// The merge is recorded as a STATUS_CHANGED event.
 // If the pull request is already merged, it returns the existing state without error.
 // If the pull request does not exist, ErrNotFound is returned.
 func (r *pullRequestRepositoryPG) Merge(
 	ctx context.Context,
 	id domain.PullRequestID,
 	mergedAt time.Time,
+	force bool,
 ) (*domain.PullRequest, error) {
-	cmdTag, err := r.db.Pool.Exec(ctx, `
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	status, err := lockPullRequest(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if status == domain.PRStatusMerged {
+		return r.GetByID(ctx, id)
+	}
+	if !status.CanTransitionTo(domain.PRStatusMerged) {
+		return nil, fmt.Errorf("%w: %s -> %s", domain.ErrInvalidStatusTransition, status, domain.PRStatusMerged)
+	}
+
+	if err := checkDependencies(ctx, tx, id); err != nil {
+		return nil, err
+	}
+
+	var approvals int
+	err = tx.QueryRow(ctx, `
+        SELECT COUNT(*)
+        FROM pull_request_reviews rv
+        JOIN pull_request_reviewers prr
+          ON prr.pull_request_id = rv.pull_request_id
+         AND prr.reviewer_id = rv.reviewer_id
+        WHERE rv.pull_request_id = $1
+          AND rv.decision = $2
+    `, string(id), string(domain.ReviewDecisionApproved)).Scan(&approvals)
+	if err != nil {
+		return nil, fmt.Errorf("count approvals of %s: %w", id, err)
+	}
+
+	required, err := requiredApprovals(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := domain.PullRequestEvent{
+		PullRequestID: id,
+		Type:          domain.PREventStatusChanged,
+		OldStatus:     status,
+		NewStatus:     domain.PRStatusMerged,
+	}
+
+	if approvals < required {
+		if !force {
+			return nil, fmt.Errorf("%w: %d of %d required approvals", domain.ErrNotApproved, approvals, required)
+		}
+		if _, err := tx.Exec(ctx, `
+            INSERT INTO forced_merges (pull_request_id, approvals, required_approvals, forced_at)
+            VALUES ($1, $2, $3, $4)
+            ON CONFLICT (pull_request_id) DO NOTHING
+        `, string(id), approvals, required, mergedAt); err != nil {
+			return nil, fmt.Errorf("record forced merge %s: %w", id, err)
+		}
+		merged.Reason = domain.ReasonForced
+		merged.Details = fmt.Sprintf("%d of %d required approvals", approvals, required)
+	}
+
+	if _, err := tx.Exec(ctx, `
         UPDATE pull_requests
         SET status = $2,
             merged_at = $3
         WHERE pull_request_id = $1
-          AND status = $4
-    `,
-		id,
-		domain.PRStatusMerged,
-		mergedAt,
-		domain.PRStatusOpen,
-	)
-	if err != nil {
+    `, string(id), domain.PRStatusMerged, mergedAt); err != nil {
 		return nil, fmt.Errorf("merge pull request %s: %w", id, err)
 	}
 
-	if cmdTag.RowsAffected() == 0 {
-		pr, err := r.GetByID(ctx, id)
-		if err != nil {
-			return nil, err
-		}
-		if pr.IsMerged() {
-			return pr, nil
-		}
-		return nil, fmt.Errorf("merge pull request %s: unexpected status %s", id, pr.Status)
+	if err := insertEvents(ctx, tx, merged); err != nil {
+		return nil, err
 	}
-
-	pr, err := r.GetByID(ctx, id)
-	if err != nil {
+	if _, err := bumpVersion(ctx, tx, id); err != nil {
 		return nil, err
 	}
 
-	return pr, nil
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return r.GetByID(ctx, id)
 }
 
-// GetReviewerAssignmentStats returns statistics on the number of pull requests where user is a reviewer.
+// GetReviewerAssignmentStats returns statistics on the number of pull requests where user
+// is a reviewer and how many of those reviews missed the SLA.
 func (r *pullRequestRepositoryPG) GetReviewerAssignmentStats(ctx context.Context) ([]domain.ReviewerAssignmentStat, error) {
 	const query = `
         SELECT u.user_id AS reviewer_id,
-               COALESCE(COUNT(prr.pull_request_id), 0) AS assignments_count
+               COALESCE(COUNT(prr.pull_request_id), 0) AS assignments_count,
+               COUNT(prr.overdue_at) AS overdue_count
         FROM users u
         LEFT JOIN pull_request_reviewers prr
                ON prr.reviewer_id = u.user_id
         GROUP BY u.user_id
     `
 
-	rows, err := r.db.Pool.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("query reviewer stats: %w", err)
 	}
@@ -271,7 +986,7 @@ func (r *pullRequestRepositoryPG) GetReviewerAssignmentStats(ctx context.Context
 
 	for rows.Next() {
 		var s domain.ReviewerAssignmentStat
-		if err := rows.Scan(&s.ReviewerID, &s.AssignmentsCount); err != nil {
+		if err := rows.Scan(&s.ReviewerID, &s.AssignmentsCount, &s.OverdueCount); err != nil {
 			return nil, fmt.Errorf("scan reviewer stats: %w", err)
 		}
 		stats = append(stats, s)
@@ -284,6 +999,49 @@ func (r *pullRequestRepositoryPG) GetReviewerAssignmentStats(ctx context.Context
 	return stats, nil
 }
 
+// GetOpenReviewCounts returns the number of OPEN pull requests each of the given users
+// is reviewing. Every requested user is present in the result, with 0 when they have no open reviews.
+func (r *pullRequestRepositoryPG) GetOpenReviewCounts(
+	ctx context.Context,
+	reviewerIDs []domain.UserID,
+) (map[domain.UserID]int, error) {
+	res := make(map[domain.UserID]int, len(reviewerIDs))
+	if len(reviewerIDs) == 0 {
+		return res, nil
+	}
+	for _, id := range reviewerIDs {
+		res[id] = 0
+	}
+
+	rows, err := r.db.Query(ctx, `
+        SELECT prr.reviewer_id, COUNT(*) AS open_reviews
+        FROM pull_request_reviewers prr
+        JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id
+        WHERE prr.reviewer_id = ANY($1)
+          AND pr.status = $2
+        GROUP BY prr.reviewer_id
+    `, userIDStrings(reviewerIDs), domain.PRStatusOpen)
+	if err != nil {
+		return nil, fmt.Errorf("query open review counts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id domain.UserID
+		var count int
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, fmt.Errorf("scan open review counts: %w", err)
+		}
+		res[id] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows open review counts: %w", err)
+	}
+
+	return res, nil
+}
+
 // GetPullRequestReviewerStats returns statistics on the number of reviewers assigned per pull request.
 func (r *pullRequestRepositoryPG) GetPullRequestReviewerStats(ctx context.Context) ([]domain.PullRequestReviewersStat, error) {
 	const query = `
@@ -295,7 +1053,7 @@ func (r *pullRequestRepositoryPG) GetPullRequestReviewerStats(ctx context.Contex
         GROUP BY pr.pull_request_id
     `
 
-	rows, err := r.db.Pool.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("query pull request stats: %w", err)
 	}
@@ -317,3 +1075,130 @@ func (r *pullRequestRepositoryPG) GetPullRequestReviewerStats(ctx context.Contex
 
 	return stats, nil
 }
+
+// ListUnderstaffedOpen returns OPEN pull requests that have fewer reviewers than the
+// author's team settings and review rules ask for. Pull requests with a reviewer
+// removed by hand since they were last opened are left out.
+func (r *pullRequestRepositoryPG) ListUnderstaffedOpen(ctx context.Context) ([]domain.UnderstaffedPullRequest, error) {
+	const query = `
+        SELECT pull_request_id, author_id, reviewers_count, reviewers_target
+        FROM (
+            SELECT pr.pull_request_id,
+                   pr.author_id,
+                   (
+                       SELECT COUNT(*)
+                       FROM pull_request_reviewers prr
+                       WHERE prr.pull_request_id = pr.pull_request_id
+                   ) AS reviewers_count,
+                   COALESCE((
+                       SELECT MAX(rr.reviewers_count)
+                       FROM team_review_rules rr
+                       WHERE rr.team_id = at.team_id
+                         AND (rr.priority IS NULL OR rr.priority = pr.priority)
+                         AND (rr.label IS NULL OR EXISTS (
+                             SELECT 1
+                             FROM pull_request_labels l
+                             WHERE l.pull_request_id = pr.pull_request_id AND l.label = rr.label
+                         ))
+                   ), at.reviewers_count, $5) AS reviewers_target
+            FROM pull_requests pr
+            LEFT JOIN LATERAL (
+                SELECT tm.team_id,
+                       CASE WHEN ts.reviewers_count > 0 THEN ts.reviewers_count ELSE $5 END AS reviewers_count
+                FROM team_members tm
+                LEFT JOIN team_settings ts ON ts.team_id = tm.team_id
+                WHERE tm.user_id = pr.author_id
+                LIMIT 1
+            ) at ON true
+            WHERE pr.status = $4
+        ) s
+        WHERE s.reviewers_count < s.reviewers_target
+          AND NOT EXISTS (
+              SELECT 1
+              FROM pull_request_events e
+              WHERE e.pull_request_id = s.pull_request_id
+                AND e.event_type = $1
+                AND e.reason = $2
+                AND e.id > COALESCE((
+                    SELECT MAX(o.id)
+                    FROM pull_request_events o
+                    WHERE o.pull_request_id = s.pull_request_id
+                      AND o.event_type = $3
+                      AND o.new_status = $4
+                ), 0)
+          )
+        ORDER BY s.pull_request_id
+    `
+
+	rows, err := r.db.Query(ctx, query,
+		string(domain.PREventReviewerRemoved), string(domain.ReasonManual),
+		string(domain.PREventStatusChanged), string(domain.PRStatusOpen),
+		domain.DefaultReviewersCount)
+	if err != nil {
+		return nil, fmt.Errorf("query understaffed pull requests: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]domain.UnderstaffedPullRequest, 0)
+
+	for rows.Next() {
+		var u domain.UnderstaffedPullRequest
+		if err := rows.Scan(&u.PullRequestID, &u.AuthorID, &u.ReviewersCount, &u.ReviewersTarget); err != nil {
+			return nil, fmt.Errorf("scan understaffed pull request: %w", err)
+		}
+		res = append(res, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows understaffed pull requests: %w", err)
+	}
+
+	return res, nil
+}
+
+// GetLastAssignedAt returns, for each of the given users, when they were last assigned
+// to a pull request they still review. Users without assignments are absent from the map.
+func (r *pullRequestRepositoryPG) GetLastAssignedAt(
+	ctx context.Context,
+	reviewerIDs []domain.UserID,
+) (map[domain.UserID]time.Time, error) {
+	res := make(map[domain.UserID]time.Time, len(reviewerIDs))
+	if len(reviewerIDs) == 0 {
+		return res, nil
+	}
+
+	rows, err := r.db.Query(ctx, `
+        SELECT reviewer_id, MAX(assigned_at)
+        FROM pull_request_reviewers
+        WHERE reviewer_id = ANY($1)
+        GROUP BY reviewer_id
+    `, userIDStrings(reviewerIDs))
+	if err != nil {
+		return nil, fmt.Errorf("query last assignments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id domain.UserID
+		var at time.Time
+		if err := rows.Scan(&id, &at); err != nil {
+			return nil, fmt.Errorf("scan last assignment: %w", err)
+		}
+		res[id] = at
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows last assignments: %w", err)
+	}
+
+	return res, nil
+}
+
+// userIDStrings converts user IDs to plain strings so they can be passed as a text[] parameter.
+func userIDStrings(ids []domain.UserID) []string {
+	res := make([]string, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, string(id))
+	}
+	return res
+}
@@ -9,9 +9,25 @@ import (
 	"github.com/juzu400/avito-internship/internal/domain"
 )
 
+// ReplacementPicker chooses a replacement for oldReviewer on the open pull request pr.
+// repos work inside the caller's transaction, so they see its changes so far. The
+// picker records the fallback team and delegation of the replacement on pr.
+// An empty ID drops the review slot.
+type ReplacementPicker func(
+	ctx context.Context,
+	repos *Repositories,
+	pr *domain.PullRequest,
+	oldReviewer domain.UserID,
+) (domain.UserID, error)
+
+// TeamSettingsUpdater changes team settings in place. An error aborts the update.
+type TeamSettingsUpdater func(settings *domain.TeamSettings) error
+
 type UserRepository interface {
 	GetByID(ctx context.Context, id domain.UserID) (*domain.User, error)
 	SetIsActive(ctx context.Context, id domain.UserID, active bool) error
+	SetMaxOpenReviews(ctx context.Context, id domain.UserID, limit *int) error
+	DeactivateUsers(ctx context.Context, ids []domain.UserID, pick ReplacementPicker, at time.Time) ([]domain.ReviewerReassignment, error)
 }
 
 type TeamRepository interface {
@@ -19,16 +35,59 @@ type TeamRepository interface {
 	GetByName(ctx context.Context, name string) (*domain.Team, error)
 	GetByMemberID(ctx context.Context, userID domain.UserID) (*domain.Team, error)
 	GetTeamsByMemberIDs(ctx context.Context, userIDs []domain.UserID) (map[domain.UserID]*domain.Team, error)
+	GetSettings(ctx context.Context, teamName string) (*domain.TeamSettings, error)
+	UpdateSettings(ctx context.Context, teamName string, update TeamSettingsUpdater) (*domain.TeamSettings, error)
 }
 
 type PullRequestRepository interface {
 	Create(ctx context.Context, pr *domain.PullRequest) error
-	Update(ctx context.Context, pr *domain.PullRequest) error
+	Update(ctx context.Context, pr *domain.PullRequest, at time.Time) error
+	AddReviewer(ctx context.Context, pr *domain.PullRequest, reviewerID domain.UserID, at time.Time) error
+	RemoveReviewer(ctx context.Context, pr *domain.PullRequest, reviewerID domain.UserID) error
+	ReplaceReviewer(ctx context.Context, pr *domain.PullRequest, oldID, newID domain.UserID, at time.Time) error
+	ChangeStatus(ctx context.Context, pr *domain.PullRequest, from domain.PullRequestStatus, at time.Time) error
+	UpdateMetadata(ctx context.Context, id domain.PullRequestID, upd domain.PullRequestUpdate) (*domain.PullRequest, error)
 	GetByID(ctx context.Context, id domain.PullRequestID) (*domain.PullRequest, error)
-	ListByReviewer(ctx context.Context, reviewerID domain.UserID) ([]*domain.PullRequest, error)
-	Merge(ctx context.Context, id domain.PullRequestID, mergedAt time.Time) (*domain.PullRequest, error)
+	ListByReviewer(ctx context.Context, reviewerID domain.UserID, q domain.ReviewListQuery) (*domain.PullRequestPage, error)
+	List(ctx context.Context, q domain.PullRequestListQuery) (*domain.PullRequestPage, error)
+	Merge(ctx context.Context, id domain.PullRequestID, mergedAt time.Time, force bool) (*domain.PullRequest, error)
 	GetReviewerAssignmentStats(ctx context.Context) ([]domain.ReviewerAssignmentStat, error)
+	GetOpenReviewCounts(ctx context.Context, reviewerIDs []domain.UserID) (map[domain.UserID]int, error)
 	GetPullRequestReviewerStats(ctx context.Context) ([]domain.PullRequestReviewersStat, error)
+	ListUnderstaffedOpen(ctx context.Context) ([]domain.UnderstaffedPullRequest, error)
+	GetLastAssignedAt(ctx context.Context, reviewerIDs []domain.UserID) (map[domain.UserID]time.Time, error)
+	MarkOverdueReviews(ctx context.Context, now time.Time) ([]domain.OverdueReview, error)
+	ListStale(ctx context.Context, assignedBefore time.Time, teamName string) ([]domain.StalePullRequest, error)
+	AddDependency(ctx context.Context, id, dependsOn domain.PullRequestID) error
+	RemoveDependency(ctx context.Context, id, dependsOn domain.PullRequestID) error
+	GetDependencyGraph(ctx context.Context, id domain.PullRequestID) (*domain.DependencyGraph, error)
+}
+
+type AbsenceRepository interface {
+	Create(ctx context.Context, a *domain.Absence) error
+	ListByUser(ctx context.Context, userID domain.UserID) ([]domain.Absence, error)
+	Cancel(ctx context.Context, id domain.AbsenceID, at time.Time) (*domain.Absence, error)
+}
+
+type DelegationRepository interface {
+	Create(ctx context.Context, d *domain.Delegation) error
+	ListByUser(ctx context.Context, userID domain.UserID) ([]domain.Delegation, error)
+	Cancel(ctx context.Context, id domain.DelegationID, at time.Time) (*domain.Delegation, error)
+}
+
+type ReviewRepository interface {
+	Save(ctx context.Context, review *domain.Review) error
+}
+
+type EventRepository interface {
+	ListByPullRequest(ctx context.Context, id domain.PullRequestID) ([]domain.PullRequestEvent, error)
+}
+
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, req *domain.IdempotentRequest, now time.Time) (*domain.IdempotentRequest, error)
+	Complete(ctx context.Context, key domain.IdempotencyKey, resp domain.IdempotentResponse) error
+	Release(ctx context.Context, key domain.IdempotencyKey) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
 }
 
 // Repositories groups all repository interfaces used by services.
@@ -36,12 +95,28 @@ type Repositories struct {
 	Users        UserRepository
 	Teams        TeamRepository
 	PullRequests PullRequestRepository
+	Absences     AbsenceRepository
+	Delegations  DelegationRepository
+	Reviews      ReviewRepository
+	Events       EventRepository
+	Idempotency  IdempotencyRepository
 }
 
 func NewRepositories(db *DB) *Repositories {
+	return newRepositories(db.Pool)
+}
+
+// newRepositories returns repositories that run their queries through c, which
+// may be a transaction.
+func newRepositories(c conn) *Repositories {
 	return &Repositories{
-		Users:        NewUserRepository(db),
-		Teams:        NewTeamRepository(db),
-		PullRequests: NewPullRequestRepository(db),
+		Users:        &userRepositoryPG{db: c},
+		Teams:        &teamRepositoryPG{db: c},
+		PullRequests: &pullRequestRepositoryPG{db: c},
+		Absences:     &absenceRepositoryPG{db: c},
+		Delegations:  &delegationRepositoryPG{db: c},
+		Reviews:      &reviewRepositoryPG{db: c},
+		Events:       &eventRepositoryPG{db: c},
+		Idempotency:  &idempotencyRepositoryPG{db: c},
 	}
 }
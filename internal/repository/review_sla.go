@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+)
+
+// reviewAssignment is when a reviewer was assigned and the review SLA state of
+// that assignment.
+type reviewAssignment struct {
+	assignedAt time.Time
+	dueAt      *time.Time
+	overdueAt  *time.Time
+}
+
+// reviewDueAt returns the deadline of a review assigned at the given moment on pr
+// under the SLA of the author's team. Reviews of pull requests that are not OPEN,
+// and of teams without an SLA, have no deadline.
+func reviewDueAt(ctx context.Context, tx pgx.Tx, pr *domain.PullRequest, assignedAt time.Time) (*time.Time, error) {
+	if pr.Status != domain.PRStatusOpen {
+		return nil, nil
+	}
+
+	var settings domain.TeamSettings
+	err := tx.QueryRow(ctx, `
+        SELECT ts.review_sla_hours, ts.review_sla_business_days
+        FROM team_members tm
+        JOIN team_settings ts ON ts.team_id = tm.team_id
+        WHERE tm.user_id = $1
+        LIMIT 1
+    `, string(pr.AuthorID)).Scan(&settings.ReviewSLAHours, &settings.ReviewSLABusinessDays)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get review sla of %s: %w", pr.AuthorID, err)
+	}
+
+	return settings.ReviewDueAt(assignedAt), nil
+}
+
+// reviewSLAsOf returns, for each of the given pull request authors, the review SLA
+// settings of their team. Authors whose team has no settings are absent from the map.
+func reviewSLAsOf(ctx context.Context, tx pgx.Tx, authorIDs []string) (map[domain.UserID]domain.TeamSettings, error) {
+	rows, err := tx.Query(ctx, `
+        SELECT DISTINCT ON (tm.user_id) tm.user_id, ts.review_sla_hours, ts.review_sla_business_days
+        FROM team_members tm
+        JOIN team_settings ts ON ts.team_id = tm.team_id
+        WHERE tm.user_id = ANY($1)
+        ORDER BY tm.user_id
+    `, authorIDs)
+	if err != nil {
+		return nil, fmt.Errorf("query review slas: %w", err)
+	}
+	defer rows.Close()
+
+	res := make(map[domain.UserID]domain.TeamSettings)
+	for rows.Next() {
+		var author domain.UserID
+		var settings domain.TeamSettings
+		if err := rows.Scan(&author, &settings.ReviewSLAHours, &settings.ReviewSLABusinessDays); err != nil {
+			return nil, fmt.Errorf("scan review sla: %w", err)
+		}
+		res[author] = settings
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows review slas: %w", err)
+	}
+
+	return res, nil
+}
+
+// MarkOverdueReviews marks review assignments on OPEN pull requests whose deadline
+// passed by now and that have no submitted review yet, and records a REVIEW_OVERDUE
+// event for each of them. Every assignment is reported only once. The result is
+// ordered by pull request and reviewer ID.
+func (r *pullRequestRepositoryPG) MarkOverdueReviews(ctx context.Context, now time.Time) ([]domain.OverdueReview, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	rows, err := tx.Query(ctx, `
+        WITH overdue AS (
+            UPDATE pull_request_reviewers prr
+            SET overdue_at = $1
+            FROM pull_requests p
+            WHERE p.pull_request_id = prr.pull_request_id
+              AND p.status = $2
+              AND prr.overdue_at IS NULL
+              AND prr.review_due_at <= $1
+              AND NOT EXISTS (
+                  SELECT 1
+                  FROM pull_request_reviews rv
+                  WHERE rv.pull_request_id = prr.pull_request_id
+                    AND rv.reviewer_id = prr.reviewer_id
+              )
+            RETURNING prr.pull_request_id, prr.reviewer_id, prr.review_due_at, p.author_id
+        )
+        SELECT o.pull_request_id, o.reviewer_id, o.review_due_at,
+               COALESCE((SELECT ts.review_sla_auto_reassign
+                         FROM team_members tm
+                         JOIN team_settings ts ON ts.team_id = tm.team_id
+                         WHERE tm.user_id = o.author_id
+                         LIMIT 1), FALSE)
+        FROM overdue o
+        ORDER BY o.pull_request_id, o.reviewer_id
+    `, now, string(domain.PRStatusOpen))
+	if err != nil {
+		return nil, fmt.Errorf("mark overdue reviews: %w", err)
+	}
+
+	overdue := make([]domain.OverdueReview, 0)
+	for rows.Next() {
+		var o domain.OverdueReview
+		if err := rows.Scan(&o.PullRequestID, &o.ReviewerID, &o.DueAt, &o.AutoReassign); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan overdue review: %w", err)
+		}
+		overdue = append(overdue, o)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows overdue reviews: %w", err)
+	}
+
+	ids := make([]string, 0, len(overdue))
+	for _, o := range overdue {
+		ids = append(ids, string(o.PullRequestID))
+		if err := insertEvents(ctx, tx, domain.PullRequestEvent{
+			PullRequestID: o.PullRequestID,
+			Type:          domain.PREventReviewOverdue,
+			OldReviewerID: o.ReviewerID,
+			Reason:        domain.ReasonSLA,
+			Details:       fmt.Sprintf("review was due at %s", o.DueAt.UTC().Format(time.RFC3339)),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(ids) > 0 {
+		if _, err := tx.Exec(ctx, `
+            UPDATE pull_requests
+            SET version = version + 1
+            WHERE pull_request_id = ANY($1)
+        `, ids); err != nil {
+			return nil, fmt.Errorf("bump versions of overdue pull requests: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return overdue, nil
+}
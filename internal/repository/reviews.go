@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+)
+
+type reviewRepositoryPG struct {
+	db conn
+}
+
+func NewReviewRepository(db *DB) *reviewRepositoryPG {
+	return &reviewRepositoryPG{db: db.Pool}
+}
+
+// Save stores the decision of a reviewer on an OPEN pull request, replacing their
+// previous one. It returns domain.ErrNotFound if the pull request does not exist,
+// domain.ErrPullRequestAlreadyMerged if it is merged, domain.ErrPullRequestNotOpen
+// if it is a draft or closed and domain.ErrReviewerNotAssigned if the user does
+// not review it.
+// Every submission is appended to the pull request history and bumps its version,
+// which is stored in review.PullRequestVersion.
+func (r *reviewRepositoryPG) Save(ctx context.Context, review *domain.Review) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := lockOpenPullRequest(ctx, tx, review.PullRequestID); err != nil {
+		return err
+	}
+
+	cmd, err := tx.Exec(ctx, `
+        INSERT INTO pull_request_reviews (pull_request_id, reviewer_id, decision, submitted_at)
+        SELECT pull_request_id, reviewer_id, $3, $4
+        FROM pull_request_reviewers
+        WHERE pull_request_id = $1 AND reviewer_id = $2
+        ON CONFLICT (pull_request_id, reviewer_id)
+        DO UPDATE SET decision = EXCLUDED.decision,
+                      submitted_at = EXCLUDED.submitted_at
+    `, string(review.PullRequestID), string(review.ReviewerID), string(review.Decision), review.SubmittedAt)
+	if err != nil {
+		return fmt.Errorf("save review of %s: %w", review.ReviewerID, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrReviewerNotAssigned
+	}
+
+	if err := insertEvents(ctx, tx, domain.PullRequestEvent{
+		PullRequestID: review.PullRequestID,
+		Type:          domain.PREventReviewSubmitted,
+		ActorID:       review.ReviewerID,
+		Decision:      review.Decision,
+	}); err != nil {
+		return err
+	}
+	version, err := bumpVersion(ctx, tx, review.PullRequestID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	review.PullRequestVersion = version
+	return nil
+}
+
+// deleteReviews drops the decisions of reviewers who left the pull request, so
+// that a reviewer assigned to it again has to review it anew.
+func deleteReviews(ctx context.Context, tx pgx.Tx, id domain.PullRequestID, reviewerIDs []domain.UserID) error {
+	if len(reviewerIDs) == 0 {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, `
+        DELETE FROM pull_request_reviews
+        WHERE pull_request_id = $1 AND reviewer_id = ANY($2)
+    `, string(id), userIDStrings(reviewerIDs)); err != nil {
+		return fmt.Errorf("delete reviews of %s: %w", id, err)
+	}
+	return nil
+}
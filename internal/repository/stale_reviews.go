@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+)
+
+// ListStale returns OPEN pull requests that have reviewers assigned at or before
+// assignedBefore and still without a submitted review, longest waiting first.
+// Only those reviewers are listed. A non-empty teamName keeps pull requests whose
+// author is a member of that team. Age is left for the caller to fill.
+func (r *pullRequestRepositoryPG) ListStale(
+	ctx context.Context,
+	assignedBefore time.Time,
+	teamName string,
+) ([]domain.StalePullRequest, error) {
+	rows, err := r.db.Query(ctx, `
+        SELECT p.pull_request_id, p.pull_request_name, p.author_id, COALESCE(at.team_name, ''),
+               p.created_at,
+               MIN(prr.assigned_at) OVER (PARTITION BY p.pull_request_id) AS waiting_since,
+               prr.reviewer_id
+        FROM pull_requests p
+        JOIN pull_request_reviewers prr ON prr.pull_request_id = p.pull_request_id
+        LEFT JOIN LATERAL (
+            SELECT t.team_name
+            FROM team_members tm
+            JOIN teams t ON t.id = tm.team_id
+            WHERE tm.user_id = p.author_id
+            LIMIT 1
+        ) at ON TRUE
+        WHERE p.status = $1
+          AND prr.assigned_at <= $2
+          AND ($3 = '' OR at.team_name = $3)
+          AND NOT EXISTS (
+              SELECT 1
+              FROM pull_request_reviews rv
+              WHERE rv.pull_request_id = prr.pull_request_id
+                AND rv.reviewer_id = prr.reviewer_id
+          )
+        ORDER BY waiting_since, p.pull_request_id, prr.reviewer_id
+    `, string(domain.PRStatusOpen), assignedBefore, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("query stale pull requests: %w", err)
+	}
+	defer rows.Close()
+
+	stale := make([]domain.StalePullRequest, 0)
+	for rows.Next() {
+		var pr domain.StalePullRequest
+		var rid domain.UserID
+		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.TeamName, &pr.CreatedAt, &pr.WaitingSince, &rid); err != nil {
+			return nil, fmt.Errorf("scan stale pull request: %w", err)
+		}
+		if n := len(stale); n > 0 && stale[n-1].ID == pr.ID {
+			stale[n-1].PendingReviewers = append(stale[n-1].PendingReviewers, rid)
+			continue
+		}
+		pr.PendingReviewers = []domain.UserID{rid}
+		stale = append(stale, pr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows stale pull requests: %w", err)
+	}
+
+	return stale, nil
+}
@@ -14,18 +14,18 @@ import (
 )
 
 type teamRepositoryPG struct {
-	db *DB
+	db conn
 }
 
 func NewTeamRepository(db *DB) *teamRepositoryPG {
-	return &teamRepositoryPG{db: db}
+	return &teamRepositoryPG{db: db.Pool}
 }
 
 // UpsertTeam creates a new team with the given members or fails if a team
 // with the same name already exists. User records are upserted into the users
 // table.
 func (r *teamRepositoryPG) UpsertTeam(ctx context.Context, team *domain.Team) error {
-	tx, err := r.db.Pool.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
 	}
@@ -81,75 +81,59 @@ func (r *teamRepositoryPG) UpsertTeam(ctx context.Context, team *domain.Team) er
 	return nil
 }
 
-// GetByMemberID returns a team and its members for the given user ID.
-// If the user does not belong to any team, domain.ErrNotFound is returned.
+// GetByName returns the team with the given name and its members.
+// If the team does not exist, domain.ErrNotFound is returned.
 func (r *teamRepositoryPG) GetByName(ctx context.Context, name string) (*domain.Team, error) {
-	var teamID int64
-	row := r.db.Pool.QueryRow(ctx, `
-        SELECT id
-        FROM teams
-        WHERE team_name = $1
-    `, name)
-
-	if err := row.Scan(&teamID); err != nil {
-		return nil, domain.ErrNotFound
-	}
-
-	team := &domain.Team{
-		Name:    name,
-		Members: make([]domain.User, 0),
-	}
-
-	rows, err := r.db.Pool.Query(ctx, `
-        SELECT u.user_id, u.username, u.is_active
-        FROM team_members tm
-        JOIN users u ON u.user_id = tm.user_id
-        WHERE tm.team_id = $1
-        ORDER BY u.user_id
-    `, teamID)
-	if err != nil {
-		return nil, fmt.Errorf("query team members: %w", err)
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var u domain.User
-		if err := rows.Scan(&u.ID, &u.Username, &u.IsActive); err != nil {
-			return nil, fmt.Errorf("scan team member: %w", err)
-		}
-		team.Members = append(team.Members, u)
-	}
-
-	return team, nil
+	return r.loadTeam(ctx, `t.team_name = $1`, name)
 }
 
-// GetTeamsByMemberIDs returns teams for the given user IDs keyed by user ID.
-// Users that are not members of any team are not present in the result map.
+// GetByMemberID returns a team and its members for the given user ID.
+// If the user does not belong to any team, domain.ErrNotFound is returned.
 func (r *teamRepositoryPG) GetByMemberID(ctx context.Context, userID domain.UserID) (*domain.Team, error) {
-	var teamID int64
-	var teamName string
+	return r.loadTeam(ctx, `t.id = (
+            SELECT tm.team_id
+            FROM team_members tm
+            WHERE tm.user_id = $1
+            LIMIT 1
+        )`, string(userID))
+}
 
-	row := r.db.Pool.QueryRow(ctx, `
-        SELECT t.id, t.team_name
-        FROM team_members tm
-        JOIN teams t ON t.id = tm.team_id
-        WHERE tm.user_id = $1
-        LIMIT 1
-    `, string(userID))
+// loadTeam returns the team matching the given condition on teams t, with its
+// settings, fallback teams, review rules and members ordered by user ID.
+// If no team matches, domain.ErrNotFound is returned.
+func (r *teamRepositoryPG) loadTeam(ctx context.Context, cond string, arg any) (*domain.Team, error) {
+	var teamID int64
+	var settings teamSettingsRow
+	team := &domain.Team{Members: make([]domain.User, 0)}
+
+	row := r.db.QueryRow(ctx, `
+        SELECT t.id, t.team_name, `+teamSettingsColumns+`
+        FROM teams t
+        LEFT JOIN team_settings ts ON ts.team_id = t.id
+        WHERE `+cond, arg)
+	if err := row.Scan(append([]any{&teamID, &team.Name}, settings.dest()...)...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("get team: %w", err)
+	}
+	team.Settings = settings.toDomain()
 
-	if err := row.Scan(&teamID, &teamName); err != nil {
-		return nil, domain.ErrNotFound
+	fallbacks, err := getFallbackTeams(ctx, r.db, teamID)
+	if err != nil {
+		return nil, err
 	}
+	team.Settings.FallbackTeams = fallbacks
 
-	team := &domain.Team{
-		Name:    teamName,
-		Members: make([]domain.User, 0),
+	if team.Settings.ReviewRules, err = reviewRules(ctx, r.db, teamID); err != nil {
+		return nil, err
 	}
 
-	rows, err := r.db.Pool.Query(ctx, `
-        SELECT u.user_id, u.username, u.is_active
+	rows, err := r.db.Query(ctx, `
+        SELECT u.user_id, u.username, u.is_active, u.max_open_reviews,
+               `+currentAbsenceColumns+`, `+currentDelegationColumns+`
         FROM team_members tm
-        JOIN users u ON u.user_id = tm.user_id
+        JOIN users u ON u.user_id = tm.user_id`+currentAbsenceJoin+currentDelegationJoin+`
         WHERE tm.team_id = $1
         ORDER BY u.user_id
     `, teamID)
@@ -159,16 +143,22 @@ func (r *teamRepositoryPG) GetByMemberID(ctx context.Context, userID domain.User
 	defer rows.Close()
 
 	for rows.Next() {
-		var u domain.User
-		if err := rows.Scan(&u.ID, &u.Username, &u.IsActive); err != nil {
-			return nil, fmt.Errorf("scan team member: %w", err)
+		u, err := scanUserWithAbsence(rows)
+		if err != nil {
+			return nil, err
 		}
 		team.Members = append(team.Members, u)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows team members: %w", err)
+	}
+
 	return team, nil
 }
 
+// GetTeamsByMemberIDs returns teams for the given user IDs keyed by user ID.
+// Users that are not members of any team are not present in the result map.
 func (r *teamRepositoryPG) GetTeamsByMemberIDs(ctx context.Context, userIDs []domain.UserID) (map[domain.UserID]*domain.Team, error) {
 	if len(userIDs) == 0 {
 		return map[domain.UserID]*domain.Team{}, nil
@@ -181,7 +171,7 @@ func (r *teamRepositoryPG) GetTeamsByMemberIDs(ctx context.Context, userIDs []do
 		placeholders[i] = fmt.Sprintf("$%d", i+1)
 	}
 
-	rows, err := r.db.Pool.Query(ctx, fmt.Sprintf(`
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`
         SELECT tm.user_id, t.team_name
         FROM team_members tm
         JOIN teams t ON t.id = tm.team_id
@@ -212,3 +202,235 @@ func (r *teamRepositoryPG) GetTeamsByMemberIDs(ctx context.Context, userIDs []do
 
 	return res, nil
 }
+
+// GetSettings returns reviewer assignment settings of the team with the given name.
+// Teams without stored settings get domain.DefaultTeamSettings.
+// If the team does not exist, domain.ErrNotFound is returned.
+func (r *teamRepositoryPG) GetSettings(ctx context.Context, teamName string) (*domain.TeamSettings, error) {
+	var teamID int64
+	var settings teamSettingsRow
+	row := r.db.QueryRow(ctx, `
+        SELECT t.id, `+teamSettingsColumns+`
+        FROM teams t
+        LEFT JOIN team_settings ts ON ts.team_id = t.id
+        WHERE t.team_name = $1
+    `, teamName)
+
+	if err := row.Scan(append([]any{&teamID}, settings.dest()...)...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("get team settings %s: %w", teamName, err)
+	}
+
+	res := settings.toDomain()
+
+	fallbacks, err := getFallbackTeams(ctx, r.db, teamID)
+	if err != nil {
+		return nil, err
+	}
+	res.FallbackTeams = fallbacks
+
+	if res.ReviewRules, err = reviewRules(ctx, r.db, teamID); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// UpdateSettings changes reviewer assignment settings of the team in a single
+// transaction: the team is locked, its current settings (or the defaults) are
+// passed to update, and the result is stored, including the ordered lists of
+// fallback teams and review rules, and returned. An error from update aborts the
+// change and is returned as is. If the team does not exist, domain.ErrNotFound
+// is returned. If a fallback team does not exist, domain.ErrValidation is returned.
+func (r *teamRepositoryPG) UpdateSettings(
+	ctx context.Context,
+	teamName string,
+	update TeamSettingsUpdater,
+) (*domain.TeamSettings, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var teamID int64
+	var current teamSettingsRow
+	err = tx.QueryRow(ctx, `
+        SELECT t.id, `+teamSettingsColumns+`
+        FROM teams t
+        LEFT JOIN team_settings ts ON ts.team_id = t.id
+        WHERE t.team_name = $1
+        FOR UPDATE OF t
+    `, teamName).Scan(append([]any{&teamID}, current.dest()...)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("lock team settings %s: %w", teamName, err)
+	}
+
+	settings := current.toDomain()
+	if settings.FallbackTeams, err = getFallbackTeams(ctx, tx, teamID); err != nil {
+		return nil, err
+	}
+	if settings.ReviewRules, err = reviewRules(ctx, tx, teamID); err != nil {
+		return nil, err
+	}
+
+	if err := update(&settings); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, `
+        INSERT INTO team_settings (
+            team_id, reviewers_count, min_reviewers, reviewer_strategy, required_approvals,
+            review_sla_hours, review_sla_business_days, review_sla_auto_reassign
+        )
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        ON CONFLICT (team_id)
+        DO UPDATE SET reviewers_count = EXCLUDED.reviewers_count,
+                      min_reviewers = EXCLUDED.min_reviewers,
+                      reviewer_strategy = EXCLUDED.reviewer_strategy,
+                      required_approvals = EXCLUDED.required_approvals,
+                      review_sla_hours = EXCLUDED.review_sla_hours,
+                      review_sla_business_days = EXCLUDED.review_sla_business_days,
+                      review_sla_auto_reassign = EXCLUDED.review_sla_auto_reassign
+    `, teamID, settings.ReviewersCount, settings.MinReviewers, string(settings.Strategy),
+		settings.RequiredApprovals, settings.ReviewSLAHours, settings.ReviewSLABusinessDays,
+		settings.ReviewSLAAutoReassign); err != nil {
+		return nil, fmt.Errorf("upsert team settings %s: %w", teamName, err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM team_fallbacks WHERE team_id = $1`, teamID); err != nil {
+		return nil, fmt.Errorf("delete old fallback teams: %w", err)
+	}
+
+	for i, name := range settings.FallbackTeams {
+		cmd, err := tx.Exec(ctx, `
+            INSERT INTO team_fallbacks (team_id, fallback_team_id, position)
+            SELECT $1, id, $3
+            FROM teams
+            WHERE team_name = $2
+        `, teamID, name, i)
+		if err != nil {
+			return nil, fmt.Errorf("insert fallback team %s: %w", name, err)
+		}
+		if cmd.RowsAffected() == 0 {
+			return nil, fmt.Errorf("%w: fallback team %s not found", domain.ErrValidation, name)
+		}
+	}
+
+	if err := saveReviewRules(ctx, tx, teamID, settings.ReviewRules); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// getFallbackTeams returns names of the fallback teams of the given team in priority order.
+func getFallbackTeams(ctx context.Context, q querier, teamID int64) ([]string, error) {
+	rows, err := q.Query(ctx, `
+        SELECT t.team_name
+        FROM team_fallbacks tf
+        JOIN teams t ON t.id = tf.fallback_team_id
+        WHERE tf.team_id = $1
+        ORDER BY tf.position
+    `, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("query fallback teams: %w", err)
+	}
+	defer rows.Close()
+
+	names := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("scan fallback team: %w", err)
+		}
+		names = append(names, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows fallback teams: %w", err)
+	}
+
+	return names, nil
+}
+
+// teamSettingsColumns selects the team_settings columns read by teamSettingsRow.
+const teamSettingsColumns = `ts.reviewers_count, ts.min_reviewers, ts.reviewer_strategy, ts.required_approvals,
+               ts.review_sla_hours, ts.review_sla_business_days, ts.review_sla_auto_reassign`
+
+// teamSettingsRow is a nullable projection of team_settings used with LEFT JOIN queries.
+type teamSettingsRow struct {
+	reviewersCount        *int
+	minReviewers          *int
+	strategy              *string
+	requiredApprovals     *int
+	reviewSLAHours        *int
+	reviewSLABusinessDays *bool
+	reviewSLAAutoReassign *bool
+}
+
+// dest returns scan destinations matching teamSettingsColumns.
+func (r *teamSettingsRow) dest() []any {
+	return []any{
+		&r.reviewersCount, &r.minReviewers, &r.strategy, &r.requiredApprovals,
+		&r.reviewSLAHours, &r.reviewSLABusinessDays, &r.reviewSLAAutoReassign,
+	}
+}
+
+// toDomain converts the row into domain settings, using defaults for missing values.
+func (r teamSettingsRow) toDomain() domain.TeamSettings {
+	settings := domain.DefaultTeamSettings()
+	if r.reviewersCount != nil {
+		settings.ReviewersCount = *r.reviewersCount
+	}
+	if r.minReviewers != nil {
+		settings.MinReviewers = *r.minReviewers
+	}
+	if r.strategy != nil {
+		settings.Strategy = domain.ReviewerStrategy(*r.strategy)
+	}
+	if r.requiredApprovals != nil {
+		settings.RequiredApprovals = *r.requiredApprovals
+	}
+	if r.reviewSLAHours != nil {
+		settings.ReviewSLAHours = *r.reviewSLAHours
+	}
+	if r.reviewSLABusinessDays != nil {
+		settings.ReviewSLABusinessDays = *r.reviewSLABusinessDays
+	}
+	if r.reviewSLAAutoReassign != nil {
+		settings.ReviewSLAAutoReassign = *r.reviewSLAAutoReassign
+	}
+	return settings
+}
+
+// scanUserWithAbsence reads a user selected together with currentAbsenceColumns
+// and currentDelegationColumns.
+// Extra destinations, if any, receive the leading columns of the row.
+func scanUserWithAbsence(row pgx.Row, dest ...any) (domain.User, error) {
+	var u domain.User
+	var absence currentAbsenceRow
+	var delegation currentDelegationRow
+
+	dest = append(dest, &u.ID, &u.Username, &u.IsActive, &u.MaxOpenReviews,
+		&absence.id, &absence.startsAt, &absence.endsAt,
+		&delegation.id, &delegation.toUserID, &delegation.startsAt, &delegation.endsAt)
+	if err := row.Scan(dest...); err != nil {
+		return domain.User{}, fmt.Errorf("scan user: %w", err)
+	}
+	u.Absence = absence.toDomain(u.ID)
+	u.Delegation = delegation.toDomain(u.ID)
+
+	return u, nil
+}
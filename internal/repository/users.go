@@ -4,30 +4,32 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/juzu400/avito-internship/internal/domain"
 )
 
 type userRepositoryPG struct {
-	db *DB
+	db conn
 }
 
 func NewUserRepository(db *DB) *userRepositoryPG {
-	return &userRepositoryPG{db: db}
+	return &userRepositoryPG{db: db.Pool}
 }
 
 // GetByID returns a user by ID.
 // If the user does not exist, domain.ErrNotFound is returned.
 func (r *userRepositoryPG) GetByID(ctx context.Context, id domain.UserID) (*domain.User, error) {
-	row := r.db.Pool.QueryRow(ctx, `
-        SELECT user_id, username, is_active
-        FROM users
-        WHERE user_id = $1
+	row := r.db.QueryRow(ctx, `
+        SELECT u.user_id, u.username, u.is_active, u.max_open_reviews,
+               `+currentAbsenceColumns+`, `+currentDelegationColumns+`
+        FROM users u`+currentAbsenceJoin+currentDelegationJoin+`
+        WHERE u.user_id = $1
     `, string(id))
 
-	var u domain.User
-	if err := row.Scan(&u.ID, &u.Username, &u.IsActive); err != nil {
+	u, err := scanUserWithAbsence(row)
+	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrNotFound
 		}
@@ -40,7 +42,7 @@ func (r *userRepositoryPG) GetByID(ctx context.Context, id domain.UserID) (*doma
 // SetIsActive updates the is_active flag for the given user.
 // If the user does not exist, domain.ErrNotFound is returned.
 func (r *userRepositoryPG) SetIsActive(ctx context.Context, id domain.UserID, active bool) error {
-	cmd, err := r.db.Pool.Exec(ctx, `
+	cmd, err := r.db.Exec(ctx, `
         UPDATE users
         SET is_active = $2
         WHERE user_id = $1
@@ -53,3 +55,242 @@ func (r *userRepositoryPG) SetIsActive(ctx context.Context, id domain.UserID, ac
 	}
 	return nil
 }
+
+// SetMaxOpenReviews updates the review capacity of the given user; nil removes the limit.
+// If the user does not exist, domain.ErrNotFound is returned.
+func (r *userRepositoryPG) SetMaxOpenReviews(ctx context.Context, id domain.UserID, limit *int) error {
+	cmd, err := r.db.Exec(ctx, `
+        UPDATE users
+        SET max_open_reviews = $2
+        WHERE user_id = $1
+    `, string(id), limit)
+	if err != nil {
+		return fmt.Errorf("set max_open_reviews for %s: %w", id, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// DeactivateUsers marks the given users inactive and releases their reviews on OPEN
+// pull requests in a single transaction. For every affected review pick is called
+// with repositories bound to the transaction, so candidates are read with the users
+// already inactive and with the handovers made so far. The slot is given to the
+// returned user, with a review deadline counted from at, or dropped when the ID is empty;
+// either way the decision of the old reviewer is dropped. Every handover or drop is recorded as a pull request event.
+// If any of the users does not exist, domain.ErrNotFound is returned and nothing changes.
+func (r *userRepositoryPG) DeactivateUsers(
+	ctx context.Context,
+	ids []domain.UserID,
+	pick ReplacementPicker,
+	at time.Time,
+) ([]domain.ReviewerReassignment, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	idStrings := userIDStrings(ids)
+
+	cmd, err := tx.Exec(ctx, `
+        UPDATE users
+        SET is_active = FALSE
+        WHERE user_id = ANY($1)
+    `, idStrings)
+	if err != nil {
+		return nil, fmt.Errorf("deactivate users: %w", err)
+	}
+	if int(cmd.RowsAffected()) != len(ids) {
+		return nil, fmt.Errorf("%w: some of the users do not exist", domain.ErrNotFound)
+	}
+
+	prs, err := lockOpenReviewsOf(ctx, tx, idStrings)
+	if err != nil {
+		return nil, err
+	}
+
+	authorIDs := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		authorIDs = append(authorIDs, string(pr.AuthorID))
+	}
+	slas, err := reviewSLAsOf(ctx, tx, authorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	deactivated := make(map[domain.UserID]struct{}, len(ids))
+	for _, id := range ids {
+		deactivated[id] = struct{}{}
+	}
+
+	repos := newRepositories(tx)
+	result := make([]domain.ReviewerReassignment, 0)
+
+	for _, pr := range prs {
+		batch := &pgx.Batch{}
+		batch.Queue(`
+            UPDATE pull_requests
+            SET version = version + 1
+            WHERE pull_request_id = $1
+        `, string(pr.ID))
+
+		for _, old := range append([]domain.UserID(nil), pr.AssignedReviewers...) {
+			if _, ok := deactivated[old]; !ok {
+				continue
+			}
+
+			newID, err := pick(ctx, repos, pr, old)
+			if err != nil {
+				return nil, fmt.Errorf("pick replacement for %s on %s: %w", old, pr.ID, err)
+			}
+
+			ev := domain.PullRequestEvent{
+				PullRequestID: pr.ID,
+				Type:          domain.PREventReviewerReassigned,
+				OldReviewerID: old,
+				NewReviewerID: newID,
+				Reason:        domain.ReasonDeactivation,
+				Details:       reviewerDetails(pr, newID),
+			}
+			if newID == "" {
+				ev.Type = domain.PREventReviewerRemoved
+			}
+			batch.Queue(insertEventSQL, eventArgs(ctx, ev)...)
+			batch.Queue(`
+                DELETE FROM pull_request_reviews
+                WHERE pull_request_id = $1 AND reviewer_id = $2
+            `, string(pr.ID), string(old))
+
+			if newID == "" {
+				batch.Queue(`
+                    DELETE FROM pull_request_reviewers
+                    WHERE pull_request_id = $1 AND reviewer_id = $2
+                `, string(pr.ID), string(old))
+				pr.AssignedReviewers = removeUserID(pr.AssignedReviewers, old)
+			} else {
+				fallbackTeam, delegatedFrom := reviewerOrigin(pr, newID)
+				due := slas[pr.AuthorID].ReviewDueAt(at)
+				batch.Queue(`
+                    UPDATE pull_request_reviewers
+                    SET reviewer_id = $3,
+                        fallback_team = $4,
+                        delegated_from = $5,
+                        assigned_at = $6,
+                        review_due_at = $7,
+                        overdue_at = NULL
+                    WHERE pull_request_id = $1 AND reviewer_id = $2
+                `, string(pr.ID), string(old), string(newID), fallbackTeam, delegatedFrom, at, due)
+				for i, id := range pr.AssignedReviewers {
+					if id == old {
+						pr.AssignedReviewers[i] = newID
+					}
+				}
+			}
+
+			result = append(result, domain.ReviewerReassignment{
+				PullRequestID: pr.ID,
+				OldReviewerID: old,
+				NewReviewerID: newID,
+			})
+		}
+
+		// Apply the changes before the next pull request, so that the picker
+		// counts the reviews handed out so far.
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return nil, fmt.Errorf("apply reassignments on %s: %w", pr.ID, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return result, nil
+}
+
+// lockOpenReviewsOf locks OPEN pull requests reviewed by any of the given users and
+// returns them with their full reviewer lists, ordered by pull request ID.
+func lockOpenReviewsOf(ctx context.Context, tx pgx.Tx, reviewerIDs []string) ([]*domain.PullRequest, error) {
+	rows, err := tx.Query(ctx, `
+        SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at
+        FROM pull_requests pr
+        WHERE pr.status = $2
+          AND EXISTS (
+              SELECT 1
+              FROM pull_request_reviewers prr
+              WHERE prr.pull_request_id = pr.pull_request_id
+                AND prr.reviewer_id = ANY($1)
+          )
+        ORDER BY pr.pull_request_id
+        FOR UPDATE
+    `, reviewerIDs, domain.PRStatusOpen)
+	if err != nil {
+		return nil, fmt.Errorf("lock open reviews: %w", err)
+	}
+
+	prs := make([]*domain.PullRequest, 0)
+	byID := make(map[domain.PullRequestID]*domain.PullRequest)
+	for rows.Next() {
+		var pr domain.PullRequest
+		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan open review: %w", err)
+		}
+		prs = append(prs, &pr)
+		byID[pr.ID] = &pr
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows open reviews: %w", err)
+	}
+
+	if len(prs) == 0 {
+		return prs, nil
+	}
+
+	prIDs := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		prIDs = append(prIDs, string(pr.ID))
+	}
+
+	rows, err = tx.Query(ctx, `
+        SELECT pull_request_id, reviewer_id
+        FROM pull_request_reviewers
+        WHERE pull_request_id = ANY($1)
+        ORDER BY pull_request_id, reviewer_id
+    `, prIDs)
+	if err != nil {
+		return nil, fmt.Errorf("query reviewers of open reviews: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var prID domain.PullRequestID
+		var rid domain.UserID
+		if err := rows.Scan(&prID, &rid); err != nil {
+			return nil, fmt.Errorf("scan reviewer: %w", err)
+		}
+		byID[prID].AssignedReviewers = append(byID[prID].AssignedReviewers, rid)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows reviewers of open reviews: %w", err)
+	}
+
+	return prs, nil
+}
+
+// removeUserID returns the list without the given user ID.
+func removeUserID(list []domain.UserID, id domain.UserID) []domain.UserID {
+	res := list[:0]
+	for _, v := range list {
+		if v != id {
+			res = append(res, v)
+		}
+	}
+	return res
+}
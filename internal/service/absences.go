@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+)
+
+// AddAbsence registers an out-of-office window for the user. While the window is
+// active the user is skipped by reviewer selection. The window must end after it
+// starts and must not be entirely in the past; the delegate, if set, must differ
+// from the user and gets their reviews for the same window, as with AddDelegation,
+// until the absence is cancelled. If the user or the delegate does not exist,
+// domain.ErrNotFound is returned.
+func (s *UsersService) AddAbsence(ctx context.Context, a *domain.Absence) error {
+	var reason string
+	switch {
+	case a.UserID == "":
+		reason = "empty user_id"
+	case a.StartsAt.IsZero() || a.EndsAt.IsZero():
+		reason = "starts_at and ends_at are required"
+	case !a.EndsAt.After(a.StartsAt):
+		reason = "ends_at must be after starts_at"
+	case !a.EndsAt.After(s.clock.Now()):
+		reason = "ends_at is in the past"
+	case a.DelegateID == a.UserID:
+		reason = "user cannot delegate to themselves"
+	}
+	if reason != "" {
+		s.log.Warn("validate AddAbsence failed",
+			slog.String("error_code", ErrCodeValidation),
+			slog.String("reason", reason),
+		)
+		return fmt.Errorf("%w: %s", domain.ErrValidation, reason)
+	}
+
+	s.log.Info("adding absence",
+		slog.String("user_id", string(a.UserID)),
+		slog.Time("starts_at", a.StartsAt),
+		slog.Time("ends_at", a.EndsAt),
+		slog.String("delegate_id", string(a.DelegateID)),
+	)
+
+	if err := s.absences.Create(ctx, a); err != nil {
+		s.log.Error("AddAbsence failed",
+			slog.String("user_id", string(a.UserID)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// ListAbsences returns all absences of the user, including cancelled and past ones.
+// It first ensures that the user exists by calling GetByID.
+func (s *UsersService) ListAbsences(ctx context.Context, id domain.UserID) ([]domain.Absence, error) {
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	absences, err := s.absences.ListByUser(ctx, id)
+	if err != nil {
+		s.log.Error("ListAbsences failed",
+			slog.String("user_id", string(id)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	return absences, nil
+}
+
+// CancelAbsence cancels the absence so that the user becomes available again.
+// Cancelling is idempotent. If the absence does not exist, domain.ErrNotFound is returned.
+func (s *UsersService) CancelAbsence(ctx context.Context, id domain.AbsenceID) (*domain.Absence, error) {
+	if id <= 0 {
+		s.log.Warn("validate CancelAbsence failed",
+			slog.String("error_code", ErrCodeValidation),
+			slog.String("reason", "invalid absence_id"),
+		)
+		return nil, fmt.Errorf("%w: absence_id must be positive", domain.ErrValidation)
+	}
+
+	s.log.Info("cancelling absence", slog.Int64("absence_id", int64(id)))
+
+	a, err := s.absences.Cancel(ctx, id, s.clock.Now())
+	if err != nil {
+		s.log.Error("CancelAbsence failed",
+			slog.Int64("absence_id", int64(id)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	return a, nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/repository/mocks"
+)
+
+func TestUsersService_AddAbsence_Validation(t *testing.T) {
+	now := time.Now().UTC()
+
+	cases := []struct {
+		name    string
+		absence domain.Absence
+	}{
+		{"empty user", domain.Absence{StartsAt: now, EndsAt: now.Add(time.Hour)}},
+		{"missing window", domain.Absence{UserID: "u1"}},
+		{"ends before start", domain.Absence{UserID: "u1", StartsAt: now.Add(time.Hour), EndsAt: now}},
+		{"in the past", domain.Absence{UserID: "u1", StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour)}},
+		{"self delegate", domain.Absence{UserID: "u1", StartsAt: now, EndsAt: now.Add(time.Hour), DelegateID: "u1"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc, _, _ := newTestUsersService(ctrl)
+
+			if err := svc.AddAbsence(context.Background(), &tc.absence); !errors.Is(err, domain.ErrValidation) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestUsersService_AddAbsence_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, _, _ := newTestUsersService(ctrl)
+	absenceRepo := mocks.NewMockAbsenceRepository(ctrl)
+	svc.absences = absenceRepo
+
+	now := time.Now().UTC()
+	absence := &domain.Absence{
+		UserID:     "u1",
+		StartsAt:   now,
+		EndsAt:     now.Add(24 * time.Hour),
+		DelegateID: "u2",
+	}
+
+	absenceRepo.EXPECT().
+		Create(gomock.Any(), absence).
+		Return(nil)
+
+	if err := svc.AddAbsence(context.Background(), absence); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUsersService_CancelAbsence_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, _, _ := newTestUsersService(ctrl)
+	absenceRepo := mocks.NewMockAbsenceRepository(ctrl)
+	svc.absences = absenceRepo
+
+	absenceRepo.EXPECT().
+		Cancel(gomock.Any(), domain.AbsenceID(7), gomock.Any()).
+		Return(nil, domain.ErrNotFound)
+
+	if _, err := svc.CancelAbsence(context.Background(), 7); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestUsersService_ListAbsences_UserNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, userRepo, _ := newTestUsersService(ctrl)
+
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), domain.UserID("u1")).
+		Return(nil, domain.ErrNotFound)
+
+	if _, err := svc.ListAbsences(context.Background(), "u1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
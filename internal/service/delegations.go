@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+)
+
+// AddDelegation hands the reviews of a user over to a named substitute for a
+// period. While it is in effect the user is skipped by reviewer selection and the
+// delegate is picked in their place when eligible. The period must end after it
+// starts and must not be entirely in the past; the delegate must differ from the
+// user. If either user does not exist, domain.ErrNotFound is returned.
+func (s *UsersService) AddDelegation(ctx context.Context, d *domain.Delegation) error {
+	var reason string
+	switch {
+	case d.FromUserID == "" || d.ToUserID == "":
+		reason = "empty from_user_id or to_user_id"
+	case d.StartsAt.IsZero() || d.EndsAt.IsZero():
+		reason = "starts_at and ends_at are required"
+	case !d.EndsAt.After(d.StartsAt):
+		reason = "ends_at must be after starts_at"
+	case !d.EndsAt.After(s.clock.Now()):
+		reason = "ends_at is in the past"
+	case d.FromUserID == d.ToUserID:
+		reason = "user cannot delegate to themselves"
+	}
+	if reason != "" {
+		s.log.Warn("validate AddDelegation failed",
+			slog.String("error_code", ErrCodeValidation),
+			slog.String("reason", reason),
+		)
+		return fmt.Errorf("%w: %s", domain.ErrValidation, reason)
+	}
+
+	s.log.Info("adding delegation",
+		slog.String("from_user_id", string(d.FromUserID)),
+		slog.String("to_user_id", string(d.ToUserID)),
+		slog.Time("starts_at", d.StartsAt),
+		slog.Time("ends_at", d.EndsAt),
+	)
+
+	if err := s.delegations.Create(ctx, d); err != nil {
+		s.log.Error("AddDelegation failed",
+			slog.String("from_user_id", string(d.FromUserID)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// ListDelegations returns all delegations made by the user, including cancelled
+// and past ones. It first ensures that the user exists by calling GetByID.
+func (s *UsersService) ListDelegations(ctx context.Context, id domain.UserID) ([]domain.Delegation, error) {
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	delegations, err := s.delegations.ListByUser(ctx, id)
+	if err != nil {
+		s.log.Error("ListDelegations failed",
+			slog.String("user_id", string(id)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	return delegations, nil
+}
+
+// CancelDelegation cancels the delegation so that the user is picked as a reviewer
+// again. Cancelling is idempotent. If the delegation does not exist,
+// domain.ErrNotFound is returned.
+func (s *UsersService) CancelDelegation(ctx context.Context, id domain.DelegationID) (*domain.Delegation, error) {
+	if id <= 0 {
+		s.log.Warn("validate CancelDelegation failed",
+			slog.String("error_code", ErrCodeValidation),
+			slog.String("reason", "invalid delegation_id"),
+		)
+		return nil, fmt.Errorf("%w: delegation_id must be positive", domain.ErrValidation)
+	}
+
+	s.log.Info("cancelling delegation", slog.Int64("delegation_id", int64(id)))
+
+	d, err := s.delegations.Cancel(ctx, id, s.clock.Now())
+	if err != nil {
+		s.log.Error("CancelDelegation failed",
+			slog.Int64("delegation_id", int64(id)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	return d, nil
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/repository/mocks"
+)
+
+func TestUsersService_AddDelegation_Validation(t *testing.T) {
+	now := time.Now().UTC()
+
+	cases := []struct {
+		name       string
+		delegation domain.Delegation
+	}{
+		{"empty from user", domain.Delegation{ToUserID: "u2", StartsAt: now, EndsAt: now.Add(time.Hour)}},
+		{"empty to user", domain.Delegation{FromUserID: "u1", StartsAt: now, EndsAt: now.Add(time.Hour)}},
+		{"missing window", domain.Delegation{FromUserID: "u1", ToUserID: "u2"}},
+		{"ends before start", domain.Delegation{FromUserID: "u1", ToUserID: "u2", StartsAt: now.Add(time.Hour), EndsAt: now}},
+		{"in the past", domain.Delegation{FromUserID: "u1", ToUserID: "u2", StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour)}},
+		{"self delegate", domain.Delegation{FromUserID: "u1", ToUserID: "u1", StartsAt: now, EndsAt: now.Add(time.Hour)}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc, _, _ := newTestUsersService(ctrl)
+
+			if err := svc.AddDelegation(context.Background(), &tc.delegation); !errors.Is(err, domain.ErrValidation) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestUsersService_AddDelegation_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, _, _ := newTestUsersService(ctrl)
+	delegationRepo := mocks.NewMockDelegationRepository(ctrl)
+	svc.delegations = delegationRepo
+
+	now := time.Now().UTC()
+	delegation := &domain.Delegation{
+		FromUserID: "u1",
+		ToUserID:   "u2",
+		StartsAt:   now,
+		EndsAt:     now.Add(24 * time.Hour),
+	}
+
+	delegationRepo.EXPECT().
+		Create(gomock.Any(), delegation).
+		Return(nil)
+
+	if err := svc.AddDelegation(context.Background(), delegation); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUsersService_CancelDelegation_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, _, _ := newTestUsersService(ctrl)
+	delegationRepo := mocks.NewMockDelegationRepository(ctrl)
+	svc.delegations = delegationRepo
+
+	delegationRepo.EXPECT().
+		Cancel(gomock.Any(), domain.DelegationID(7), gomock.Any()).
+		Return(nil, domain.ErrNotFound)
+
+	if _, err := svc.CancelDelegation(context.Background(), 7); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestUsersService_ListDelegations_UserNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, userRepo, _ := newTestUsersService(ctrl)
+
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), domain.UserID("u1")).
+		Return(nil, domain.ErrNotFound)
+
+	if _, err := svc.ListDelegations(context.Background(), "u1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/repository"
+)
+
+// defaultStaleAfter is how long a pull request may wait for reviews before it
+// shows up in reminders and digests when no threshold is configured.
+const defaultStaleAfter = 48 * time.Hour
+
+// DigestService finds pull requests that wait for reviews too long, builds team
+// digests and sends reminders through the configured Notifier.
+type DigestService struct {
+	log      *slog.Logger
+	teams    repository.TeamRepository
+	prs      repository.PullRequestRepository
+	notifier Notifier
+	clock    clock
+	// staleAfter is the pull request age after which pending reviews are stale.
+	staleAfter time.Duration
+}
+
+// TeamDigest returns the digest of stale pull requests authored by members of
+// the team. If the name is empty, ErrValidation is returned; if the team does not
+// exist, domain.ErrNotFound.
+func (s *DigestService) TeamDigest(ctx context.Context, teamName string) (*domain.TeamDigest, error) {
+	if teamName == "" {
+		s.log.Warn("validate TeamDigest failed",
+			slog.String("error_code", ErrCodeValidation),
+			slog.String("reason", "empty team_name"),
+		)
+		return nil, fmt.Errorf("%w: team_name is empty", domain.ErrValidation)
+	}
+
+	if _, err := s.teams.GetSettings(ctx, teamName); err != nil {
+		s.log.Warn("TeamDigest: team lookup failed",
+			slog.String("team_name", teamName),
+			slog.String("error_code", ErrorCode(err)),
+		)
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	stale, err := s.listStale(ctx, now, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TeamDigest{
+		TeamName:     teamName,
+		GeneratedAt:  now,
+		StaleAfter:   s.staleAfter,
+		PullRequests: stale,
+	}, nil
+}
+
+// SendReminders sends every reviewer with stale pull requests a reminder and
+// publishes a digest for every team that has any. Pull requests of authors
+// without a team only produce reminders. Delivery failures are logged and do not
+// stop the run; the report lists everything that was built.
+func (s *DigestService) SendReminders(ctx context.Context) (*domain.StaleReviewReport, error) {
+	now := s.clock.Now()
+	stale, err := s.listStale(ctx, now, "")
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.StaleReviewReport{
+		Reminders: buildReminders(stale),
+		Digests:   buildDigests(stale, now, s.staleAfter),
+	}
+
+	for _, reminder := range report.Reminders {
+		if err := s.notifier.NotifyReviewer(ctx, reminder); err != nil {
+			s.log.Error("SendReminders: reminder not delivered",
+				slog.String("reviewer_id", string(reminder.ReviewerID)),
+				slog.Any("err", err),
+			)
+		}
+	}
+	for _, digest := range report.Digests {
+		if err := s.notifier.PublishDigest(ctx, digest); err != nil {
+			s.log.Error("SendReminders: digest not delivered",
+				slog.String("team_name", digest.TeamName),
+				slog.Any("err", err),
+			)
+		}
+	}
+
+	s.log.Info("stale review reminders sent",
+		slog.Int("stale_pull_requests", len(stale)),
+		slog.Int("reminders", len(report.Reminders)),
+		slog.Int("digests", len(report.Digests)),
+	)
+	return report, nil
+}
+
+// listStale loads stale pull requests, optionally of a single team, and fills their age.
+func (s *DigestService) listStale(ctx context.Context, now time.Time, teamName string) ([]domain.StalePullRequest, error) {
+	stale, err := s.prs.ListStale(ctx, now.Add(-s.staleAfter), teamName)
+	if err != nil {
+		s.log.Error("ListStale failed",
+			slog.String("team_name", teamName),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+	for i := range stale {
+		stale[i].Age = now.Sub(stale[i].WaitingSince)
+	}
+	return stale, nil
+}
+
+// buildReminders groups stale pull requests by pending reviewer, ordered by reviewer ID.
+func buildReminders(stale []domain.StalePullRequest) []domain.ReviewReminder {
+	byReviewer := make(map[domain.UserID][]domain.StalePullRequest)
+	for _, pr := range stale {
+		for _, rid := range pr.PendingReviewers {
+			byReviewer[rid] = append(byReviewer[rid], pr)
+		}
+	}
+
+	reminders := make([]domain.ReviewReminder, 0, len(byReviewer))
+	for rid, prs := range byReviewer {
+		reminders = append(reminders, domain.ReviewReminder{ReviewerID: rid, PullRequests: prs})
+	}
+	sort.Slice(reminders, func(i, j int) bool {
+		return reminders[i].ReviewerID < reminders[j].ReviewerID
+	})
+	return reminders
+}
+
+// buildDigests groups stale pull requests by the author's team, ordered by team name.
+func buildDigests(stale []domain.StalePullRequest, now time.Time, staleAfter time.Duration) []domain.TeamDigest {
+	byTeam := make(map[string][]domain.StalePullRequest)
+	for _, pr := range stale {
+		if pr.TeamName != "" {
+			byTeam[pr.TeamName] = append(byTeam[pr.TeamName], pr)
+		}
+	}
+
+	digests := make([]domain.TeamDigest, 0, len(byTeam))
+	for team, prs := range byTeam {
+		digests = append(digests, domain.TeamDigest{
+			TeamName:     team,
+			GeneratedAt:  now,
+			StaleAfter:   staleAfter,
+			PullRequests: prs,
+		})
+	}
+	sort.Slice(digests, func(i, j int) bool {
+		return digests[i].TeamName < digests[j].TeamName
+	})
+	return digests
+}
+
+// RenderDigestMarkdown renders a team digest as a Markdown table.
+func RenderDigestMarkdown(d domain.TeamDigest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Review digest: %s\n\n", d.TeamName)
+	fmt.Fprintf(&b, "Generated at %s. Pull requests waiting for reviews longer than %s.\n\n",
+		d.GeneratedAt.UTC().Format(time.RFC3339), formatAge(d.StaleAfter))
+
+	if len(d.PullRequests) == 0 {
+		b.WriteString("No stale pull requests.\n")
+		return b.String()
+	}
+
+	b.WriteString("| Pull request | Author | Age | Waiting for |\n")
+	b.WriteString("| --- | --- | --- | --- |\n")
+	for _, pr := range d.PullRequests {
+		reviewers := make([]string, 0, len(pr.PendingReviewers))
+		for _, rid := range pr.PendingReviewers {
+			reviewers = append(reviewers, string(rid))
+		}
+		fmt.Fprintf(&b, "| %s (%s) | %s | %s | %s |\n",
+			markdownCell(pr.Name), pr.ID, pr.AuthorID, formatAge(pr.Age), strings.Join(reviewers, ", "))
+	}
+	return b.String()
+}
+
+// formatAge renders a duration in whole days and hours, e.g. "2d 5h".
+func formatAge(d time.Duration) string {
+	hours := int(d / time.Hour)
+	if hours < 24 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dd %dh", hours/24, hours%24)
+}
+
+// markdownCell escapes characters that would break a Markdown table cell.
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
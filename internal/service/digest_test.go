@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/repository/mocks"
+)
+
+// recordingNotifier collects everything sent through it and can fail reminders.
+type recordingNotifier struct {
+	reminders []domain.ReviewReminder
+	digests   []domain.TeamDigest
+	failFor   domain.UserID
+}
+
+func (n *recordingNotifier) NotifyReviewer(_ context.Context, r domain.ReviewReminder) error {
+	n.reminders = append(n.reminders, r)
+	if r.ReviewerID == n.failFor {
+		return errors.New("chat unavailable")
+	}
+	return nil
+}
+
+func (n *recordingNotifier) PublishDigest(_ context.Context, d domain.TeamDigest) error {
+	n.digests = append(n.digests, d)
+	return nil
+}
+
+func TestDigestService_SendReminders_GroupsByReviewerAndTeam(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	pr1 := domain.StalePullRequest{
+		ID: "pr-1", AuthorID: "a1", TeamName: "backend",
+		CreatedAt: now.Add(-96 * time.Hour), WaitingSince: now.Add(-72 * time.Hour), PendingReviewers: []domain.UserID{"u1", "u2"},
+	}
+	pr2 := domain.StalePullRequest{
+		ID: "pr-2", AuthorID: "a2", TeamName: "",
+		CreatedAt: now.Add(-50 * time.Hour), WaitingSince: now.Add(-50 * time.Hour), PendingReviewers: []domain.UserID{"u1"},
+	}
+
+	prRepo.EXPECT().
+		ListStale(gomock.Any(), now.Add(-48*time.Hour), "").
+		Return([]domain.StalePullRequest{pr1, pr2}, nil)
+
+	notifier := &recordingNotifier{failFor: "u1"}
+	svc := &DigestService{
+		log:        newTestLogger(),
+		prs:        prRepo,
+		notifier:   notifier,
+		clock:      fixedClock(now),
+		staleAfter: 48 * time.Hour,
+	}
+
+	report, err := svc.SendReminders(context.Background())
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	pr1.Age = 72 * time.Hour
+	pr2.Age = 50 * time.Hour
+	wantReminders := []domain.ReviewReminder{
+		{ReviewerID: "u1", PullRequests: []domain.StalePullRequest{pr1, pr2}},
+		{ReviewerID: "u2", PullRequests: []domain.StalePullRequest{pr1}},
+	}
+	if !reflect.DeepEqual(report.Reminders, wantReminders) {
+		t.Fatalf("unexpected reminders:\n got:  %+v\n want: %+v", report.Reminders, wantReminders)
+	}
+	wantDigests := []domain.TeamDigest{{
+		TeamName: "backend", GeneratedAt: now, StaleAfter: 48 * time.Hour,
+		PullRequests: []domain.StalePullRequest{pr1},
+	}}
+	if !reflect.DeepEqual(report.Digests, wantDigests) {
+		t.Fatalf("unexpected digests:\n got:  %+v\n want: %+v", report.Digests, wantDigests)
+	}
+
+	if len(notifier.reminders) != 2 || len(notifier.digests) != 1 {
+		t.Fatalf("expected delivery to continue after a failure, got %d reminders and %d digests",
+			len(notifier.reminders), len(notifier.digests))
+	}
+}
+
+func TestDigestService_TeamDigest_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	teamRepo.EXPECT().
+		GetSettings(gomock.Any(), "ghost").
+		Return(nil, domain.ErrNotFound)
+
+	svc := &DigestService{log: newTestLogger(), teams: teamRepo, staleAfter: time.Hour}
+
+	if _, err := svc.TeamDigest(context.Background(), "ghost"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDigestService_TeamDigest_EmptyName(t *testing.T) {
+	svc := &DigestService{log: newTestLogger()}
+
+	if _, err := svc.TeamDigest(context.Background(), ""); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected ErrValidation, got %v", err)
+	}
+}
+
+func TestRenderDigestMarkdown_Empty(t *testing.T) {
+	got := RenderDigestMarkdown(domain.TeamDigest{
+		TeamName:    "backend",
+		GeneratedAt: time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC),
+		StaleAfter:  48 * time.Hour,
+	})
+
+	want := "# Review digest: backend\n\n" +
+		"Generated at 2025-03-10T12:00:00Z. Pull requests waiting for reviews longer than 2d 0h.\n\n" +
+		"No stale pull requests.\n"
+	if got != want {
+		t.Fatalf("unexpected markdown:\n got:  %q\n want: %q", got, want)
+	}
+}
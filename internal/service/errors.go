@@ -13,8 +13,18 @@ const (
 	ErrCodeTeamAlreadyExists        = "TEAM_EXISTS"
 	ErrCodePullRequestAlreadyExists = "PR_EXISTS"
 	ErrCodePullRequestAlreadyMerged = "PR_MERGED"
+	ErrCodePullRequestNotOpen       = "PR_NOT_OPEN"
+	ErrCodeInvalidStatusTransition  = "INVALID_TRANSITION"
 	ErrCodeReviewerNotAssigned      = "NOT_ASSIGNED"
+	ErrCodeReviewerAlreadyAssigned  = "ALREADY_ASSIGNED"
 	ErrCodeNoReviewerCandidates     = "NO_CANDIDATE"
+	ErrCodeReviewerAtCapacity       = "REVIEWER_AT_CAPACITY"
+	ErrCodeNotApproved              = "NOT_APPROVED"
+	ErrCodeDependencyNotMerged      = "DEPENDENCY_NOT_MERGED"
+	ErrCodeDependencyCycle          = "DEPENDENCY_CYCLE"
+	ErrCodeVersionConflict          = "VERSION_CONFLICT"
+	ErrCodeIdempotencyKeyReused     = "IDEMPOTENCY_KEY_REUSED"
+	ErrCodeIdempotencyInProgress    = "IDEMPOTENCY_IN_PROGRESS"
 )
 
 // ErrorCode maps a domain or service error to a stable string error code
@@ -25,10 +35,30 @@ func ErrorCode(err error) string {
 		return ErrCodeNotFound
 	case errors.Is(err, domain.ErrPullRequestAlreadyMerged):
 		return ErrCodePullRequestAlreadyMerged
+	case errors.Is(err, domain.ErrPullRequestNotOpen):
+		return ErrCodePullRequestNotOpen
+	case errors.Is(err, domain.ErrInvalidStatusTransition):
+		return ErrCodeInvalidStatusTransition
 	case errors.Is(err, domain.ErrReviewerNotAssigned):
 		return ErrCodeReviewerNotAssigned
+	case errors.Is(err, domain.ErrReviewerAlreadyAssigned):
+		return ErrCodeReviewerAlreadyAssigned
 	case errors.Is(err, domain.ErrNoReviewerCandidates):
 		return ErrCodeNoReviewerCandidates
+	case errors.Is(err, domain.ErrReviewerAtCapacity):
+		return ErrCodeReviewerAtCapacity
+	case errors.Is(err, domain.ErrNotApproved):
+		return ErrCodeNotApproved
+	case errors.Is(err, domain.ErrDependencyNotMerged):
+		return ErrCodeDependencyNotMerged
+	case errors.Is(err, domain.ErrDependencyCycle):
+		return ErrCodeDependencyCycle
+	case errors.Is(err, domain.ErrVersionConflict):
+		return ErrCodeVersionConflict
+	case errors.Is(err, domain.ErrIdempotencyKeyReused):
+		return ErrCodeIdempotencyKeyReused
+	case errors.Is(err, domain.ErrIdempotencyInProgress):
+		return ErrCodeIdempotencyInProgress
 	case errors.Is(err, domain.ErrPullRequestAlreadyExists):
 		return ErrCodePullRequestAlreadyExists
 	case errors.Is(err, domain.ErrTeamAlreadyExists):
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/repository"
+)
+
+// defaultIdempotencyTTL is how long responses to requests with an idempotency
+// key are kept when no TTL is configured.
+const defaultIdempotencyTTL = 24 * time.Hour
+
+// idempotencyLease is how long a request may hold its key without storing a
+// response. After that a retry takes the key over, so a request lost in a crash
+// does not block the key until it expires.
+const idempotencyLease = time.Minute
+
+// IdempotencyService remembers responses to requests sent with an idempotency
+// key, so that a retried request gets the original response instead of being
+// applied twice.
+type IdempotencyService struct {
+	log   *slog.Logger
+	keys  repository.IdempotencyRepository
+	clock clock
+	// ttl is how long a key and its response are kept.
+	ttl time.Duration
+}
+
+// Begin starts handling a request with the given key. It returns nil if the
+// request is new: the caller handles it and then calls Complete or Release.
+// For a retry of a handled request it returns the stored response.
+// A key used for another request yields domain.ErrIdempotencyKeyReused, and a
+// key whose first request is still being handled yields domain.ErrIdempotencyInProgress.
+func (s *IdempotencyService) Begin(ctx context.Context, key domain.IdempotencyKey, requestHash string) (*domain.IdempotentResponse, error) {
+	if len(key.Key) > domain.MaxIdempotencyKeyLength {
+		reason := fmt.Sprintf("idempotency key is longer than %d characters", domain.MaxIdempotencyKeyLength)
+		s.log.Warn("validate Begin failed",
+			slog.String("error_code", ErrCodeValidation),
+			slog.String("reason", reason),
+		)
+		return nil, fmt.Errorf("%w: %s", domain.ErrValidation, reason)
+	}
+
+	now := s.clock.Now()
+	existing, err := s.keys.Reserve(ctx, &domain.IdempotentRequest{
+		Key:         key,
+		RequestHash: requestHash,
+		LockedUntil: now.Add(idempotencyLease),
+		ExpiresAt:   now.Add(s.ttl),
+	}, now)
+	if err != nil {
+		s.log.Error("Reserve idempotency key failed",
+			slog.String("idempotency_key", key.Key),
+			slog.String("path", key.Path),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	switch {
+	case existing == nil:
+		return nil, nil
+	case existing.RequestHash != requestHash:
+		s.log.Warn("idempotency key reused for another request",
+			slog.String("idempotency_key", key.Key),
+			slog.String("path", key.Path),
+			slog.String("error_code", ErrCodeIdempotencyKeyReused),
+		)
+		return nil, domain.ErrIdempotencyKeyReused
+	case existing.Response == nil:
+		return nil, domain.ErrIdempotencyInProgress
+	}
+
+	s.log.Info("replaying response",
+		slog.String("idempotency_key", key.Key),
+		slog.String("path", key.Path),
+		slog.Int("status", existing.Response.StatusCode),
+	)
+	return existing.Response, nil
+}
+
+// Complete stores the response to the request that holds the key.
+func (s *IdempotencyService) Complete(ctx context.Context, key domain.IdempotencyKey, resp domain.IdempotentResponse) error {
+	if err := s.keys.Complete(ctx, key, resp); err != nil {
+		s.log.Error("Complete idempotency key failed",
+			slog.String("idempotency_key", key.Key),
+			slog.String("path", key.Path),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return err
+	}
+	return nil
+}
+
+// Release frees the key after a request that must not be replayed, such as one
+// that failed with an internal error, so that a retry is handled again.
+func (s *IdempotencyService) Release(ctx context.Context, key domain.IdempotencyKey) error {
+	if err := s.keys.Release(ctx, key); err != nil {
+		s.log.Error("Release idempotency key failed",
+			slog.String("idempotency_key", key.Key),
+			slog.String("path", key.Path),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return err
+	}
+	return nil
+}
+
+// DeleteExpired removes keys older than the TTL and returns how many were removed.
+func (s *IdempotencyService) DeleteExpired(ctx context.Context) (int64, error) {
+	n, err := s.keys.DeleteExpired(ctx, s.clock.Now())
+	if err != nil {
+		s.log.Error("DeleteExpired failed",
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return 0, err
+	}
+	if n > 0 {
+		s.log.Info("expired idempotency keys deleted", slog.Int64("count", n))
+	}
+	return n, nil
+}
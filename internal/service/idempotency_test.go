@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/repository"
+	"github.com/juzu400/avito-internship/internal/repository/mocks"
+)
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	now := time.Date(2025, 10, 20, 9, 0, 0, 0, time.UTC)
+	key := domain.IdempotencyKey{Method: "POST", Path: "/pullRequest/reassign", ActorID: "u1", Key: "k1"}
+	stored := &domain.IdempotentResponse{StatusCode: 201, Body: []byte(`{"pr":{}}`)}
+
+	tests := []struct {
+		name     string
+		existing *domain.IdempotentRequest
+		want     *domain.IdempotentResponse
+		wantErr  error
+	}{
+		{name: "new request"},
+		{
+			name:     "retry",
+			existing: &domain.IdempotentRequest{Key: key, RequestHash: "h1", Response: stored},
+			want:     stored,
+		},
+		{
+			name:     "another request",
+			existing: &domain.IdempotentRequest{Key: key, RequestHash: "h2", Response: stored},
+			wantErr:  domain.ErrIdempotencyKeyReused,
+		},
+		{
+			name:     "in progress",
+			existing: &domain.IdempotentRequest{Key: key, RequestHash: "h1"},
+			wantErr:  domain.ErrIdempotencyInProgress,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			keys := mocks.NewMockIdempotencyRepository(ctrl)
+			keys.EXPECT().
+				Reserve(gomock.Any(), &domain.IdempotentRequest{
+					Key:         key,
+					RequestHash: "h1",
+					LockedUntil: now.Add(time.Minute),
+					ExpiresAt:   now.Add(6 * time.Hour),
+				}, now).
+				Return(tt.existing, nil)
+
+			svc := NewServices(newTestLogger(), &repository.Repositories{Idempotency: keys}, Config{
+				Clock:          func() time.Time { return now },
+				IdempotencyTTL: 6 * time.Hour,
+			}).Idempotency
+
+			got, err := svc.Begin(context.Background(), key, "h1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected response %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIdempotencyService_Begin_KeyTooLong(t *testing.T) {
+	svc := NewServices(newTestLogger(), &repository.Repositories{}, Config{}).Idempotency
+
+	_, err := svc.Begin(context.Background(), domain.IdempotencyKey{
+		Method: "POST",
+		Path:   "/pullRequest/reassign",
+		Key:    strings.Repeat("k", domain.MaxIdempotencyKeyLength+1),
+	}, "h1")
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected ErrValidation, got %v", err)
+	}
+}
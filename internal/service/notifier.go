@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+)
+
+// Notifier delivers stale review reminders and team digests. Implementations
+// choose the channel (chat, e-mail, webhook); the service only decides what to send.
+type Notifier interface {
+	NotifyReviewer(ctx context.Context, reminder domain.ReviewReminder) error
+	PublishDigest(ctx context.Context, digest domain.TeamDigest) error
+}
+
+// LogNotifier is a Notifier that writes reminders and digests to the log.
+// It is used when no other notifier is configured.
+type LogNotifier struct {
+	log *slog.Logger
+}
+
+func NewLogNotifier(log *slog.Logger) *LogNotifier {
+	return &LogNotifier{log: log.With(slog.String("notifier", "log"))}
+}
+
+func (n *LogNotifier) NotifyReviewer(_ context.Context, reminder domain.ReviewReminder) error {
+	ids := make([]string, 0, len(reminder.PullRequests))
+	for _, pr := range reminder.PullRequests {
+		ids = append(ids, string(pr.ID))
+	}
+	n.log.Info("stale review reminder",
+		slog.String("reviewer_id", string(reminder.ReviewerID)),
+		slog.Any("pull_request_ids", ids),
+	)
+	return nil
+}
+
+func (n *LogNotifier) PublishDigest(_ context.Context, digest domain.TeamDigest) error {
+	n.log.Info("team review digest",
+		slog.String("team_name", digest.TeamName),
+		slog.Int("stale_pull_requests", len(digest.PullRequests)),
+		slog.String("markdown", RenderDigestMarkdown(digest)),
+	)
+	return nil
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+)
+
+// AddDependency records that the pull request id cannot be merged before
+// dependsOn, and returns id with its updated dependency graph. Adding an existing
+// dependency is a no-op. It returns domain.ErrDependencyCycle if dependsOn already
+// depends on id, domain.ErrPullRequestAlreadyMerged if id is merged and
+// domain.ErrNotFound if either pull request does not exist.
+func (s *PullRequestService) AddDependency(
+	ctx context.Context,
+	id, dependsOn domain.PullRequestID,
+) (*domain.PullRequest, error) {
+	if err := s.validateDependencyChange("AddDependency", id, dependsOn); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("adding pull request dependency",
+		slog.String("pull_request_id", string(id)),
+		slog.String("depends_on", string(dependsOn)),
+	)
+
+	if err := s.prs.AddDependency(ctx, id, dependsOn); err != nil {
+		s.log.Error("AddDependency failed",
+			slog.String("pull_request_id", string(id)),
+			slog.String("depends_on", string(dependsOn)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	return s.Get(ctx, id)
+}
+
+// RemoveDependency deletes the dependency of id on dependsOn and returns id with
+// its updated dependency graph. It returns domain.ErrNotFound if there is no such
+// dependency and domain.ErrPullRequestAlreadyMerged if id is merged.
+func (s *PullRequestService) RemoveDependency(
+	ctx context.Context,
+	id, dependsOn domain.PullRequestID,
+) (*domain.PullRequest, error) {
+	if err := s.validateDependencyChange("RemoveDependency", id, dependsOn); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("removing pull request dependency",
+		slog.String("pull_request_id", string(id)),
+		slog.String("depends_on", string(dependsOn)),
+	)
+
+	if err := s.prs.RemoveDependency(ctx, id, dependsOn); err != nil {
+		s.log.Error("RemoveDependency failed",
+			slog.String("pull_request_id", string(id)),
+			slog.String("depends_on", string(dependsOn)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	return s.Get(ctx, id)
+}
+
+// validateDependencyChange checks both pull request IDs of a dependency change
+// and logs validation errors with the given operation name.
+func (s *PullRequestService) validateDependencyChange(op string, id, dependsOn domain.PullRequestID) error {
+	var reason string
+	switch {
+	case id == "" || dependsOn == "":
+		reason = "empty pull_request_id or depends_on"
+	case id == dependsOn:
+		reason = "pull request cannot depend on itself"
+	}
+	if reason != "" {
+		s.log.Warn("validate "+op+" failed",
+			slog.String("error_code", ErrCodeValidation),
+			slog.String("reason", reason),
+			slog.String("pull_request_id", string(id)),
+		)
+		return fmt.Errorf("%w: %s", domain.ErrValidation, reason)
+	}
+	return nil
+}
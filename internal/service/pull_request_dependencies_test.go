@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/repository/mocks"
+)
+
+func TestPullRequestService_AddDependency_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := &PullRequestService{
+		log: newTestLogger(),
+		prs: mocks.NewMockPullRequestRepository(ctrl),
+	}
+
+	tests := []struct {
+		name          string
+		id, dependsOn domain.PullRequestID
+	}{
+		{"empty pull request", "", "pr-1"},
+		{"empty dependency", "pr-2", ""},
+		{"self dependency", "pr-1", "pr-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.AddDependency(context.Background(), tt.id, tt.dependsOn); !errors.Is(err, domain.ErrValidation) {
+				t.Fatalf("expected ErrValidation, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPullRequestService_AddDependency_ReturnsGraph(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	svc := &PullRequestService{
+		log: newTestLogger(),
+		prs: prRepo,
+	}
+
+	graph := &domain.DependencyGraph{
+		Nodes: []domain.DependencyNode{
+			{ID: "pr-1", Name: "Base", Status: domain.PRStatusOpen},
+			{ID: "pr-2", Name: "On top", Status: domain.PRStatusOpen},
+		},
+		Edges: []domain.DependencyEdge{{PullRequestID: "pr-2", DependsOnID: "pr-1"}},
+	}
+
+	gomock.InOrder(
+		prRepo.EXPECT().AddDependency(gomock.Any(), domain.PullRequestID("pr-2"), domain.PullRequestID("pr-1")).Return(nil),
+		prRepo.EXPECT().GetByID(gomock.Any(), domain.PullRequestID("pr-2")).
+			Return(&domain.PullRequest{ID: "pr-2", Status: domain.PRStatusOpen}, nil),
+		prRepo.EXPECT().GetDependencyGraph(gomock.Any(), domain.PullRequestID("pr-2")).Return(graph, nil),
+	)
+
+	pr, err := svc.AddDependency(context.Background(), "pr-2", "pr-1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if pr.Dependencies != graph {
+		t.Fatalf("expected dependency graph %v, got %v", graph, pr.Dependencies)
+	}
+}
+
+func TestPullRequestService_AddDependency_Cycle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	svc := &PullRequestService{
+		log: newTestLogger(),
+		prs: prRepo,
+	}
+
+	prRepo.EXPECT().
+		AddDependency(gomock.Any(), domain.PullRequestID("pr-1"), domain.PullRequestID("pr-2")).
+		Return(domain.ErrDependencyCycle)
+
+	_, err := svc.AddDependency(context.Background(), "pr-1", "pr-2")
+	if !errors.Is(err, domain.ErrDependencyCycle) {
+		t.Fatalf("expected ErrDependencyCycle, got %v", err)
+	}
+	if code := ErrorCode(err); code != ErrCodeDependencyCycle {
+		t.Fatalf("expected error code %s, got %s", ErrCodeDependencyCycle, code)
+	}
+}
+
+func TestPullRequestService_Merge_DependencyNotMerged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	svc := &PullRequestService{
+		log: newTestLogger(),
+		prs: prRepo,
+	}
+
+	prRepo.EXPECT().
+		Merge(gomock.Any(), domain.PullRequestID("pr-2"), gomock.Any(), false).
+		Return(nil, domain.ErrDependencyNotMerged)
+
+	_, err := svc.Merge(context.Background(), "pr-2")
+	if !errors.Is(err, domain.ErrDependencyNotMerged) {
+		t.Fatalf("expected ErrDependencyNotMerged, got %v", err)
+	}
+	if code := ErrorCode(err); code != ErrCodeDependencyNotMerged {
+		t.Fatalf("expected error code %s, got %s", ErrCodeDependencyNotMerged, code)
+	}
+}
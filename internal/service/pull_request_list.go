@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+)
+
+const (
+	// defaultListLimit is the page size used when the client does not ask for one.
+	defaultListLimit = 50
+	// maxListLimit caps the page size of pull request listings.
+	maxListLimit = 100
+)
+
+// List returns one page of pull requests matching the filter, ordered by
+// q.SortBy (created_at by default) in q.Order (desc by default).
+// Invalid filters, limits and cursors are rejected with domain.ErrValidation.
+func (s *PullRequestService) List(ctx context.Context, q domain.PullRequestListQuery) (*domain.PullRequestPage, error) {
+	if q.SortBy == "" {
+		q.SortBy = domain.PRSortCreatedAt
+	}
+	if q.Order == "" {
+		q.Order = domain.SortDesc
+	}
+	if q.Limit == 0 {
+		q.Limit = defaultListLimit
+	}
+
+	if len(q.Filter.Labels) > 0 {
+		labels, err := domain.NormalizeLabels(q.Filter.Labels)
+		if err != nil {
+			s.log.Warn("validate List failed",
+				slog.String("error_code", ErrCodeValidation),
+				slog.String("reason", err.Error()),
+			)
+			return nil, err
+		}
+		q.Filter.Labels = labels
+	}
+
+	if reason := validateListQuery(q); reason != "" {
+		s.log.Warn("validate List failed",
+			slog.String("error_code", ErrCodeValidation),
+			slog.String("reason", reason),
+		)
+		return nil, fmt.Errorf("%w: %s", domain.ErrValidation, reason)
+	}
+
+	page, err := s.prs.List(ctx, q)
+	if err != nil {
+		s.log.Error("List failed",
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+	return page, nil
+}
+
+// validateListQuery returns why the listing query is invalid, or an empty string.
+func validateListQuery(q domain.PullRequestListQuery) string {
+	for _, st := range q.Filter.Statuses {
+		if !st.IsValid() {
+			return fmt.Sprintf("unknown status %q", st)
+		}
+	}
+	for _, p := range q.Filter.Priorities {
+		if !p.IsValid() {
+			return fmt.Sprintf("unknown priority %q", p)
+		}
+	}
+
+	switch {
+	case !q.SortBy.IsValid():
+		return fmt.Sprintf("unknown sort field %q", q.SortBy)
+	case !q.Order.IsValid():
+		return fmt.Sprintf("unknown sort order %q", q.Order)
+	case q.Limit < 1 || q.Limit > maxListLimit:
+		return fmt.Sprintf("limit must be between 1 and %d", maxListLimit)
+	case !validRange(q.Filter.CreatedFrom, q.Filter.CreatedTo):
+		return "created_from must be before created_to"
+	case !validRange(q.Filter.MergedFrom, q.Filter.MergedTo):
+		return "merged_from must be before merged_to"
+	case q.After != nil && (q.After.SortBy != q.SortBy || q.After.Order != q.Order):
+		return "cursor does not match sort and order"
+	}
+	return ""
+}
+
+// validRange reports whether the optional half-open range [from, to) is not empty.
+func validRange(from, to *time.Time) bool {
+	return from == nil || to == nil || from.Before(*to)
+}
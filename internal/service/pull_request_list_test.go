@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/repository/mocks"
+)
+
+func TestPullRequestService_List_AppliesDefaults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	prRepo.EXPECT().
+		List(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, q domain.PullRequestListQuery) (*domain.PullRequestPage, error) {
+			if q.SortBy != domain.PRSortCreatedAt || q.Order != domain.SortDesc {
+				t.Errorf("expected created_at desc, got %s %s", q.SortBy, q.Order)
+			}
+			if q.Limit != defaultListLimit {
+				t.Errorf("expected limit %d, got %d", defaultListLimit, q.Limit)
+			}
+			return &domain.PullRequestPage{}, nil
+		})
+
+	svc := &PullRequestService{log: newTestLogger(), prs: prRepo}
+
+	if _, err := svc.List(context.Background(), domain.PullRequestListQuery{}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+}
+
+func TestPullRequestService_List_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	svc := &PullRequestService{log: newTestLogger(), prs: prRepo}
+
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+
+	tests := []struct {
+		name string
+		q    domain.PullRequestListQuery
+	}{
+		{
+			name: "unknown status",
+			q:    domain.PullRequestListQuery{Filter: domain.PullRequestFilter{Statuses: []domain.PullRequestStatus{"PENDING"}}},
+		},
+		{
+			name: "unknown sort",
+			q:    domain.PullRequestListQuery{SortBy: "author"},
+		},
+		{
+			name: "unknown order",
+			q:    domain.PullRequestListQuery{Order: "up"},
+		},
+		{
+			name: "limit too large",
+			q:    domain.PullRequestListQuery{Limit: maxListLimit + 1},
+		},
+		{
+			name: "negative limit",
+			q:    domain.PullRequestListQuery{Limit: -1},
+		},
+		{
+			name: "empty created range",
+			q:    domain.PullRequestListQuery{Filter: domain.PullRequestFilter{CreatedFrom: &now, CreatedTo: &earlier}},
+		},
+		{
+			name: "empty merged range",
+			q:    domain.PullRequestListQuery{Filter: domain.PullRequestFilter{MergedFrom: &now, MergedTo: &now}},
+		},
+		{
+			name: "cursor from another sort",
+			q: domain.PullRequestListQuery{
+				SortBy: domain.PRSortCreatedAt,
+				After:  &domain.PullRequestCursor{SortBy: domain.PRSortName, Order: domain.SortDesc, ID: "pr-1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.List(context.Background(), tt.q); !errors.Is(err, domain.ErrValidation) {
+				t.Fatalf("expected ErrValidation, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+)
+
+// CreateDraft creates a pull request in DRAFT status. Drafts get no reviewers until
+// they are marked ready with MarkReady. The author must belong to a team, otherwise
+// domain.ErrNotFound is returned.
+func (s *PullRequestService) CreateDraft(
+	ctx context.Context,
+	id domain.PullRequestID,
+	name string,
+	authorID domain.UserID,
+	attrs domain.PullRequestAttributes,
+) (*domain.PullRequest, error) {
+	attrs, err := s.validateNewPullRequest("CreateDraft", id, name, authorID, attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("creating draft pull request",
+		slog.String("pull_request_id", string(id)),
+		slog.String("author_id", string(authorID)),
+	)
+
+	if _, err := s.teams.GetByMemberID(ctx, authorID); err != nil {
+		s.log.Error("get team for author failed",
+			slog.String("pull_request_id", string(id)),
+			slog.String("author_id", string(authorID)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	pr := &domain.PullRequest{
+		ID:                id,
+		Name:              name,
+		AuthorID:          authorID,
+		Status:            domain.PRStatusDraft,
+		Labels:            attrs.Labels,
+		Priority:          attrs.Priority,
+		AssignedReviewers: []domain.UserID{},
+		CreatedAt:         s.clock.Now(),
+	}
+	if err := s.prs.Create(domain.WithDefaultActor(ctx, authorID), pr); err != nil {
+		s.log.Error("Create draft pull request failed",
+			slog.String("pull_request_id", string(id)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+// MarkReady moves a DRAFT pull request to OPEN and assigns reviewers the same way
+// Create does, including the team's minimum reviewers check and review rules.
+func (s *PullRequestService) MarkReady(ctx context.Context, id domain.PullRequestID) (*domain.PullRequest, error) {
+	return s.open(ctx, "MarkReady", id, domain.PRStatusDraft)
+}
+
+// Reopen moves a CLOSED pull request back to OPEN. Reviewers released on close
+// are not restored: a fresh set is picked the same way Create does.
+func (s *PullRequestService) Reopen(ctx context.Context, id domain.PullRequestID) (*domain.PullRequest, error) {
+	return s.open(ctx, "Reopen", id, domain.PRStatusClosed)
+}
+
+// Close abandons a DRAFT or OPEN pull request and releases its reviewers.
+// Merged pull requests cannot be closed (domain.ErrInvalidStatusTransition).
+func (s *PullRequestService) Close(ctx context.Context, id domain.PullRequestID) (*domain.PullRequest, error) {
+	if err := s.validateStatusChange("Close", id); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("closing pull request", slog.String("pull_request_id", string(id)))
+
+	pr, err := s.prs.GetByID(ctx, id)
+	if err != nil {
+		s.log.Error("GetByID in Close failed",
+			slog.String("pull_request_id", string(id)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	from := pr.Status
+	now := s.clock.Now()
+	if err := pr.TransitionTo(domain.PRStatusClosed, now); err != nil {
+		s.log.Warn("Close: invalid transition",
+			slog.String("pull_request_id", string(id)),
+			slog.String("status", string(from)),
+			slog.String("error_code", ErrCodeInvalidStatusTransition),
+		)
+		return nil, err
+	}
+
+	if err := s.prs.ChangeStatus(ctx, pr, from, now); err != nil {
+		s.log.Error("ChangeStatus in Close failed",
+			slog.String("pull_request_id", string(id)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+// open implements MarkReady and Reopen: it moves the pull request from the given
+// status to OPEN and assigns reviewers from the author's team.
+func (s *PullRequestService) open(
+	ctx context.Context,
+	op string,
+	id domain.PullRequestID,
+	from domain.PullRequestStatus,
+) (*domain.PullRequest, error) {
+	if err := s.validateStatusChange(op, id); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("opening pull request",
+		slog.String("pull_request_id", string(id)),
+		slog.String("from_status", string(from)),
+	)
+
+	pr, err := s.prs.GetByID(ctx, id)
+	if err != nil {
+		s.log.Error("GetByID in "+op+" failed",
+			slog.String("pull_request_id", string(id)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	if pr.Status != from {
+		err := fmt.Errorf("%w: %s is %s, expected %s", domain.ErrInvalidStatusTransition, id, pr.Status, from)
+		s.log.Warn(op+": invalid transition",
+			slog.String("pull_request_id", string(id)),
+			slog.String("status", string(pr.Status)),
+			slog.String("error_code", ErrCodeInvalidStatusTransition),
+		)
+		return nil, err
+	}
+	now := s.clock.Now()
+	if err := pr.TransitionTo(domain.PRStatusOpen, now); err != nil {
+		return nil, err
+	}
+
+	attrs := domain.PullRequestAttributes{Labels: pr.Labels, Priority: pr.Priority}
+	planned, err := s.forPullRequest(id).planPullRequest(ctx, id, pr.Name, pr.AuthorID, attrs)
+	if err != nil {
+		return nil, err
+	}
+	pr.AssignedReviewers = planned.AssignedReviewers
+	pr.FallbackReviewers = planned.FallbackReviewers
+	pr.DelegatedReviewers = planned.DelegatedReviewers
+	pr.AssignmentExplanation = planned.AssignmentExplanation
+
+	if err := s.prs.ChangeStatus(ctx, pr, from, now); err != nil {
+		s.log.Error("ChangeStatus in "+op+" failed",
+			slog.String("pull_request_id", string(id)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+// validateStatusChange checks the pull request ID of a status change or lookup
+// and logs validation errors with the given operation name.
+func (s *PullRequestService) validateStatusChange(op string, id domain.PullRequestID) error {
+	if id == "" {
+		s.log.Warn("validate "+op+" failed",
+			slog.String("error_code", ErrCodeValidation),
+			slog.String("reason", "empty pull_request_id"),
+		)
+		return fmt.Errorf("%w: pull_request_id is empty", domain.ErrValidation)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/repository/mocks"
+)
+
+func TestPullRequestService_CreateDraft_AssignsNoReviewers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+
+	teamRepo.EXPECT().
+		GetByMemberID(gomock.Any(), domain.UserID("author")).
+		Return(&domain.Team{Name: "backend"}, nil)
+	prRepo.EXPECT().
+		Create(gomock.Any(), gomock.AssignableToTypeOf(&domain.PullRequest{})).
+		DoAndReturn(func(_ context.Context, pr *domain.PullRequest) error {
+			if pr.Status != domain.PRStatusDraft {
+				t.Errorf("expected status %q, got %q", domain.PRStatusDraft, pr.Status)
+			}
+			if len(pr.AssignedReviewers) != 0 {
+				t.Errorf("expected no reviewers, got %v", pr.AssignedReviewers)
+			}
+			return nil
+		})
+
+	svc := &PullRequestService{log: newTestLogger(), teams: teamRepo, prs: prRepo}
+
+	if _, err := svc.CreateDraft(context.Background(), "pr-1", "WIP", "author", domain.PullRequestAttributes{}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+}
+
+func TestPullRequestService_MarkReady_AssignsReviewers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+
+	prID := domain.PullRequestID("pr-1")
+	draft := &domain.PullRequest{
+		ID:                prID,
+		Name:              "WIP",
+		AuthorID:          "author",
+		Status:            domain.PRStatusDraft,
+		AssignedReviewers: []domain.UserID{},
+	}
+
+	prRepo.EXPECT().GetByID(gomock.Any(), prID).Return(draft, nil)
+	teamRepo.EXPECT().
+		GetByMemberID(gomock.Any(), domain.UserID("author")).
+		Return(&domain.Team{
+			Name: "backend",
+			Members: []domain.User{
+				{ID: "author", IsActive: true},
+				{ID: "u1", IsActive: true},
+			},
+		}, nil)
+	prRepo.EXPECT().ChangeStatus(gomock.Any(), draft, domain.PRStatusDraft, gomock.Any()).Return(nil)
+
+	svc := &PullRequestService{log: newTestLogger(), teams: teamRepo, prs: prRepo}
+
+	pr, err := svc.MarkReady(context.Background(), prID)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if pr.Status != domain.PRStatusOpen {
+		t.Fatalf("expected status %q, got %q", domain.PRStatusOpen, pr.Status)
+	}
+	if len(pr.AssignedReviewers) != 1 || pr.AssignedReviewers[0] != "u1" {
+		t.Fatalf("expected reviewers [u1], got %v", pr.AssignedReviewers)
+	}
+}
+
+func TestPullRequestService_Close_ReleasesReviewers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	prID := domain.PullRequestID("pr-1")
+	open := &domain.PullRequest{
+		ID:                prID,
+		Status:            domain.PRStatusOpen,
+		AssignedReviewers: []domain.UserID{"u1", "u2"},
+		FallbackReviewers: map[domain.UserID]string{"u2": "mobile"},
+	}
+
+	prRepo.EXPECT().GetByID(gomock.Any(), prID).Return(open, nil)
+	prRepo.EXPECT().ChangeStatus(gomock.Any(), open, domain.PRStatusOpen, gomock.Any()).Return(nil)
+
+	svc := &PullRequestService{log: newTestLogger(), prs: prRepo, clock: fixedClock(now)}
+
+	pr, err := svc.Close(context.Background(), prID)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if pr.Status != domain.PRStatusClosed {
+		t.Fatalf("expected status %q, got %q", domain.PRStatusClosed, pr.Status)
+	}
+	if len(pr.AssignedReviewers) != 0 || len(pr.FallbackReviewers) != 0 {
+		t.Fatalf("expected released reviewers, got %v / %v", pr.AssignedReviewers, pr.FallbackReviewers)
+	}
+	if pr.ClosedAt == nil || !pr.ClosedAt.Equal(now) {
+		t.Fatalf("expected closedAt %v, got %v", now, pr.ClosedAt)
+	}
+}
+
+func TestPullRequestService_StatusTransitions_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		status domain.PullRequestStatus
+		call   func(*PullRequestService, context.Context, domain.PullRequestID) (*domain.PullRequest, error)
+	}{
+		{"close merged", domain.PRStatusMerged, (*PullRequestService).Close},
+		{"close closed", domain.PRStatusClosed, (*PullRequestService).Close},
+		{"mark open ready", domain.PRStatusOpen, (*PullRequestService).MarkReady},
+		{"reopen draft", domain.PRStatusDraft, (*PullRequestService).Reopen},
+		{"reopen merged", domain.PRStatusMerged, (*PullRequestService).Reopen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			prRepo := mocks.NewMockPullRequestRepository(ctrl)
+			prRepo.EXPECT().
+				GetByID(gomock.Any(), domain.PullRequestID("pr-1")).
+				Return(&domain.PullRequest{ID: "pr-1", Status: tt.status}, nil)
+
+			svc := &PullRequestService{log: newTestLogger(), prs: prRepo}
+
+			_, err := tt.call(svc, context.Background(), "pr-1")
+			if !errors.Is(err, domain.ErrInvalidStatusTransition) {
+				t.Fatalf("expected ErrInvalidStatusTransition, got %v", err)
+			}
+		})
+	}
+}
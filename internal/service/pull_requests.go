@@ -2,39 +2,125 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"log/slog"
 	"math/rand"
-	"time"
+	"strings"
 
 	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/repository"
 )
 
 // Create creates a new pull request for the given author and automatically
-// assigns reviewers from the author's team. At most two reviewers are assigned.
+// assigns reviewers from the author's team. The number of reviewers and the selection
+// strategy come from the team settings. If fewer than the team's minimum number of
+// reviewers can be assigned, ErrNoReviewerCandidates is returned. Review rules of
+// the team matching the labels or priority in attrs override the reviewer count.
+// With Config.SeedByPullRequest the choice depends only on the ID and the team state.
 // If required fields are missing or business rules are violated, ErrValidation is returned.
 func (s *PullRequestService) Create(
 	ctx context.Context,
 	id domain.PullRequestID,
 	name string,
 	authorID domain.UserID,
+	attrs domain.PullRequestAttributes,
 ) (*domain.PullRequest, error) {
+	attrs, err := s.validateNewPullRequest("Create", id, name, authorID, attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("creating pull request",
+		slog.String("pull_request_id", string(id)),
+		slog.String("author_id", string(authorID)),
+	)
+
+	pr, err := s.forPullRequest(id).planPullRequest(ctx, id, name, authorID, attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.prs.Create(domain.WithDefaultActor(ctx, authorID), pr); err != nil {
+		s.log.Error("Create pull request failed",
+			slog.String("pull_request_id", string(id)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+// PreviewAssignment runs the same reviewer selection as Create and returns the
+// pull request that would be created, without storing anything. Random choices
+// are seeded the same way as in Create: with Config.SeedByPullRequest the preview
+// matches a later Create while the team and its load stay unchanged, otherwise
+// it is not binding.
+func (s *PullRequestService) PreviewAssignment(
+	ctx context.Context,
+	id domain.PullRequestID,
+	name string,
+	authorID domain.UserID,
+	attrs domain.PullRequestAttributes,
+) (*domain.PullRequest, error) {
+	attrs, err := s.validateNewPullRequest("PreviewAssignment", id, name, authorID, attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("previewing reviewer assignment",
+		slog.String("pull_request_id", string(id)),
+		slog.String("author_id", string(authorID)),
+	)
+
+	return s.forPullRequest(id).planPullRequest(ctx, id, name, authorID, attrs)
+}
+
+// validateNewPullRequest checks the fields required to create a pull request,
+// returns its normalized attributes and logs validation errors with the given
+// operation name.
+func (s *PullRequestService) validateNewPullRequest(
+	op string,
+	id domain.PullRequestID,
+	name string,
+	authorID domain.UserID,
+	attrs domain.PullRequestAttributes,
+) (domain.PullRequestAttributes, error) {
 	if id == "" || name == "" || authorID == "" {
 		err := fmt.Errorf("%w: missing fields (id/name/author)", domain.ErrValidation)
-		s.log.Warn("validate Create failed",
+		s.log.Warn("validate "+op+" failed",
 			slog.String("error_code", ErrCodeValidation),
 			slog.String("reason", "empty id/name/author_id"),
 			slog.String("pull_request_id", string(id)),
 			slog.String("author_id", string(authorID)),
 		)
-		return nil, err
+		return domain.PullRequestAttributes{}, err
 	}
 
-	s.log.Info("creating pull request",
-		slog.String("pull_request_id", string(id)),
-		slog.String("author_id", string(authorID)),
-	)
+	attrs, err := attrs.Normalize()
+	if err != nil {
+		s.log.Warn("validate "+op+" failed",
+			slog.String("error_code", ErrCodeValidation),
+			slog.String("reason", err.Error()),
+			slog.String("pull_request_id", string(id)),
+		)
+		return domain.PullRequestAttributes{}, err
+	}
+	return attrs, nil
+}
 
+// planPullRequest looks up the author's team and picks reviewers for a new
+// OPEN pull request with the given normalized attributes. The result is not stored.
+func (s *PullRequestService) planPullRequest(
+	ctx context.Context,
+	id domain.PullRequestID,
+	name string,
+	authorID domain.UserID,
+	attrs domain.PullRequestAttributes,
+) (*domain.PullRequest, error) {
 	team, err := s.teams.GetByMemberID(ctx, authorID)
 	if err != nil {
 		s.log.Error("get team for author failed",
@@ -46,35 +132,205 @@ func (s *PullRequestService) Create(
 		return nil, err
 	}
 
-	reviewers := pickReviewersFromTeam(team, authorID, 2)
+	target, _ := team.ReviewRequirements(attrs.Labels, attrs.Priority)
+	pick, err := s.pickReviewersFromTeam(ctx, team, authorID, nil, target)
+	if err != nil {
+		s.log.Error("pick reviewers failed",
+			slog.String("pull_request_id", string(id)),
+			slog.String("team_name", team.Name),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	// A rule asking for fewer reviewers than the team minimum lowers the minimum too.
+	minReviewers := min(team.Settings.MinReviewers, target)
+	reviewers := userIDs(pick.reviewers)
+	if len(reviewers) < minReviewers {
+		err := fmt.Errorf("%w: team %s requires at least %d reviewers, only %d available",
+			domain.ErrNoReviewerCandidates, team.Name, minReviewers, len(reviewers))
+		s.log.Warn("not enough reviewer candidates",
+			slog.String("pull_request_id", string(id)),
+			slog.String("team_name", team.Name),
+			slog.Int("min_reviewers", minReviewers),
+			slog.Int("available", len(reviewers)),
+			slog.String("error_code", ErrCodeNoReviewerCandidates),
+		)
+		return nil, err
+	}
 
 	pr := &domain.PullRequest{
 		ID:                id,
 		Name:              name,
 		AuthorID:          authorID,
 		Status:            domain.PRStatusOpen,
+		Labels:            attrs.Labels,
+		Priority:          attrs.Priority,
 		AssignedReviewers: reviewers,
-		CreatedAt:         time.Now().UTC(),
+		CreatedAt:         s.clock.Now(),
 	}
+	pick.annotate(pr)
 
-	if err := s.prs.Create(ctx, pr); err != nil {
-		s.log.Error("Create pull request failed",
+	return pr, nil
+}
+
+// pullRequestSeed derives a stable random seed from the pull request ID and,
+// optionally, the reviewers the selection starts from.
+func pullRequestSeed(id domain.PullRequestID, reviewers ...domain.UserID) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(id))
+	for _, r := range reviewers {
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(r))
+	}
+	return int64(h.Sum64())
+}
+
+// Get returns the pull request with its reviewers and dependency graph.
+// If it does not exist, domain.ErrNotFound is returned.
+func (s *PullRequestService) Get(ctx context.Context, id domain.PullRequestID) (*domain.PullRequest, error) {
+	pr, err := s.get(ctx, "Get", id)
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := s.prs.GetDependencyGraph(ctx, id)
+	if err != nil {
+		s.log.Error("GetDependencyGraph failed",
+			slog.String("pull_request_id", string(id)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+	pr.Dependencies = graph
+	return pr, nil
+}
+
+// get validates the ID and returns the pull request with its reviewers, logging
+// failures with the given operation name.
+func (s *PullRequestService) get(ctx context.Context, op string, id domain.PullRequestID) (*domain.PullRequest, error) {
+	if err := s.validateStatusChange(op, id); err != nil {
+		return nil, err
+	}
+
+	pr, err := s.prs.GetByID(ctx, id)
+	if err != nil {
+		s.log.Error("GetByID in "+op+" failed",
 			slog.String("pull_request_id", string(id)),
 			slog.String("error_code", ErrorCode(err)),
 			slog.Any("err", err),
 		)
 		return nil, err
 	}
+	return pr, nil
+}
+
+// History returns the events of the pull request in the order they happened.
+// If the pull request does not exist, domain.ErrNotFound is returned.
+func (s *PullRequestService) History(ctx context.Context, id domain.PullRequestID) ([]domain.PullRequestEvent, error) {
+	if _, err := s.get(ctx, "History", id); err != nil {
+		return nil, err
+	}
+
+	events, err := s.events.ListByPullRequest(ctx, id)
+	if err != nil {
+		s.log.Error("ListByPullRequest failed",
+			slog.String("pull_request_id", string(id)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+	return events, nil
+}
+
+// Update changes mutable metadata of the pull request, such as its name, labels
+// and priority, and returns the updated pull request. Merged pull requests cannot
+// be changed (domain.ErrPullRequestAlreadyMerged). An update without fields, with
+// an empty name, invalid labels or an unknown priority is rejected with
+// domain.ErrValidation. Reviewers already assigned are kept.
+func (s *PullRequestService) Update(
+	ctx context.Context,
+	id domain.PullRequestID,
+	upd domain.PullRequestUpdate,
+) (*domain.PullRequest, error) {
+	var reason string
+	switch {
+	case id == "":
+		reason = "empty pull_request_id"
+	case upd.IsEmpty():
+		reason = "nothing to update"
+	case upd.Name != nil && strings.TrimSpace(*upd.Name) == "":
+		reason = "empty pull_request_name"
+	case upd.Priority != nil && !upd.Priority.IsValid():
+		reason = fmt.Sprintf("unknown priority %q", *upd.Priority)
+	}
+	if reason != "" {
+		s.log.Warn("validate Update failed",
+			slog.String("error_code", ErrCodeValidation),
+			slog.String("reason", reason),
+			slog.String("pull_request_id", string(id)),
+		)
+		return nil, fmt.Errorf("%w: %s", domain.ErrValidation, reason)
+	}
+
+	if upd.Labels != nil {
+		labels, err := domain.NormalizeLabels(*upd.Labels)
+		if err != nil {
+			s.log.Warn("validate Update failed",
+				slog.String("error_code", ErrCodeValidation),
+				slog.String("reason", err.Error()),
+				slog.String("pull_request_id", string(id)),
+			)
+			return nil, err
+		}
+		upd.Labels = &labels
+	}
+
+	s.log.Info("updating pull request", slog.String("pull_request_id", string(id)))
 
+	pr, err := s.prs.UpdateMetadata(ctx, id, upd)
+	if err != nil {
+		s.log.Error("UpdateMetadata failed",
+			slog.String("pull_request_id", string(id)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
 	return pr, nil
 }
 
 // Merge marks a pull request as merged in an idempotent way.
+// If the author's team requires approvals and not enough assigned reviewers have
+// approved the pull request, domain.ErrNotApproved is returned; a pull request
+// with fewer reviewers than required needs approvals from all of them. While a pull request
+// it depends on is a draft or open, domain.ErrDependencyNotMerged is returned.
 // If the pull request is already merged, the existing state is returned without error.
 // If the pull request does not exist, domain.ErrNotFound is returned.
 func (s *PullRequestService) Merge(
 	ctx context.Context,
 	id domain.PullRequestID,
+) (*domain.PullRequest, error) {
+	return s.merge(ctx, id, false)
+}
+
+// ForceMerge is like Merge, but skips the required approvals check, while
+// unmerged dependencies still block it. It is meant for admins only, and a merge
+// that skipped missing approvals is logged and recorded for audit.
+func (s *PullRequestService) ForceMerge(
+	ctx context.Context,
+	id domain.PullRequestID,
+) (*domain.PullRequest, error) {
+	return s.merge(ctx, id, true)
+}
+
+func (s *PullRequestService) merge(
+	ctx context.Context,
+	id domain.PullRequestID,
+	force bool,
 ) (*domain.PullRequest, error) {
 	if id == "" {
 		err := fmt.Errorf("%w: pull_request_id is empty", domain.ErrValidation)
@@ -85,13 +341,20 @@ func (s *PullRequestService) Merge(
 		return nil, err
 	}
 
-	s.log.Info("merging pull request",
-		slog.String("pull_request_id", string(id)),
-	)
+	if force {
+		s.log.Warn("force merging pull request",
+			slog.String("pull_request_id", string(id)),
+			slog.Bool("audit", true),
+		)
+	} else {
+		s.log.Info("merging pull request",
+			slog.String("pull_request_id", string(id)),
+		)
+	}
 
-	now := time.Now().UTC()
+	now := s.clock.Now()
 
-	pr, err := s.prs.Merge(ctx, id, now)
+	pr, err := s.prs.Merge(ctx, id, now, force)
 	if err != nil {
 		s.log.Error("Merge failed",
 			slog.String("pull_request_id", string(id)),
@@ -114,14 +377,96 @@ func (s *PullRequestService) Merge(
 	return pr, nil
 }
 
+// SubmitReview stores the decision of an assigned reviewer on an OPEN pull request.
+// A repeated submission replaces the previous decision of the same reviewer.
+// Only APPROVED decisions of currently assigned reviewers count towards the
+// approvals required for merge.
+func (s *PullRequestService) SubmitReview(
+	ctx context.Context,
+	prID domain.PullRequestID,
+	reviewerID domain.UserID,
+	decision domain.ReviewDecision,
+) (*domain.Review, error) {
+	var reason string
+	switch {
+	case prID == "" || reviewerID == "":
+		reason = "empty pull_request_id or user_id"
+	case !decision.IsValid():
+		reason = "decision must be APPROVED, CHANGES_REQUESTED or COMMENTED"
+	}
+	if reason != "" {
+		s.log.Warn("validate SubmitReview failed",
+			slog.String("error_code", ErrCodeValidation),
+			slog.String("reason", reason),
+		)
+		return nil, fmt.Errorf("%w: %s", domain.ErrValidation, reason)
+	}
+
+	s.log.Info("submitting review",
+		slog.String("pull_request_id", string(prID)),
+		slog.String("reviewer_id", string(reviewerID)),
+		slog.String("decision", string(decision)),
+	)
+
+	review := &domain.Review{
+		PullRequestID: prID,
+		ReviewerID:    reviewerID,
+		Decision:      decision,
+		SubmittedAt:   s.clock.Now(),
+	}
+	if err := s.reviews.Save(ctx, review); err != nil {
+		s.log.Error("SubmitReview failed",
+			slog.String("pull_request_id", string(prID)),
+			slog.String("reviewer_id", string(reviewerID)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	return review, nil
+}
+
 // ReassignReviewer replaces an existing reviewer of a pull request with another
-// candidate from the same team. It skips inactive users, the author and already
-// assigned reviewers. If the pull request is merged, has no such reviewer or
-// there are no suitable candidates, a corresponding domain error is returned.
+// candidate from the same team, or from its fallback teams when the team has
+// none left. It skips inactive users, the author and already assigned reviewers.
+// If the pull request is merged, has no such reviewer or there are no suitable
+// candidates, a corresponding domain error is returned.
 func (s *PullRequestService) ReassignReviewer(
 	ctx context.Context,
 	prID domain.PullRequestID,
 	oldReviewerID domain.UserID,
+) (*domain.PullRequest, *domain.User, error) {
+	return s.reassign(ctx, prID, oldReviewerID, "")
+}
+
+// ReassignReviewerTo replaces an existing reviewer of a pull request with the given
+// user. The new reviewer must pass the same rules as automatic assignment: active,
+// not the author, not yet assigned, a member of the old reviewer's team or one of its
+// fallback teams, and below their review capacity (domain.ErrReviewerAtCapacity).
+func (s *PullRequestService) ReassignReviewerTo(
+	ctx context.Context,
+	prID domain.PullRequestID,
+	oldReviewerID domain.UserID,
+	newReviewerID domain.UserID,
+) (*domain.PullRequest, *domain.User, error) {
+	if newReviewerID == "" {
+		s.log.Warn("validate ReassignReviewerTo failed",
+			slog.String("error_code", ErrCodeValidation),
+			slog.String("reason", "empty new_user_id"),
+		)
+		return nil, nil, fmt.Errorf("%w: empty new_user_id", domain.ErrValidation)
+	}
+	return s.reassign(ctx, prID, oldReviewerID, newReviewerID)
+}
+
+// reassign implements ReassignReviewer and ReassignReviewerTo. An empty
+// newReviewerID lets the team's selector pick the replacement.
+func (s *PullRequestService) reassign(
+	ctx context.Context,
+	prID domain.PullRequestID,
+	oldReviewerID domain.UserID,
+	newReviewerID domain.UserID,
 ) (*domain.PullRequest, *domain.User, error) {
 	if prID == "" || oldReviewerID == "" {
 		err := fmt.Errorf("%w: empty prID or oldReviewerID", domain.ErrValidation)
@@ -135,24 +480,11 @@ func (s *PullRequestService) ReassignReviewer(
 	s.log.Info("reassigning reviewer",
 		slog.String("pull_request_id", string(prID)),
 		slog.String("old_reviewer_id", string(oldReviewerID)),
+		slog.String("new_reviewer_id", string(newReviewerID)),
 	)
 
-	pr, err := s.prs.GetByID(ctx, prID)
+	pr, err := s.getOpenPullRequest(ctx, "ReassignReviewer", prID)
 	if err != nil {
-		s.log.Error("GetByID in ReassignReviewer failed",
-			slog.String("pull_request_id", string(prID)),
-			slog.String("error_code", ErrorCode(err)),
-			slog.Any("err", err),
-		)
-		return nil, nil, err
-	}
-
-	if pr.IsMerged() {
-		err := domain.ErrPullRequestAlreadyMerged
-		s.log.Warn("ReassignReviewer on merged PR",
-			slog.String("pull_request_id", string(prID)),
-			slog.String("error_code", ErrCodePullRequestAlreadyMerged),
-		)
 		return nil, nil, err
 	}
 
@@ -184,24 +516,24 @@ func (s *PullRequestService) ReassignReviewer(
 		return nil, nil, err
 	}
 
-	candidates := make([]domain.User, 0)
-	for _, m := range team.Members {
-		if !m.IsActive {
-			continue
-		}
-		if m.ID == oldReviewerID {
-			continue
-		}
-		if m.ID == pr.AuthorID {
-			continue
-		}
-		if containsUserID(pr.AssignedReviewers, m.ID) {
-			continue
-		}
-		candidates = append(candidates, m)
+	var pick *reviewerPick
+	if newReviewerID == "" {
+		ctx = domain.WithDefaultReason(ctx, domain.ReasonAutomatic)
+		pick, err = s.forPullRequest(prID, oldReviewerID).
+			pickReviewersFromTeam(ctx, team, pr.AuthorID, pr.AssignedReviewers, 1)
+	} else {
+		pick, err = s.checkManualReviewer(ctx, pr, team, newReviewerID)
+	}
+	if err != nil {
+		s.log.Error("pick reviewer in ReassignReviewer failed",
+			slog.String("pull_request_id", string(prID)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, nil, err
 	}
 
-	if len(candidates) == 0 {
+	if len(pick.reviewers) == 0 {
 		err := domain.ErrNoReviewerCandidates
 		s.log.Warn("ReassignReviewer: no reviewer candidates",
 			slog.String("pull_request_id", string(prID)),
@@ -210,15 +542,15 @@ func (s *PullRequestService) ReassignReviewer(
 		)
 		return nil, nil, err
 	}
-
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	newIdx := r.Intn(len(candidates))
-	newReviewer := candidates[newIdx]
+	newReviewer := pick.reviewers[0]
 
 	pr.AssignedReviewers[foundIdx] = newReviewer.ID
+	delete(pr.FallbackReviewers, oldReviewerID)
+	delete(pr.DelegatedReviewers, oldReviewerID)
+	pick.annotate(pr)
 
-	if err := s.prs.Update(ctx, pr); err != nil {
-		s.log.Error("Update in ReassignReviewer failed",
+	if err := s.prs.ReplaceReviewer(ctx, pr, oldReviewerID, newReviewer.ID, s.clock.Now()); err != nil {
+		s.log.Error("ReplaceReviewer in ReassignReviewer failed",
 			slog.String("pull_request_id", string(prID)),
 			slog.String("error_code", ErrorCode(err)),
 			slog.Any("err", err),
@@ -229,36 +561,624 @@ func (s *PullRequestService) ReassignReviewer(
 	return pr, &newReviewer, nil
 }
 
+// AddReviewer manually assigns one more reviewer to an OPEN pull request.
+// The user must be active, must not be the author or already assigned, must belong
+// to the author's team or one of its fallback teams and must be below their review
+// capacity. Other assignments of the pull request are left untouched.
+func (s *PullRequestService) AddReviewer(
+	ctx context.Context,
+	prID domain.PullRequestID,
+	reviewerID domain.UserID,
+) (*domain.PullRequest, error) {
+	if err := s.validateReviewerChange("AddReviewer", prID, reviewerID); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("adding reviewer",
+		slog.String("pull_request_id", string(prID)),
+		slog.String("reviewer_id", string(reviewerID)),
+	)
+
+	pr, err := s.getOpenPullRequest(ctx, "AddReviewer", prID)
+	if err != nil {
+		return nil, err
+	}
+
+	team, err := s.teams.GetByMemberID(ctx, pr.AuthorID)
+	if err != nil {
+		s.log.Error("GetByMemberID in AddReviewer failed",
+			slog.String("pull_request_id", string(prID)),
+			slog.String("author_id", string(pr.AuthorID)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	pick, err := s.checkManualReviewer(ctx, pr, team, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	pr.AssignedReviewers = append(pr.AssignedReviewers, reviewerID)
+	pick.annotate(pr)
+
+	if err := s.prs.AddReviewer(ctx, pr, reviewerID, s.clock.Now()); err != nil {
+		s.log.Error("AddReviewer failed",
+			slog.String("pull_request_id", string(prID)),
+			slog.String("reviewer_id", string(reviewerID)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+// RemoveReviewer removes a reviewer from an OPEN pull request without picking a
+// replacement. If the user is not assigned, domain.ErrReviewerNotAssigned is returned.
+func (s *PullRequestService) RemoveReviewer(
+	ctx context.Context,
+	prID domain.PullRequestID,
+	reviewerID domain.UserID,
+) (*domain.PullRequest, error) {
+	if err := s.validateReviewerChange("RemoveReviewer", prID, reviewerID); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("removing reviewer",
+		slog.String("pull_request_id", string(prID)),
+		slog.String("reviewer_id", string(reviewerID)),
+	)
+
+	pr, err := s.getOpenPullRequest(ctx, "RemoveReviewer", prID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.prs.RemoveReviewer(ctx, pr, reviewerID); err != nil {
+		s.log.Error("RemoveReviewer failed",
+			slog.String("pull_request_id", string(prID)),
+			slog.String("reviewer_id", string(reviewerID)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	reviewers := make([]domain.UserID, 0, len(pr.AssignedReviewers))
+	for _, id := range pr.AssignedReviewers {
+		if id != reviewerID {
+			reviewers = append(reviewers, id)
+		}
+	}
+	pr.AssignedReviewers = reviewers
+	delete(pr.FallbackReviewers, reviewerID)
+	delete(pr.DelegatedReviewers, reviewerID)
+
+	return pr, nil
+}
+
+// validateReviewerChange checks IDs of a manual reviewer change and logs
+// validation errors with the given operation name.
+func (s *PullRequestService) validateReviewerChange(op string, prID domain.PullRequestID, reviewerID domain.UserID) error {
+	if prID == "" || reviewerID == "" {
+		s.log.Warn("validate "+op+" failed",
+			slog.String("error_code", ErrCodeValidation),
+			slog.String("reason", "empty pull_request_id or user_id"),
+		)
+		return fmt.Errorf("%w: empty pull_request_id or user_id", domain.ErrValidation)
+	}
+	return nil
+}
+
+// getOpenPullRequest loads the pull request and returns
+// domain.ErrPullRequestAlreadyMerged if it is merged or
+// domain.ErrPullRequestNotOpen if it is a draft or closed.
+func (s *PullRequestService) getOpenPullRequest(
+	ctx context.Context,
+	op string,
+	prID domain.PullRequestID,
+) (*domain.PullRequest, error) {
+	pr, err := s.prs.GetByID(ctx, prID)
+	if err != nil {
+		s.log.Error("GetByID in "+op+" failed",
+			slog.String("pull_request_id", string(prID)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	if pr.IsMerged() {
+		s.log.Warn(op+" on merged PR",
+			slog.String("pull_request_id", string(prID)),
+			slog.String("error_code", ErrCodePullRequestAlreadyMerged),
+		)
+		return nil, domain.ErrPullRequestAlreadyMerged
+	}
+	if pr.Status != domain.PRStatusOpen {
+		s.log.Warn(op+" on PR that is not open",
+			slog.String("pull_request_id", string(prID)),
+			slog.String("status", string(pr.Status)),
+			slog.String("error_code", ErrCodePullRequestNotOpen),
+		)
+		return nil, fmt.Errorf("%w: status %s", domain.ErrPullRequestNotOpen, pr.Status)
+	}
+
+	return pr, nil
+}
+
+// checkManualReviewer verifies that the user may be assigned to the pull request by
+// hand: active, not the author, not yet assigned, a member of the team or one of its
+// fallback teams, and below their review capacity. On success it returns a pick
+// with this single reviewer.
+func (s *PullRequestService) checkManualReviewer(
+	ctx context.Context,
+	pr *domain.PullRequest,
+	team *domain.Team,
+	userID domain.UserID,
+) (*reviewerPick, error) {
+	reject := func(err error, reason string) (*reviewerPick, error) {
+		s.log.Warn("manual reviewer rejected",
+			slog.String("pull_request_id", string(pr.ID)),
+			slog.String("reviewer_id", string(userID)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.String("reason", reason),
+		)
+		return nil, err
+	}
+
+	if userID == pr.AuthorID {
+		return reject(fmt.Errorf("%w: author cannot review own pull request", domain.ErrValidation), "author")
+	}
+	if containsUserID(pr.AssignedReviewers, userID) {
+		return reject(domain.ErrReviewerAlreadyAssigned, "already assigned")
+	}
+
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return reject(fmt.Errorf("%w: user %s is inactive", domain.ErrValidation, userID), "inactive")
+	}
+
+	userTeam, err := s.teams.GetByMemberID(ctx, userID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	pick := &reviewerPick{reviewers: []domain.User{*user}}
+	switch {
+	case userTeam != nil && userTeam.Name == team.Name:
+	case userTeam != nil && containsString(team.Settings.FallbackTeams, userTeam.Name):
+		pick.fallback = map[domain.UserID]string{userID: userTeam.Name}
+	default:
+		return reject(fmt.Errorf("%w: user %s is not in team %s or its fallback teams",
+			domain.ErrValidation, userID, team.Name), "foreign team")
+	}
+
+	if user.MaxOpenReviews != nil {
+		load, err := s.prs.GetOpenReviewCounts(ctx, []domain.UserID{userID})
+		if err != nil {
+			return nil, err
+		}
+		if user.AtCapacity(load[userID]) {
+			return reject(fmt.Errorf("%w: user %s already reviews %d open pull requests",
+				domain.ErrReviewerAtCapacity, userID, load[userID]), "at capacity")
+		}
+	}
+
+	return pick, nil
+}
+
+// containsString reports whether the given string is present in the list.
+func containsString(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// reviewerPick is the outcome of reviewer selection for a pull request.
+type reviewerPick struct {
+	reviewers []domain.User
+	// fallback maps reviewers taken from a fallback team to that team's name.
+	fallback map[domain.UserID]string
+	// delegated maps reviewers picked as delegates to the absent users they replace.
+	delegated map[domain.UserID]domain.UserID
+	// explanation records the decision about every candidate considered.
+	explanation []domain.CandidateExplanation
+
+	// available and delegateOf hold the candidate pool of the last team picked from.
+	available  []domain.User
+	delegateOf map[domain.UserID]domain.User
+}
+
+// explain records why each member of the team was picked or skipped, followed by
+// picked delegates from outside the team. It must be called right after picking
+// from that team; exclude is the exclude list used for it.
+func (p *reviewerPick) explain(team *domain.Team, authorID domain.UserID, exclude []domain.UserID) {
+	picked := userIDs(p.reviewers)
+	available := userIDs(p.available)
+
+	for _, m := range team.Members {
+		e := domain.CandidateExplanation{UserID: m.ID, TeamName: team.Name}
+
+		switch {
+		case m.ID == authorID:
+			e.Reason = domain.AssignmentReasonAuthor
+		case containsUserID(exclude, m.ID):
+			e.Reason = domain.AssignmentReasonAlreadyAssigned
+		case !m.IsActive:
+			e.Reason = domain.AssignmentReasonInactive
+		case containsUserID(picked, m.ID):
+			e.Picked = true
+			e.Reason = domain.AssignmentReasonSelected
+			if from, ok := p.delegated[m.ID]; ok {
+				e.Reason = domain.AssignmentReasonDelegate
+				e.DelegatedFrom = from
+			}
+		case m.Absence != nil || m.Delegation != nil:
+			e.Reason = domain.AssignmentReasonOutOfOffice
+			if d, ok := p.delegateOf[m.ID]; ok {
+				e.DelegateID = d.ID
+			}
+		case !containsUserID(available, m.ID):
+			e.Reason = domain.AssignmentReasonAtCapacity
+		default:
+			e.Reason = domain.AssignmentReasonNotSelected
+		}
+
+		p.explanation = append(p.explanation, e)
+	}
+
+	members := userIDs(team.Members)
+	for _, rid := range picked {
+		from, ok := p.delegated[rid]
+		if !ok || containsUserID(members, rid) {
+			continue
+		}
+		p.explanation = append(p.explanation, domain.CandidateExplanation{
+			UserID:        rid,
+			Picked:        true,
+			Reason:        domain.AssignmentReasonDelegate,
+			DelegatedFrom: from,
+		})
+	}
+}
+
+// fillDelegating appends ranked candidates up to maxCount, swapping absent users
+// for their delegates and skipping users already picked.
+func (p *reviewerPick) fillDelegating(ranked []domain.User, delegateOf map[domain.UserID]domain.User, maxCount int) {
+	for _, u := range ranked {
+		if len(p.reviewers) >= maxCount {
+			return
+		}
+
+		from := u.ID
+		if delegate, ok := delegateOf[u.ID]; ok {
+			u = delegate
+		}
+		if containsUserID(userIDs(p.reviewers), u.ID) {
+			continue
+		}
+
+		if from != u.ID {
+			if p.delegated == nil {
+				p.delegated = make(map[domain.UserID]domain.UserID)
+			}
+			p.delegated[u.ID] = from
+		}
+		p.reviewers = append(p.reviewers, u)
+	}
+}
+
+// annotate records fallback and delegation details of the picked reviewers and
+// the assignment explanation on the pull request.
+func (p *reviewerPick) annotate(pr *domain.PullRequest) {
+	pr.AssignmentExplanation = p.explanation
+	for _, u := range p.reviewers {
+		if team, ok := p.fallback[u.ID]; ok {
+			if pr.FallbackReviewers == nil {
+				pr.FallbackReviewers = make(map[domain.UserID]string, len(p.fallback))
+			}
+			pr.FallbackReviewers[u.ID] = team
+		}
+		if from, ok := p.delegated[u.ID]; ok {
+			if pr.DelegatedReviewers == nil {
+				pr.DelegatedReviewers = make(map[domain.UserID]domain.UserID, len(p.delegated))
+			}
+			pr.DelegatedReviewers[u.ID] = from
+		}
+	}
+}
+
 // pickReviewersFromTeam selects up to maxCount active team members as reviewers,
-// excluding the author. If there are fewer candidates than maxCount, all of them
-// are returned. Selection is randomized to avoid always picking the same users.
-func pickReviewersFromTeam(team *domain.Team, authorID domain.UserID, maxCount int) []domain.UserID {
-	candidates := make([]domain.UserID, 0, len(team.Members))
+// skipping the author and already assigned reviewers. When the team cannot fill all
+// slots, candidates are taken from the team's fallback teams in the configured order
+// until maxCount is met. The choice among candidates is delegated to the team's
+// ReviewerSelector. Every member considered is recorded in the pick's explanation.
+func (s *PullRequestService) pickReviewersFromTeam(
+	ctx context.Context,
+	team *domain.Team,
+	authorID domain.UserID,
+	assigned []domain.UserID,
+	maxCount int,
+) (*reviewerPick, error) {
+	exclude := append([]domain.UserID{authorID}, assigned...)
+
+	pick, err := s.pickFromSingleTeam(ctx, team.Settings.Strategy, team, exclude, maxCount)
+	if err != nil {
+		return nil, err
+	}
+	pick.explain(team, authorID, exclude)
+
+	for _, name := range team.Settings.FallbackTeams {
+		if len(pick.reviewers) >= maxCount {
+			break
+		}
+
+		fallbackTeam, err := s.teams.GetByName(ctx, name)
+		if errors.Is(err, domain.ErrNotFound) {
+			s.log.Warn("fallback team not found",
+				slog.String("team_name", team.Name),
+				slog.String("fallback_team", name),
+			)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get fallback team %s: %w", name, err)
+		}
+
+		taken := append(append([]domain.UserID(nil), exclude...), userIDs(pick.reviewers)...)
+		extra, err := s.pickFromSingleTeam(ctx, team.Settings.Strategy, fallbackTeam, taken, maxCount-len(pick.reviewers))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, u := range extra.reviewers {
+			if pick.fallback == nil {
+				pick.fallback = make(map[domain.UserID]string)
+			}
+			pick.fallback[u.ID] = fallbackTeam.Name
+		}
+		for rid, from := range extra.delegated {
+			if pick.delegated == nil {
+				pick.delegated = make(map[domain.UserID]domain.UserID)
+			}
+			pick.delegated[rid] = from
+		}
+		pick.reviewers = append(pick.reviewers, extra.reviewers...)
+		extra.explain(fallbackTeam, authorID, taken)
+		pick.explanation = append(pick.explanation, extra.explanation...)
+	}
+
+	return pick, nil
+}
+
+// pickFromSingleTeam selects up to count reviewers among available members of
+// one team. An absent member who is picked is swapped for their delegate.
+func (s *PullRequestService) pickFromSingleTeam(
+	ctx context.Context,
+	strategy domain.ReviewerStrategy,
+	team *domain.Team,
+	exclude []domain.UserID,
+	count int,
+) (*reviewerPick, error) {
+	candidates, delegateOf, err := s.availableCandidates(ctx, team, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	pick := &reviewerPick{available: candidates, delegateOf: delegateOf}
+
+	if len(delegateOf) == 0 {
+		picked, err := s.selectReviewers(ctx, strategy, candidates, count)
+		if err != nil {
+			return nil, err
+		}
+		pick.reviewers = picked
+		return pick, nil
+	}
+
+	// Rank everyone so that a slot lost to a duplicate (a delegate picked both
+	// directly and for an absent teammate) goes to the next candidate.
+	ranked, err := s.selectorFor(strategy).Select(ctx, candidates, len(candidates))
+	if err != nil {
+		return nil, err
+	}
+	pick.fillDelegating(ranked, delegateOf, count)
+
+	return pick, nil
+}
+
+// selectReviewers returns up to count users out of candidates using the given strategy,
+// or the deployment default when it is empty. The selector is consulted only when
+// there are more candidates than free slots.
+func (s *PullRequestService) selectReviewers(
+	ctx context.Context,
+	strategy domain.ReviewerStrategy,
+	candidates []domain.User,
+	count int,
+) ([]domain.User, error) {
+	if len(candidates) <= count {
+		return candidates, nil
+	}
+	return s.selectorFor(strategy).Select(ctx, candidates, count)
+}
+
+// selectorFor returns a selector for a team-level strategy override.
+// Empty or unknown strategies resolve to the deployment default selector.
+func (s *PullRequestService) selectorFor(strategy domain.ReviewerStrategy) ReviewerSelector {
+	if !strategy.IsValid() {
+		return s.selector
+	}
+	return newReviewerSelector(strategy, s.prs, s.rng)
+}
+
+// forPullRequest returns the service to select reviewers of the given pull request
+// with. When seeding by pull request is enabled it is a copy seeded from the ID
+// and reviewers, otherwise s itself.
+func (s *PullRequestService) forPullRequest(id domain.PullRequestID, reviewers ...domain.UserID) *PullRequestService {
+	if !s.seedByID {
+		return s
+	}
+	return s.seeded(pullRequestSeed(id, reviewers...))
+}
 
+// seeded returns a copy of the service whose random choices come from a generator
+// seeded with the given value, so that reviewer selection is reproducible.
+func (s *PullRequestService) seeded(seed int64) *PullRequestService {
+	c := *s
+	c.rng = rand.New(rand.NewSource(seed))
+	c.selector = newReviewerSelector(s.strategy, s.prs, c.rng)
+	return &c
+}
+
+// withRepositories returns a copy of the service that works through repos, such
+// as repositories bound to a transaction.
+func (s *PullRequestService) withRepositories(repos *repository.Repositories) *PullRequestService {
+	c := *s
+	c.users = repos.Users
+	c.teams = repos.Teams
+	c.prs = repos.PullRequests
+	c.reviews = repos.Reviews
+	c.events = repos.Events
+	c.selector = newReviewerSelector(s.strategy, repos.PullRequests, s.rng)
+	return &c
+}
+
+// availableCandidates returns eligible members of the team that still have
+// review capacity left. Members with a delegation in effect whose delegate is
+// eligible stay in the list as stand-ins; the returned map links them to their
+// delegates so the delegate can take the slot when the stand-in is picked.
+func (s *PullRequestService) availableCandidates(
+	ctx context.Context,
+	team *domain.Team,
+	exclude []domain.UserID,
+) ([]domain.User, map[domain.UserID]domain.User, error) {
+	candidates := eligibleCandidates(team, exclude)
+
+	var standIns []domain.User
+	var delegates []domain.User
 	for _, m := range team.Members {
-		if !m.IsActive {
+		if !m.IsActive || m.Delegation == nil || containsUserID(exclude, m.ID) {
 			continue
 		}
-		if m.ID == authorID {
+		if containsUserID(exclude, m.Delegation.ToUserID) {
+			continue
+		}
+
+		delegate, err := s.delegateUser(ctx, team, m.Delegation.ToUserID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if delegate == nil || !delegate.IsAvailable() {
+			continue
+		}
+
+		standIns = append(standIns, m)
+		delegates = append(delegates, *delegate)
+	}
+
+	candidates, err := withoutUsersAtCapacity(ctx, s.prs, candidates)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(standIns) == 0 {
+		return candidates, nil, nil
+	}
+
+	freeDelegates, err := withoutUsersAtCapacity(ctx, s.prs, delegates)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	delegateOf := make(map[domain.UserID]domain.User, len(standIns))
+	for i, m := range standIns {
+		if !containsUserID(userIDs(freeDelegates), delegates[i].ID) {
 			continue
 		}
-		candidates = append(candidates, m.ID)
+		candidates = append(candidates, m)
+		delegateOf[m.ID] = delegates[i]
 	}
+	return candidates, delegateOf, nil
+}
 
-	if len(candidates) == 0 {
-		return nil
+// delegateUser returns the delegate, looking in the team first. It returns nil
+// if the delegate no longer exists.
+func (s *PullRequestService) delegateUser(
+	ctx context.Context,
+	team *domain.Team,
+	id domain.UserID,
+) (*domain.User, error) {
+	for i := range team.Members {
+		if team.Members[i].ID == id {
+			return &team.Members[i], nil
+		}
 	}
 
-	if len(candidates) <= maxCount {
-		return candidates
+	u, err := s.users.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
 	}
+	if err != nil {
+		return nil, fmt.Errorf("get delegate %s: %w", id, err)
+	}
+	return u, nil
+}
 
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	r.Shuffle(len(candidates), func(i, j int) {
-		candidates[i], candidates[j] = candidates[j], candidates[i]
-	})
+// withoutUsersAtCapacity drops candidates whose open reviews already reach their
+// max_open_reviews limit. Open review counts are only loaded for limited users.
+func withoutUsersAtCapacity(
+	ctx context.Context,
+	prs repository.PullRequestRepository,
+	candidates []domain.User,
+) ([]domain.User, error) {
+	limited := make([]domain.UserID, 0)
+	for _, u := range candidates {
+		if u.MaxOpenReviews != nil {
+			limited = append(limited, u.ID)
+		}
+	}
+	if len(limited) == 0 {
+		return candidates, nil
+	}
 
-	return candidates[:maxCount]
+	load, err := prs.GetOpenReviewCounts(ctx, limited)
+	if err != nil {
+		return nil, fmt.Errorf("get open review counts: %w", err)
+	}
+
+	available := make([]domain.User, 0, len(candidates))
+	for _, u := range candidates {
+		if u.AtCapacity(load[u.ID]) {
+			continue
+		}
+		available = append(available, u)
+	}
+	return available, nil
+}
+
+// eligibleCandidates returns available (active and not absent) team members
+// that are not in the exclude list.
+func eligibleCandidates(team *domain.Team, exclude []domain.UserID) []domain.User {
+	candidates := make([]domain.User, 0, len(team.Members))
+	for _, m := range team.Members {
+		if !m.IsAvailable() {
+			continue
+		}
+		if containsUserID(exclude, m.ID) {
+			continue
+		}
+		candidates = append(candidates, m)
+	}
+	return candidates
 }
 
 // containsUserID reports whether the given user ID is present in the list.
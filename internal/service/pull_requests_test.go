@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
+	"math/rand"
 	"reflect"
 	"testing"
 	"time"
@@ -12,6 +14,7 @@ import (
 	"github.com/golang/mock/gomock"
 
 	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/repository"
 	"github.com/juzu400/avito-internship/internal/repository/mocks"
 )
 
@@ -19,6 +22,25 @@ func newTestLogger() *slog.Logger {
 	return slog.New(slog.NewTextHandler(io.Discard, nil))
 }
 
+// newTestPullRequestService builds a PullRequestService the way NewServices does,
+// so tests can pin the clock and the random source through cfg.
+func newTestPullRequestService(
+	users repository.UserRepository,
+	teams repository.TeamRepository,
+	prs repository.PullRequestRepository,
+	cfg Config,
+) *PullRequestService {
+	return newPullRequestService(newTestLogger(), &repository.Repositories{
+		Users:        users,
+		Teams:        teams,
+		PullRequests: prs,
+	}, cfg)
+}
+
+func fixedClock(t time.Time) func() time.Time {
+	return func() time.Time { return t }
+}
+
 func TestPullRequestService_Create_AssignsReviewersFromTeam(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -46,13 +68,16 @@ func TestPullRequestService_Create_AssignsReviewersFromTeam(t *testing.T) {
 	prRepo.
 		EXPECT().
 		Create(gomock.Any(), gomock.AssignableToTypeOf(&domain.PullRequest{})).
-		DoAndReturn(func(_ context.Context, pr *domain.PullRequest) error {
+		DoAndReturn(func(ctx context.Context, pr *domain.PullRequest) error {
 			if pr.Status != domain.PRStatusOpen {
 				t.Errorf("expected status %q, got %q", domain.PRStatusOpen, pr.Status)
 			}
 			if pr.AuthorID != authorID {
 				t.Errorf("expected author %q, got %q", authorID, pr.AuthorID)
 			}
+			if actor := domain.AuditFromContext(ctx).ActorID; actor != authorID {
+				t.Errorf("expected author to be the actor, got %q", actor)
+			}
 			if len(pr.AssignedReviewers) == 0 || len(pr.AssignedReviewers) > 2 {
 				t.Errorf("expected 1-2 reviewers, got %d", len(pr.AssignedReviewers))
 			}
@@ -77,7 +102,7 @@ func TestPullRequestService_Create_AssignsReviewersFromTeam(t *testing.T) {
 	ctx := context.Background()
 	prID := domain.PullRequestID("pr-1")
 
-	pr, err := svc.Create(ctx, prID, "Test PR", authorID)
+	pr, err := svc.Create(ctx, prID, "Test PR", authorID, domain.PullRequestAttributes{})
 	if err != nil {
 		t.Fatalf("Create returned error: %v", err)
 	}
@@ -90,6 +115,551 @@ func TestPullRequestService_Create_AssignsReviewersFromTeam(t *testing.T) {
 	}
 }
 
+func TestPullRequestService_Create_UsesTeamReviewersCount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+
+	authorID := domain.UserID("author")
+
+	team := &domain.Team{
+		Name: "platform",
+		Members: []domain.User{
+			{ID: authorID, Username: "Author", IsActive: true},
+			{ID: "u1", Username: "U1", IsActive: true},
+			{ID: "u2", Username: "U2", IsActive: true},
+			{ID: "u3", Username: "U3", IsActive: true},
+			{ID: "u4", Username: "U4", IsActive: true},
+		},
+		Settings: domain.TeamSettings{ReviewersCount: 3, MinReviewers: 3},
+	}
+
+	teamRepo.EXPECT().
+		GetByMemberID(gomock.Any(), authorID).
+		Return(team, nil)
+
+	prRepo.EXPECT().
+		Create(gomock.Any(), gomock.AssignableToTypeOf(&domain.PullRequest{})).
+		Return(nil)
+
+	svc := &PullRequestService{
+		log:      newTestLogger(),
+		teams:    teamRepo,
+		prs:      prRepo,
+		selector: randomSelector{},
+	}
+
+	pr, err := svc.Create(context.Background(), "pr-1", "Test PR", authorID, domain.PullRequestAttributes{})
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if len(pr.AssignedReviewers) != 3 {
+		t.Fatalf("expected 3 reviewers, got %d", len(pr.AssignedReviewers))
+	}
+}
+
+func TestPullRequestService_Create_NotEnoughReviewersForMinimum(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+
+	authorID := domain.UserID("author")
+
+	team := &domain.Team{
+		Name: "platform",
+		Members: []domain.User{
+			{ID: authorID, Username: "Author", IsActive: true},
+			{ID: "u1", Username: "U1", IsActive: true},
+			{ID: "u2", Username: "U2", IsActive: false},
+		},
+		Settings: domain.TeamSettings{ReviewersCount: 2, MinReviewers: 2},
+	}
+
+	teamRepo.EXPECT().
+		GetByMemberID(gomock.Any(), authorID).
+		Return(team, nil)
+
+	svc := &PullRequestService{
+		log:   newTestLogger(),
+		teams: teamRepo,
+		prs:   prRepo,
+	}
+
+	_, err := svc.Create(context.Background(), "pr-1", "Test PR", authorID, domain.PullRequestAttributes{})
+	if !errors.Is(err, domain.ErrNoReviewerCandidates) {
+		t.Fatalf("expected ErrNoReviewerCandidates, got %v", err)
+	}
+}
+
+func TestPullRequestService_Create_ReviewRulesSetReviewersCount(t *testing.T) {
+	authorID := domain.UserID("author")
+	rules := []domain.ReviewRule{
+		{Label: "hotfix", ReviewersCount: 3, RequiredApprovals: 2},
+		{Label: "typo", ReviewersCount: 1, RequiredApprovals: 1},
+	}
+
+	tests := []struct {
+		name          string
+		labels        []string
+		wantLabels    []string
+		wantReviewers int
+	}{
+		{name: "no matching rule", labels: nil, wantLabels: []string{}, wantReviewers: 2},
+		{name: "rule below the minimum", labels: []string{" Typo "}, wantLabels: []string{"typo"}, wantReviewers: 1},
+		{name: "strictest rule wins", labels: []string{"typo", "hotfix"}, wantLabels: []string{"hotfix", "typo"}, wantReviewers: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			teamRepo := mocks.NewMockTeamRepository(ctrl)
+			prRepo := mocks.NewMockPullRequestRepository(ctrl)
+
+			team := &domain.Team{
+				Name: "platform",
+				Members: []domain.User{
+					{ID: authorID, Username: "Author", IsActive: true},
+					{ID: "u1", Username: "U1", IsActive: true},
+					{ID: "u2", Username: "U2", IsActive: true},
+					{ID: "u3", Username: "U3", IsActive: true},
+				},
+				Settings: domain.TeamSettings{ReviewersCount: 2, MinReviewers: 2, ReviewRules: rules},
+			}
+
+			teamRepo.EXPECT().
+				GetByMemberID(gomock.Any(), authorID).
+				Return(team, nil)
+			prRepo.EXPECT().
+				Create(gomock.Any(), gomock.AssignableToTypeOf(&domain.PullRequest{})).
+				Return(nil)
+
+			svc := &PullRequestService{
+				log:      newTestLogger(),
+				teams:    teamRepo,
+				prs:      prRepo,
+				selector: randomSelector{},
+			}
+
+			pr, err := svc.Create(context.Background(), "pr-1", "Test PR", authorID,
+				domain.PullRequestAttributes{Labels: tt.labels})
+			if err != nil {
+				t.Fatalf("Create returned error: %v", err)
+			}
+			if len(pr.AssignedReviewers) != tt.wantReviewers {
+				t.Fatalf("expected %d reviewers, got %v", tt.wantReviewers, pr.AssignedReviewers)
+			}
+			if !reflect.DeepEqual(pr.Labels, tt.wantLabels) {
+				t.Fatalf("expected labels %v, got %v", tt.wantLabels, pr.Labels)
+			}
+			if pr.Priority != domain.PriorityNormal {
+				t.Fatalf("expected default priority %q, got %q", domain.PriorityNormal, pr.Priority)
+			}
+		})
+	}
+}
+
+func TestPullRequestService_Create_FillsFromFallbackTeams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+
+	authorID := domain.UserID("author")
+
+	team := &domain.Team{
+		Name: "mobile",
+		Members: []domain.User{
+			{ID: authorID, Username: "Author", IsActive: true},
+			{ID: "u1", Username: "U1", IsActive: true},
+			{ID: "u2", Username: "U2", IsActive: false},
+		},
+		Settings: domain.TeamSettings{ReviewersCount: 2, FallbackTeams: []string{"gone", "backend"}},
+	}
+	fallback := &domain.Team{
+		Name: "backend",
+		Members: []domain.User{
+			{ID: "b1", Username: "B1", IsActive: true},
+		},
+	}
+
+	teamRepo.EXPECT().
+		GetByMemberID(gomock.Any(), authorID).
+		Return(team, nil)
+	teamRepo.EXPECT().
+		GetByName(gomock.Any(), "gone").
+		Return(nil, domain.ErrNotFound)
+	teamRepo.EXPECT().
+		GetByName(gomock.Any(), "backend").
+		Return(fallback, nil)
+
+	prRepo.EXPECT().
+		Create(gomock.Any(), gomock.AssignableToTypeOf(&domain.PullRequest{})).
+		Return(nil)
+
+	svc := &PullRequestService{
+		log:   newTestLogger(),
+		teams: teamRepo,
+		prs:   prRepo,
+	}
+
+	pr, err := svc.Create(context.Background(), "pr-1", "Test PR", authorID, domain.PullRequestAttributes{})
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+
+	want := []domain.UserID{"u1", "b1"}
+	if !reflect.DeepEqual(pr.AssignedReviewers, want) {
+		t.Fatalf("expected reviewers %v, got %v", want, pr.AssignedReviewers)
+	}
+	wantFallback := map[domain.UserID]string{"b1": "backend"}
+	if !reflect.DeepEqual(pr.FallbackReviewers, wantFallback) {
+		t.Fatalf("expected fallback reviewers %v, got %v", wantFallback, pr.FallbackReviewers)
+	}
+}
+
+func TestPullRequestService_Create_SkipsReviewersAtCapacity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+
+	authorID := domain.UserID("author")
+	one, two := 1, 2
+
+	team := &domain.Team{
+		Name: "platform",
+		Members: []domain.User{
+			{ID: authorID, Username: "Author", IsActive: true},
+			{ID: "lead", Username: "Lead", IsActive: true, MaxOpenReviews: &one},
+			{ID: "oncall", Username: "OnCall", IsActive: true, MaxOpenReviews: &two},
+			{ID: "u1", Username: "U1", IsActive: true},
+		},
+	}
+
+	teamRepo.EXPECT().
+		GetByMemberID(gomock.Any(), authorID).
+		Return(team, nil)
+
+	prRepo.EXPECT().
+		GetOpenReviewCounts(gomock.Any(), []domain.UserID{"lead", "oncall"}).
+		Return(map[domain.UserID]int{"lead": 1, "oncall": 1}, nil)
+
+	prRepo.EXPECT().
+		Create(gomock.Any(), gomock.AssignableToTypeOf(&domain.PullRequest{})).
+		Return(nil)
+
+	svc := &PullRequestService{
+		log:   newTestLogger(),
+		teams: teamRepo,
+		prs:   prRepo,
+	}
+
+	pr, err := svc.Create(context.Background(), "pr-1", "Test PR", authorID, domain.PullRequestAttributes{})
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	for _, id := range pr.AssignedReviewers {
+		if id == "lead" {
+			t.Fatalf("reviewer at capacity must not be assigned, got %v", pr.AssignedReviewers)
+		}
+	}
+	if len(pr.AssignedReviewers) != 2 {
+		t.Fatalf("expected 2 reviewers, got %v", pr.AssignedReviewers)
+	}
+}
+
+func TestPullRequestService_Create_SkipsAbsentReviewers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+
+	authorID := domain.UserID("author")
+	now := time.Now().UTC()
+
+	team := &domain.Team{
+		Name: "platform",
+		Members: []domain.User{
+			{ID: authorID, Username: "Author", IsActive: true},
+			{ID: "away", Username: "Away", IsActive: true, Absence: &domain.Absence{
+				UserID:   "away",
+				StartsAt: now.Add(-time.Hour),
+				EndsAt:   now.Add(time.Hour),
+			}},
+			{ID: "u1", Username: "U1", IsActive: true},
+		},
+	}
+
+	teamRepo.EXPECT().
+		GetByMemberID(gomock.Any(), authorID).
+		Return(team, nil)
+
+	prRepo.EXPECT().
+		Create(gomock.Any(), gomock.AssignableToTypeOf(&domain.PullRequest{})).
+		Return(nil)
+
+	svc := &PullRequestService{
+		log:   newTestLogger(),
+		teams: teamRepo,
+		prs:   prRepo,
+	}
+
+	pr, err := svc.Create(context.Background(), "pr-1", "Test PR", authorID, domain.PullRequestAttributes{})
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if len(pr.AssignedReviewers) != 1 || pr.AssignedReviewers[0] != "u1" {
+		t.Fatalf("expected only u1 to be assigned, got %v", pr.AssignedReviewers)
+	}
+}
+
+func TestPullRequestService_Create_SwapsAbsentReviewerForDelegate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+
+	authorID := domain.UserID("author")
+	now := time.Now().UTC()
+
+	team := &domain.Team{
+		Name: "platform",
+		Members: []domain.User{
+			{ID: authorID, Username: "Author", IsActive: true},
+			{ID: "away", Username: "Away", IsActive: true, Delegation: &domain.Delegation{
+				FromUserID: "away",
+				ToUserID:   "pair",
+				StartsAt:   now.Add(-time.Hour),
+				EndsAt:     now.Add(time.Hour),
+			}},
+			{ID: "u1", Username: "U1", IsActive: true},
+		},
+	}
+
+	teamRepo.EXPECT().
+		GetByMemberID(gomock.Any(), authorID).
+		Return(team, nil)
+
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), domain.UserID("pair")).
+		Return(&domain.User{ID: "pair", Username: "Pair", IsActive: true}, nil)
+
+	prRepo.EXPECT().
+		Create(gomock.Any(), gomock.AssignableToTypeOf(&domain.PullRequest{})).
+		Return(nil)
+
+	svc := &PullRequestService{
+		log:      newTestLogger(),
+		users:    userRepo,
+		teams:    teamRepo,
+		prs:      prRepo,
+		selector: randomSelector{},
+	}
+
+	pr, err := svc.Create(context.Background(), "pr-1", "Test PR", authorID, domain.PullRequestAttributes{})
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if len(pr.AssignedReviewers) != 2 {
+		t.Fatalf("expected 2 reviewers, got %v", pr.AssignedReviewers)
+	}
+	for _, id := range pr.AssignedReviewers {
+		if id == "away" {
+			t.Fatalf("absent reviewer must not be assigned, got %v", pr.AssignedReviewers)
+		}
+	}
+	if from := pr.DelegatedReviewers["pair"]; from != "away" {
+		t.Fatalf("expected pair to be recorded as delegate of away, got %v", pr.DelegatedReviewers)
+	}
+}
+
+func TestPullRequestService_Create_DelegateAlreadyPickedDirectly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+
+	authorID := domain.UserID("author")
+	now := time.Now().UTC()
+
+	team := &domain.Team{
+		Name: "platform",
+		Members: []domain.User{
+			{ID: authorID, Username: "Author", IsActive: true},
+			{ID: "away", Username: "Away", IsActive: true, Delegation: &domain.Delegation{
+				FromUserID: "away",
+				ToUserID:   "pair",
+				StartsAt:   now.Add(-time.Hour),
+				EndsAt:     now.Add(time.Hour),
+			}},
+			{ID: "pair", Username: "Pair", IsActive: true},
+			{ID: "u1", Username: "U1", IsActive: true},
+		},
+		Settings: domain.TeamSettings{ReviewersCount: 2},
+	}
+
+	teamRepo.EXPECT().
+		GetByMemberID(gomock.Any(), authorID).
+		Return(team, nil).
+		AnyTimes()
+
+	prRepo.EXPECT().
+		Create(gomock.Any(), gomock.AssignableToTypeOf(&domain.PullRequest{})).
+		Return(nil).
+		AnyTimes()
+
+	svc := &PullRequestService{
+		log:      newTestLogger(),
+		teams:    teamRepo,
+		prs:      prRepo,
+		selector: randomSelector{},
+	}
+
+	for i := 0; i < 20; i++ {
+		pr, err := svc.Create(context.Background(), "pr-1", "Test PR", authorID, domain.PullRequestAttributes{})
+		if err != nil {
+			t.Fatalf("Create returned error: %v", err)
+		}
+		if len(pr.AssignedReviewers) != 2 || pr.AssignedReviewers[0] == pr.AssignedReviewers[1] {
+			t.Fatalf("expected 2 distinct reviewers, got %v", pr.AssignedReviewers)
+		}
+		for _, id := range pr.AssignedReviewers {
+			if id == "away" {
+				t.Fatalf("absent reviewer must not be assigned, got %v", pr.AssignedReviewers)
+			}
+		}
+	}
+}
+
+func TestPullRequestService_Create_ExplainsEveryCandidate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+
+	authorID := domain.UserID("author")
+	now := time.Now().UTC()
+	zero := 0
+
+	team := &domain.Team{
+		Name: "platform",
+		Members: []domain.User{
+			{ID: authorID, IsActive: true},
+			{ID: "away", IsActive: true, Absence: &domain.Absence{
+				UserID:   "away",
+				StartsAt: now.Add(-time.Hour),
+				EndsAt:   now.Add(time.Hour),
+			}},
+			{ID: "full", IsActive: true, MaxOpenReviews: &zero},
+			{ID: "off", IsActive: false},
+			{ID: "u1", IsActive: true},
+		},
+		Settings: domain.TeamSettings{ReviewersCount: 1},
+	}
+
+	teamRepo.EXPECT().
+		GetByMemberID(gomock.Any(), authorID).
+		Return(team, nil)
+	prRepo.EXPECT().
+		GetOpenReviewCounts(gomock.Any(), []domain.UserID{"full"}).
+		Return(map[domain.UserID]int{"full": 0}, nil)
+	prRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	svc := &PullRequestService{
+		log:   newTestLogger(),
+		teams: teamRepo,
+		prs:   prRepo,
+	}
+
+	pr, err := svc.Create(context.Background(), "pr-1", "Test PR", authorID, domain.PullRequestAttributes{})
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+
+	want := map[domain.UserID]domain.AssignmentReason{
+		authorID: domain.AssignmentReasonAuthor,
+		"away":   domain.AssignmentReasonOutOfOffice,
+		"full":   domain.AssignmentReasonAtCapacity,
+		"off":    domain.AssignmentReasonInactive,
+		"u1":     domain.AssignmentReasonSelected,
+	}
+	if len(pr.AssignmentExplanation) != len(want) {
+		t.Fatalf("expected %d explained candidates, got %v", len(want), pr.AssignmentExplanation)
+	}
+	for _, e := range pr.AssignmentExplanation {
+		if e.Reason != want[e.UserID] {
+			t.Fatalf("expected %s to have reason %q, got %q", e.UserID, want[e.UserID], e.Reason)
+		}
+		if e.Picked != (e.UserID == "u1") {
+			t.Fatalf("unexpected picked flag for %s", e.UserID)
+		}
+	}
+}
+
+func TestPullRequestService_PreviewAssignment_MatchesCreateAndDoesNotStore(t *testing.T) {
+	authorID := domain.UserID("author")
+	members := []domain.User{{ID: authorID, IsActive: true}}
+	for _, id := range []domain.UserID{"u1", "u2", "u3", "u4", "u5", "u6"} {
+		members = append(members, domain.User{ID: id, IsActive: true})
+	}
+	team := &domain.Team{Name: "platform", Members: members}
+
+	for seed := int64(1); seed <= 5; seed++ {
+		ctrl := gomock.NewController(t)
+
+		teamRepo := mocks.NewMockTeamRepository(ctrl)
+		prRepo := mocks.NewMockPullRequestRepository(ctrl)
+		teamRepo.EXPECT().GetByMemberID(gomock.Any(), authorID).Return(team, nil).Times(2)
+		prRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+		svc := newTestPullRequestService(nil, teamRepo, prRepo,
+			Config{RandSource: rand.NewSource(seed), SeedByPullRequest: true})
+
+		preview, err := svc.PreviewAssignment(context.Background(), "pr-1", "Test PR", authorID, domain.PullRequestAttributes{})
+		if err != nil {
+			t.Fatalf("PreviewAssignment returned error: %v", err)
+		}
+		if len(preview.AssignedReviewers) != domain.DefaultReviewersCount {
+			t.Fatalf("expected %d reviewers, got %v", domain.DefaultReviewersCount, preview.AssignedReviewers)
+		}
+
+		created, err := svc.Create(context.Background(), "pr-1", "Test PR", authorID, domain.PullRequestAttributes{})
+		if err != nil {
+			t.Fatalf("Create returned error: %v", err)
+		}
+		if !reflect.DeepEqual(preview.AssignedReviewers, created.AssignedReviewers) {
+			t.Fatalf("expected preview %v to match created %v", preview.AssignedReviewers, created.AssignedReviewers)
+		}
+		ctrl.Finish()
+	}
+}
+
+func TestPullRequestService_PreviewAssignment_ValidationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := &PullRequestService{log: newTestLogger()}
+
+	if _, err := svc.PreviewAssignment(context.Background(), "pr-1", "", "author", domain.PullRequestAttributes{}); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
 func TestPullRequestService_Merge_Idempotent(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -109,7 +679,7 @@ func TestPullRequestService_Merge_Idempotent(t *testing.T) {
 
 	prRepo.
 		EXPECT().
-		Merge(gomock.Any(), prID, gomock.Any()).
+		Merge(gomock.Any(), prID, gomock.Any(), false).
 		Return(mergedPR, nil).
 		Times(2)
 
@@ -148,20 +718,30 @@ func TestPullRequestService_Create_ValidationError(t *testing.T) {
 
 	ctx := context.Background()
 
-	_, err := svc.Create(ctx, "", "name", "author")
+	_, err := svc.Create(ctx, "", "name", "author", domain.PullRequestAttributes{})
 	if !errors.Is(err, domain.ErrValidation) {
 		t.Fatalf("expected ErrValidation for empty id, got %v", err)
 	}
 
-	_, err = svc.Create(ctx, "pr-1", "", "author")
+	_, err = svc.Create(ctx, "pr-1", "", "author", domain.PullRequestAttributes{})
 	if !errors.Is(err, domain.ErrValidation) {
 		t.Fatalf("expected ErrValidation for empty name, got %v", err)
 	}
 
-	_, err = svc.Create(ctx, "pr-1", "name", "")
+	_, err = svc.Create(ctx, "pr-1", "name", "", domain.PullRequestAttributes{})
 	if !errors.Is(err, domain.ErrValidation) {
 		t.Fatalf("expected ErrValidation for empty author, got %v", err)
 	}
+
+	_, err = svc.Create(ctx, "pr-1", "name", "author", domain.PullRequestAttributes{Priority: "URGENT"})
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected ErrValidation for unknown priority, got %v", err)
+	}
+
+	_, err = svc.Create(ctx, "pr-1", "name", "author", domain.PullRequestAttributes{Labels: []string{" "}})
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected ErrValidation for empty label, got %v", err)
+	}
 }
 
 func TestPullRequestService_Create_TeamNotFound(t *testing.T) {
@@ -186,7 +766,7 @@ func TestPullRequestService_Create_TeamNotFound(t *testing.T) {
 		prs:   prRepo,
 	}
 
-	_, err := svc.Create(context.Background(), "pr-1", "Test PR", authorID)
+	_, err := svc.Create(context.Background(), "pr-1", "Test PR", authorID, domain.PullRequestAttributes{})
 	if !errors.Is(err, domain.ErrNotFound) {
 		t.Fatalf("expected ErrNotFound, got %v", err)
 	}
@@ -225,7 +805,7 @@ func TestPullRequestService_Create_RepoError(t *testing.T) {
 		prs:   prRepo,
 	}
 
-	_, err := svc.Create(context.Background(), "pr-1", "Test PR", authorID)
+	_, err := svc.Create(context.Background(), "pr-1", "Test PR", authorID, domain.PullRequestAttributes{})
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -258,7 +838,7 @@ func TestPullRequestService_Merge_NotFound(t *testing.T) {
 
 	prRepo.
 		EXPECT().
-		Merge(gomock.Any(), prID, gomock.Any()).
+		Merge(gomock.Any(), prID, gomock.Any(), false).
 		Return(nil, domain.ErrNotFound)
 
 	svc := &PullRequestService{
@@ -285,7 +865,7 @@ func TestPullRequestService_Merge_RepoError(t *testing.T) {
 
 	prRepo.
 		EXPECT().
-		Merge(gomock.Any(), prID, gomock.Any()).
+		Merge(gomock.Any(), prID, gomock.Any(), false).
 		Return(nil, repoErr)
 
 	svc := &PullRequestService{
@@ -483,39 +1063,154 @@ func TestPullRequestService_ReassignReviewer_NoCandidates(t *testing.T) {
 		Name:              "Test PR",
 		AuthorID:          "author",
 		Status:            domain.PRStatusOpen,
-		AssignedReviewers: []domain.UserID{"u1", "u2"},
-		CreatedAt:         time.Now().UTC(),
+		AssignedReviewers: []domain.UserID{"u1", "u2"},
+		CreatedAt:         time.Now().UTC(),
+	}
+
+	prRepo.
+		EXPECT().
+		GetByID(gomock.Any(), prID).
+		Return(pr, nil)
+
+	team := &domain.Team{
+		Name: "backend",
+		Members: []domain.User{
+			{ID: "u1", Username: "U1", IsActive: true},
+			{ID: "u2", Username: "U2", IsActive: true},
+			{ID: "u3", Username: "U3", IsActive: false},
+		},
+	}
+
+	teamRepo.
+		EXPECT().
+		GetByMemberID(gomock.Any(), oldID).
+		Return(team, nil)
+
+	svc := &PullRequestService{
+		log:   newTestLogger(),
+		users: mocks.NewMockUserRepository(ctrl),
+		teams: teamRepo,
+		prs:   prRepo,
+	}
+
+	_, _, err := svc.ReassignReviewer(context.Background(), prID, oldID)
+	if !errors.Is(err, domain.ErrNoReviewerCandidates) {
+		t.Fatalf("expected ErrNoReviewerCandidates, got %v", err)
+	}
+}
+
+func TestPullRequestService_ReassignReviewer_UsesFallbackTeam(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+
+	prID := domain.PullRequestID("pr-1")
+	oldID := domain.UserID("u1")
+
+	pr := &domain.PullRequest{
+		ID:                prID,
+		Name:              "Test PR",
+		AuthorID:          "author",
+		Status:            domain.PRStatusOpen,
+		AssignedReviewers: []domain.UserID{oldID},
+		CreatedAt:         time.Now().UTC(),
+	}
+
+	team := &domain.Team{
+		Name: "mobile",
+		Members: []domain.User{
+			{ID: "author", Username: "Author", IsActive: true},
+			{ID: oldID, Username: "U1", IsActive: true},
+		},
+		Settings: domain.TeamSettings{FallbackTeams: []string{"backend"}},
+	}
+	fallback := &domain.Team{
+		Name: "backend",
+		Members: []domain.User{
+			{ID: "b1", Username: "B1", IsActive: true},
+		},
+	}
+
+	prRepo.EXPECT().GetByID(gomock.Any(), prID).Return(pr, nil)
+	teamRepo.EXPECT().GetByMemberID(gomock.Any(), oldID).Return(team, nil)
+	teamRepo.EXPECT().GetByName(gomock.Any(), "backend").Return(fallback, nil)
+	prRepo.EXPECT().ReplaceReviewer(gomock.Any(), pr, oldID, gomock.Any(), gomock.Any()).Return(nil)
+
+	svc := &PullRequestService{
+		log:   newTestLogger(),
+		teams: teamRepo,
+		prs:   prRepo,
+	}
+
+	gotPR, gotUser, err := svc.ReassignReviewer(context.Background(), prID, oldID)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if gotUser.ID != "b1" {
+		t.Fatalf("expected new reviewer b1, got %q", gotUser.ID)
+	}
+	if gotPR.FallbackReviewers["b1"] != "backend" {
+		t.Fatalf("expected b1 to be marked as fallback reviewer, got %v", gotPR.FallbackReviewers)
+	}
+}
+
+func TestPullRequestService_ReassignReviewer_UsesDelegateOfAbsentMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+
+	prID := domain.PullRequestID("pr-1")
+	oldID := domain.UserID("u1")
+	now := time.Now().UTC()
+
+	pr := &domain.PullRequest{
+		ID:                prID,
+		Name:              "Test PR",
+		AuthorID:          "author",
+		Status:            domain.PRStatusOpen,
+		AssignedReviewers: []domain.UserID{oldID},
+		CreatedAt:         now,
 	}
 
-	prRepo.
-		EXPECT().
-		GetByID(gomock.Any(), prID).
-		Return(pr, nil)
-
 	team := &domain.Team{
-		Name: "backend",
+		Name: "mobile",
 		Members: []domain.User{
-			{ID: "u1", Username: "U1", IsActive: true},
-			{ID: "u2", Username: "U2", IsActive: true},
-			{ID: "u3", Username: "U3", IsActive: false},
+			{ID: "author", Username: "Author", IsActive: true},
+			{ID: oldID, Username: "U1", IsActive: true},
+			{ID: "away", Username: "Away", IsActive: true, Delegation: &domain.Delegation{
+				FromUserID: "away",
+				ToUserID:   "pair",
+				StartsAt:   now.Add(-time.Hour),
+				EndsAt:     now.Add(time.Hour),
+			}},
+			{ID: "pair", Username: "Pair", IsActive: true},
 		},
 	}
 
-	teamRepo.
-		EXPECT().
-		GetByMemberID(gomock.Any(), oldID).
-		Return(team, nil)
+	prRepo.EXPECT().GetByID(gomock.Any(), prID).Return(pr, nil)
+	teamRepo.EXPECT().GetByMemberID(gomock.Any(), oldID).Return(team, nil)
+	prRepo.EXPECT().ReplaceReviewer(gomock.Any(), pr, oldID, gomock.Any(), gomock.Any()).Return(nil)
 
 	svc := &PullRequestService{
-		log:   newTestLogger(),
-		users: mocks.NewMockUserRepository(ctrl),
-		teams: teamRepo,
-		prs:   prRepo,
+		log:      newTestLogger(),
+		teams:    teamRepo,
+		prs:      prRepo,
+		selector: randomSelector{},
 	}
 
-	_, _, err := svc.ReassignReviewer(context.Background(), prID, oldID)
-	if !errors.Is(err, domain.ErrNoReviewerCandidates) {
-		t.Fatalf("expected ErrNoReviewerCandidates, got %v", err)
+	gotPR, gotUser, err := svc.ReassignReviewer(context.Background(), prID, oldID)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if gotUser.ID != "pair" {
+		t.Fatalf("expected new reviewer pair, got %q", gotUser.ID)
+	}
+	if gotPR.AssignedReviewers[0] != "pair" {
+		t.Fatalf("expected pair to replace u1, got %v", gotPR.AssignedReviewers)
 	}
 }
 
@@ -552,7 +1247,7 @@ func TestPullRequestService_Create_PrAlreadyExists(t *testing.T) {
 		prs:   prRepo,
 	}
 
-	_, err := svc.Create(context.Background(), "pr-1", "Test PR", authorID)
+	_, err := svc.Create(context.Background(), "pr-1", "Test PR", authorID, domain.PullRequestAttributes{})
 	if !errors.Is(err, domain.ErrPullRequestAlreadyExists) {
 		t.Fatalf("expected ErrPullRequestAlreadyExists, got %v", err)
 	}
@@ -598,8 +1293,11 @@ func TestPullRequestService_ReassignReviewer_Success(t *testing.T) {
 		Return(team, nil)
 
 	prRepo.EXPECT().
-		Update(gomock.Any(), gomock.AssignableToTypeOf(&domain.PullRequest{})).
-		DoAndReturn(func(_ context.Context, updated *domain.PullRequest) error {
+		ReplaceReviewer(gomock.Any(), gomock.AssignableToTypeOf(&domain.PullRequest{}), oldID, newReviewerID, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, updated *domain.PullRequest, _, _ domain.UserID, _ time.Time) error {
+			if reason := domain.AuditFromContext(ctx).Reason; reason != domain.ReasonAutomatic {
+				t.Fatalf("expected reason %q, got %q", domain.ReasonAutomatic, reason)
+			}
 			if len(updated.AssignedReviewers) != 1 {
 				t.Fatalf("expected 1 reviewer, got %d", len(updated.AssignedReviewers))
 			}
@@ -764,3 +1462,440 @@ func TestPullRequestService_GetPullRequestReviewerStats_Error(t *testing.T) {
 		t.Fatalf("expected nil stats on error, got: %+v", stats)
 	}
 }
+
+func TestPullRequestService_AddReviewer_FromFallbackTeam(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+
+	prID := domain.PullRequestID("pr-1")
+	pr := &domain.PullRequest{
+		ID:                prID,
+		AuthorID:          "author",
+		Status:            domain.PRStatusOpen,
+		AssignedReviewers: []domain.UserID{"u1"},
+	}
+	team := &domain.Team{
+		Name:     "mobile",
+		Settings: domain.TeamSettings{FallbackTeams: []string{"backend"}},
+	}
+	limit := 3
+
+	prRepo.EXPECT().GetByID(gomock.Any(), prID).Return(pr, nil)
+	teamRepo.EXPECT().GetByMemberID(gomock.Any(), domain.UserID("author")).Return(team, nil)
+	userRepo.EXPECT().GetByID(gomock.Any(), domain.UserID("b1")).
+		Return(&domain.User{ID: "b1", IsActive: true, MaxOpenReviews: &limit}, nil)
+	teamRepo.EXPECT().GetByMemberID(gomock.Any(), domain.UserID("b1")).
+		Return(&domain.Team{Name: "backend"}, nil)
+	prRepo.EXPECT().GetOpenReviewCounts(gomock.Any(), []domain.UserID{"b1"}).
+		Return(map[domain.UserID]int{"b1": 2}, nil)
+	prRepo.EXPECT().AddReviewer(gomock.Any(), pr, domain.UserID("b1"), gomock.Any()).Return(nil)
+
+	svc := &PullRequestService{
+		log:   newTestLogger(),
+		users: userRepo,
+		teams: teamRepo,
+		prs:   prRepo,
+	}
+
+	got, err := svc.AddReviewer(context.Background(), prID, "b1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if want := []domain.UserID{"u1", "b1"}; !reflect.DeepEqual(got.AssignedReviewers, want) {
+		t.Fatalf("expected reviewers %v, got %v", want, got.AssignedReviewers)
+	}
+	if got.FallbackReviewers["b1"] != "backend" {
+		t.Fatalf("expected b1 to be marked as fallback from backend, got %v", got.FallbackReviewers)
+	}
+}
+
+func TestPullRequestService_AddReviewer_Rejected(t *testing.T) {
+	limit := 1
+
+	tests := []struct {
+		name     string
+		userID   domain.UserID
+		user     *domain.User
+		userTeam string
+		load     int
+		wantErr  error
+	}{
+		{name: "author", userID: "author", wantErr: domain.ErrValidation},
+		{name: "already assigned", userID: "u1", wantErr: domain.ErrReviewerAlreadyAssigned},
+		{
+			name:    "inactive",
+			userID:  "u2",
+			user:    &domain.User{ID: "u2", IsActive: false},
+			wantErr: domain.ErrValidation,
+		},
+		{
+			name:     "foreign team",
+			userID:   "u2",
+			user:     &domain.User{ID: "u2", IsActive: true},
+			userTeam: "payments",
+			wantErr:  domain.ErrValidation,
+		},
+		{
+			name:     "at capacity",
+			userID:   "u2",
+			user:     &domain.User{ID: "u2", IsActive: true, MaxOpenReviews: &limit},
+			userTeam: "backend",
+			load:     1,
+			wantErr:  domain.ErrReviewerAtCapacity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			teamRepo := mocks.NewMockTeamRepository(ctrl)
+			prRepo := mocks.NewMockPullRequestRepository(ctrl)
+			userRepo := mocks.NewMockUserRepository(ctrl)
+
+			pr := &domain.PullRequest{
+				ID:                "pr-1",
+				AuthorID:          "author",
+				Status:            domain.PRStatusOpen,
+				AssignedReviewers: []domain.UserID{"u1"},
+			}
+			prRepo.EXPECT().GetByID(gomock.Any(), pr.ID).Return(pr, nil)
+			teamRepo.EXPECT().GetByMemberID(gomock.Any(), pr.AuthorID).Return(&domain.Team{Name: "backend"}, nil)
+			if tt.user != nil {
+				userRepo.EXPECT().GetByID(gomock.Any(), tt.userID).Return(tt.user, nil)
+			}
+			if tt.userTeam != "" {
+				teamRepo.EXPECT().GetByMemberID(gomock.Any(), tt.userID).Return(&domain.Team{Name: tt.userTeam}, nil)
+			}
+			if tt.load > 0 {
+				prRepo.EXPECT().GetOpenReviewCounts(gomock.Any(), []domain.UserID{tt.userID}).
+					Return(map[domain.UserID]int{tt.userID: tt.load}, nil)
+			}
+
+			svc := &PullRequestService{
+				log:   newTestLogger(),
+				users: userRepo,
+				teams: teamRepo,
+				prs:   prRepo,
+			}
+
+			_, err := svc.AddReviewer(context.Background(), pr.ID, tt.userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPullRequestService_ReassignReviewerTo_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+
+	prID := domain.PullRequestID("pr-1")
+	pr := &domain.PullRequest{
+		ID:                prID,
+		AuthorID:          "author",
+		Status:            domain.PRStatusOpen,
+		AssignedReviewers: []domain.UserID{"u1", "u2"},
+	}
+	team := &domain.Team{Name: "backend"}
+
+	prRepo.EXPECT().GetByID(gomock.Any(), prID).Return(pr, nil)
+	teamRepo.EXPECT().GetByMemberID(gomock.Any(), domain.UserID("u1")).Return(team, nil)
+	userRepo.EXPECT().GetByID(gomock.Any(), domain.UserID("u3")).
+		Return(&domain.User{ID: "u3", IsActive: true}, nil)
+	teamRepo.EXPECT().GetByMemberID(gomock.Any(), domain.UserID("u3")).Return(team, nil)
+	prRepo.EXPECT().ReplaceReviewer(gomock.Any(), pr, domain.UserID("u1"), domain.UserID("u3"), gomock.Any()).Return(nil)
+
+	svc := &PullRequestService{
+		log:   newTestLogger(),
+		users: userRepo,
+		teams: teamRepo,
+		prs:   prRepo,
+	}
+
+	got, newReviewer, err := svc.ReassignReviewerTo(context.Background(), prID, "u1", "u3")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if newReviewer.ID != "u3" {
+		t.Fatalf("expected new reviewer u3, got %s", newReviewer.ID)
+	}
+	if want := []domain.UserID{"u3", "u2"}; !reflect.DeepEqual(got.AssignedReviewers, want) {
+		t.Fatalf("expected reviewers %v, got %v", want, got.AssignedReviewers)
+	}
+}
+
+func TestPullRequestService_RemoveReviewer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+
+	prID := domain.PullRequestID("pr-1")
+	pr := &domain.PullRequest{
+		ID:                prID,
+		AuthorID:          "author",
+		Status:            domain.PRStatusOpen,
+		AssignedReviewers: []domain.UserID{"u1", "u2"},
+		FallbackReviewers: map[domain.UserID]string{"u2": "mobile"},
+	}
+
+	prRepo.EXPECT().GetByID(gomock.Any(), prID).Return(pr, nil)
+	prRepo.EXPECT().RemoveReviewer(gomock.Any(), pr, domain.UserID("u2")).Return(nil)
+
+	svc := &PullRequestService{
+		log: newTestLogger(),
+		prs: prRepo,
+	}
+
+	got, err := svc.RemoveReviewer(context.Background(), prID, "u2")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if want := []domain.UserID{"u1"}; !reflect.DeepEqual(got.AssignedReviewers, want) {
+		t.Fatalf("expected reviewers %v, got %v", want, got.AssignedReviewers)
+	}
+	if len(got.FallbackReviewers) != 0 {
+		t.Fatalf("expected no fallback reviewers, got %v", got.FallbackReviewers)
+	}
+}
+
+func TestPullRequestService_Create_ReproducibleWithFixedSource(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	team := &domain.Team{
+		Name: "backend",
+		Members: []domain.User{
+			{ID: "author", IsActive: true},
+			{ID: "u1", IsActive: true},
+			{ID: "u2", IsActive: true},
+			{ID: "u3", IsActive: true},
+			{ID: "u4", IsActive: true},
+			{ID: "u5", IsActive: true},
+		},
+	}
+
+	create := func(id domain.PullRequestID, cfg Config) *domain.PullRequest {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		teamRepo := mocks.NewMockTeamRepository(ctrl)
+		prRepo := mocks.NewMockPullRequestRepository(ctrl)
+		teamRepo.EXPECT().GetByMemberID(gomock.Any(), domain.UserID("author")).Return(team, nil)
+		prRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+		svc := newTestPullRequestService(nil, teamRepo, prRepo, cfg)
+		pr, err := svc.Create(context.Background(), id, "PR", "author", domain.PullRequestAttributes{})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		return pr
+	}
+
+	first := create("pr-1", Config{Clock: fixedClock(now), RandSource: rand.NewSource(42)})
+	second := create("pr-1", Config{Clock: fixedClock(now), RandSource: rand.NewSource(42)})
+	if !reflect.DeepEqual(first.AssignedReviewers, second.AssignedReviewers) {
+		t.Fatalf("expected the same reviewers for the same source, got %v and %v",
+			first.AssignedReviewers, second.AssignedReviewers)
+	}
+	if !first.CreatedAt.Equal(now) {
+		t.Fatalf("expected createdAt %v, got %v", now, first.CreatedAt)
+	}
+
+	// With seeding by pull request the source does not matter, only the ID.
+	for seed := int64(1); seed <= 5; seed++ {
+		a := create("pr-7", Config{RandSource: rand.NewSource(seed), SeedByPullRequest: true})
+		b := create("pr-7", Config{RandSource: rand.NewSource(seed * 100), SeedByPullRequest: true})
+		if !reflect.DeepEqual(a.AssignedReviewers, b.AssignedReviewers) {
+			t.Fatalf("expected the same reviewers for pr-7, got %v and %v",
+				a.AssignedReviewers, b.AssignedReviewers)
+		}
+	}
+}
+
+func TestPullRequestService_Merge_UsesClock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	prID := domain.PullRequestID("pr-1")
+
+	prRepo.EXPECT().
+		Merge(gomock.Any(), prID, now, false).
+		Return(&domain.PullRequest{ID: prID, Status: domain.PRStatusMerged, MergedAt: &now}, nil)
+
+	svc := newTestPullRequestService(nil, nil, prRepo, Config{Clock: fixedClock(now)})
+
+	pr, err := svc.Merge(context.Background(), prID)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if pr.MergedAt == nil || !pr.MergedAt.Equal(now) {
+		t.Fatalf("expected mergedAt %v, got %v", now, pr.MergedAt)
+	}
+}
+
+func TestPullRequestService_SubmitReview(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	reviewRepo := mocks.NewMockReviewRepository(ctrl)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	svc := &PullRequestService{
+		log:     newTestLogger(),
+		reviews: reviewRepo,
+		clock:   fixedClock(now),
+	}
+
+	if _, err := svc.SubmitReview(context.Background(), "pr-1", "u1", "LGTM"); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected validation error for unknown decision, got %v", err)
+	}
+
+	want := &domain.Review{
+		PullRequestID: "pr-1",
+		ReviewerID:    "u1",
+		Decision:      domain.ReviewDecisionApproved,
+		SubmittedAt:   now,
+	}
+	reviewRepo.EXPECT().Save(gomock.Any(), want).Return(nil)
+
+	got, err := svc.SubmitReview(context.Background(), "pr-1", "u1", domain.ReviewDecisionApproved)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected review:\n got:  %+v\n want: %+v", got, want)
+	}
+
+	reviewRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(domain.ErrReviewerNotAssigned)
+	if _, err := svc.SubmitReview(context.Background(), "pr-1", "u9", domain.ReviewDecisionCommented); !errors.Is(err, domain.ErrReviewerNotAssigned) {
+		t.Fatalf("expected ErrReviewerNotAssigned, got %v", err)
+	}
+}
+
+func TestPullRequestService_MergeGating(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	prID := domain.PullRequestID("pr-1")
+
+	svc := &PullRequestService{
+		log: newTestLogger(),
+		prs: prRepo,
+	}
+
+	prRepo.EXPECT().
+		Merge(gomock.Any(), prID, gomock.Any(), false).
+		Return(nil, fmt.Errorf("%w: 0 of 1 required approvals", domain.ErrNotApproved))
+	if _, err := svc.Merge(context.Background(), prID); !errors.Is(err, domain.ErrNotApproved) {
+		t.Fatalf("expected ErrNotApproved, got %v", err)
+	}
+
+	prRepo.EXPECT().
+		Merge(gomock.Any(), prID, gomock.Any(), true).
+		Return(&domain.PullRequest{ID: prID, Status: domain.PRStatusMerged}, nil)
+	pr, err := svc.ForceMerge(context.Background(), prID)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !pr.IsMerged() {
+		t.Fatalf("expected merged pull request, got status %q", pr.Status)
+	}
+}
+
+func TestPullRequestService_Update(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	prID := domain.PullRequestID("pr-1")
+	name := "Renamed"
+	empty := "  "
+
+	svc := &PullRequestService{
+		log: newTestLogger(),
+		prs: prRepo,
+	}
+
+	invalid := []struct {
+		name string
+		id   domain.PullRequestID
+		upd  domain.PullRequestUpdate
+	}{
+		{name: "empty id", id: "", upd: domain.PullRequestUpdate{Name: &name}},
+		{name: "nothing to update", id: prID, upd: domain.PullRequestUpdate{}},
+		{name: "empty name", id: prID, upd: domain.PullRequestUpdate{Name: &empty}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.Update(context.Background(), tt.id, tt.upd); !errors.Is(err, domain.ErrValidation) {
+				t.Fatalf("expected ErrValidation, got %v", err)
+			}
+		})
+	}
+
+	prRepo.EXPECT().
+		UpdateMetadata(gomock.Any(), prID, domain.PullRequestUpdate{Name: &name}).
+		Return(nil, domain.ErrPullRequestAlreadyMerged)
+	if _, err := svc.Update(context.Background(), prID, domain.PullRequestUpdate{Name: &name}); !errors.Is(err, domain.ErrPullRequestAlreadyMerged) {
+		t.Fatalf("expected ErrPullRequestAlreadyMerged, got %v", err)
+	}
+
+	prRepo.EXPECT().
+		UpdateMetadata(gomock.Any(), prID, domain.PullRequestUpdate{Name: &name}).
+		Return(&domain.PullRequest{ID: prID, Name: name, Status: domain.PRStatusOpen}, nil)
+	pr, err := svc.Update(context.Background(), prID, domain.PullRequestUpdate{Name: &name})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if pr.Name != name {
+		t.Fatalf("expected name %q, got %q", name, pr.Name)
+	}
+}
+
+func TestPullRequestService_History(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	eventRepo := mocks.NewMockEventRepository(ctrl)
+	prID := domain.PullRequestID("pr-1")
+
+	svc := &PullRequestService{
+		log:    newTestLogger(),
+		prs:    prRepo,
+		events: eventRepo,
+	}
+
+	prRepo.EXPECT().GetByID(gomock.Any(), domain.PullRequestID("missing")).Return(nil, domain.ErrNotFound)
+	if _, err := svc.History(context.Background(), "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	events := []domain.PullRequestEvent{
+		{ID: 1, PullRequestID: prID, Type: domain.PREventCreated, ActorID: "author"},
+		{ID: 2, PullRequestID: prID, Type: domain.PREventReviewerReassigned, OldReviewerID: "u1", NewReviewerID: "u2"},
+	}
+	prRepo.EXPECT().GetByID(gomock.Any(), prID).Return(&domain.PullRequest{ID: prID}, nil)
+	eventRepo.EXPECT().ListByPullRequest(gomock.Any(), prID).Return(events, nil)
+
+	got, err := svc.History(context.Background(), prID)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !reflect.DeepEqual(got, events) {
+		t.Fatalf("expected %v, got %v", events, got)
+	}
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+)
+
+// CheckReviewSLA marks review assignments whose SLA deadline has passed as overdue
+// and records an event for each of them. Overdue reviews of teams that enable
+// auto-reassignment are handed over through the regular reassignment rules; reviews
+// that cannot be reassigned stay with their reviewer. It returns every review that
+// became overdue during this run.
+func (s *PullRequestService) CheckReviewSLA(ctx context.Context) ([]domain.OverdueReview, error) {
+	ctx = domain.WithReason(ctx, domain.ReasonSLA)
+
+	overdue, err := s.prs.MarkOverdueReviews(ctx, s.clock.Now())
+	if err != nil {
+		s.log.Error("CheckReviewSLA: MarkOverdueReviews failed",
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	for i := range overdue {
+		o := &overdue[i]
+		s.log.Info("review is overdue",
+			slog.String("pull_request_id", string(o.PullRequestID)),
+			slog.String("reviewer_id", string(o.ReviewerID)),
+			slog.Time("due_at", o.DueAt),
+		)
+		if !o.AutoReassign {
+			continue
+		}
+
+		_, newReviewer, err := s.reassign(ctx, o.PullRequestID, o.ReviewerID, "")
+		if err != nil {
+			s.log.Warn("CheckReviewSLA: overdue review not reassigned",
+				slog.String("pull_request_id", string(o.PullRequestID)),
+				slog.String("reviewer_id", string(o.ReviewerID)),
+				slog.String("error_code", ErrorCode(err)),
+			)
+			continue
+		}
+		o.ReassignedTo = newReviewer.ID
+	}
+
+	return overdue, nil
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/repository/mocks"
+)
+
+func TestPullRequestService_CheckReviewSLA_ReassignsWhenTeamAllows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	due := now.Add(-time.Hour)
+	authorID := domain.UserID("author")
+
+	prRepo.EXPECT().
+		MarkOverdueReviews(gomock.Any(), now).
+		Return([]domain.OverdueReview{
+			{PullRequestID: "pr-1", ReviewerID: "u1", DueAt: due, AutoReassign: true},
+			{PullRequestID: "pr-2", ReviewerID: "u1", DueAt: due},
+		}, nil)
+
+	prRepo.EXPECT().
+		GetByID(gomock.Any(), domain.PullRequestID("pr-1")).
+		Return(&domain.PullRequest{
+			ID:                "pr-1",
+			AuthorID:          authorID,
+			Status:            domain.PRStatusOpen,
+			AssignedReviewers: []domain.UserID{"u1"},
+		}, nil)
+
+	teamRepo.EXPECT().
+		GetByMemberID(gomock.Any(), domain.UserID("u1")).
+		Return(&domain.Team{
+			Name: "backend",
+			Members: []domain.User{
+				{ID: authorID, IsActive: true},
+				{ID: "u1", IsActive: true},
+				{ID: "u2", IsActive: true},
+			},
+		}, nil)
+
+	prRepo.EXPECT().
+		ReplaceReviewer(gomock.Any(), gomock.AssignableToTypeOf(&domain.PullRequest{}), domain.UserID("u1"), domain.UserID("u2"), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ *domain.PullRequest, _, _ domain.UserID, _ time.Time) error {
+			if reason := domain.AuditFromContext(ctx).Reason; reason != domain.ReasonSLA {
+				t.Fatalf("expected reason %q, got %q", domain.ReasonSLA, reason)
+			}
+			return nil
+		})
+
+	svc := &PullRequestService{
+		log:   newTestLogger(),
+		users: mocks.NewMockUserRepository(ctrl),
+		teams: teamRepo,
+		prs:   prRepo,
+		clock: fixedClock(now),
+	}
+
+	report, err := svc.CheckReviewSLA(context.Background())
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	want := []domain.OverdueReview{
+		{PullRequestID: "pr-1", ReviewerID: "u1", DueAt: due, AutoReassign: true, ReassignedTo: "u2"},
+		{PullRequestID: "pr-2", ReviewerID: "u1", DueAt: due},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Fatalf("unexpected report:\n got:  %+v\n want: %+v", report, want)
+	}
+}
+
+func TestPullRequestService_CheckReviewSLA_KeepsReviewWithoutCandidates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	authorID := domain.UserID("author")
+
+	prRepo.EXPECT().
+		MarkOverdueReviews(gomock.Any(), now).
+		Return([]domain.OverdueReview{
+			{PullRequestID: "pr-1", ReviewerID: "u1", DueAt: now, AutoReassign: true},
+		}, nil)
+
+	prRepo.EXPECT().
+		GetByID(gomock.Any(), domain.PullRequestID("pr-1")).
+		Return(&domain.PullRequest{
+			ID:                "pr-1",
+			AuthorID:          authorID,
+			Status:            domain.PRStatusOpen,
+			AssignedReviewers: []domain.UserID{"u1"},
+		}, nil)
+
+	teamRepo.EXPECT().
+		GetByMemberID(gomock.Any(), domain.UserID("u1")).
+		Return(&domain.Team{
+			Name: "backend",
+			Members: []domain.User{
+				{ID: authorID, IsActive: true},
+				{ID: "u1", IsActive: true},
+			},
+		}, nil)
+
+	svc := &PullRequestService{
+		log:   newTestLogger(),
+		users: mocks.NewMockUserRepository(ctrl),
+		teams: teamRepo,
+		prs:   prRepo,
+		clock: fixedClock(now),
+	}
+
+	report, err := svc.CheckReviewSLA(context.Background())
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if len(report) != 1 || report[0].ReassignedTo != "" {
+		t.Fatalf("expected one overdue review left with its reviewer, got %+v", report)
+	}
+}
+
+func TestPullRequestService_CheckReviewSLA_RepoError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	repoErr := errors.New("db down")
+
+	prRepo.EXPECT().
+		MarkOverdueReviews(gomock.Any(), gomock.Any()).
+		Return(nil, repoErr)
+
+	svc := &PullRequestService{log: newTestLogger(), prs: prRepo}
+
+	if _, err := svc.CheckReviewSLA(context.Background()); !errors.Is(err, repoErr) {
+		t.Fatalf("expected repo error, got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/repository"
+)
+
+// ReviewerSelector chooses up to count reviewers out of already filtered candidates.
+// Implementations must only return users taken from candidates.
+type ReviewerSelector interface {
+	Select(ctx context.Context, candidates []domain.User, count int) ([]domain.User, error)
+}
+
+// newReviewerSelector returns a selector implementing the given strategy, with
+// random choices taken from rng. Unknown strategies fall back to random selection.
+// A nil rng makes every selection use a fresh time-seeded generator.
+func newReviewerSelector(strategy domain.ReviewerStrategy, prs repository.PullRequestRepository, rng *rand.Rand) ReviewerSelector {
+	switch strategy {
+	case domain.ReviewerStrategyRoundRobin:
+		return &roundRobinSelector{prs: prs}
+	case domain.ReviewerStrategyLeastLoaded:
+		return &leastLoadedSelector{prs: prs, rng: rng}
+	default:
+		return randomSelector{rng: rng}
+	}
+}
+
+// randomSelector picks reviewers uniformly at random.
+type randomSelector struct {
+	rng *rand.Rand
+}
+
+func (s randomSelector) Select(_ context.Context, candidates []domain.User, count int) ([]domain.User, error) {
+	shuffled := append([]domain.User(nil), candidates...)
+
+	r := s.rng
+	if r == nil {
+		r = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	r.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	return takeUsers(shuffled, count), nil
+}
+
+// roundRobinSelector rotates assignments through the team: candidates that were
+// assigned least recently (or never) go first, ties are resolved by user ID.
+type roundRobinSelector struct {
+	prs repository.PullRequestRepository
+}
+
+func (s *roundRobinSelector) Select(ctx context.Context, candidates []domain.User, count int) ([]domain.User, error) {
+	lastAssigned, err := s.prs.GetLastAssignedAt(ctx, userIDs(candidates))
+	if err != nil {
+		return nil, fmt.Errorf("round robin: %w", err)
+	}
+
+	ordered := append([]domain.User(nil), candidates...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		ti, tj := lastAssigned[ordered[i].ID], lastAssigned[ordered[j].ID]
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return ordered[i].ID < ordered[j].ID
+	})
+
+	return takeUsers(ordered, count), nil
+}
+
+// leastLoadedSelector prefers candidates with the fewest OPEN pull requests to review.
+// Candidates with equal load are ordered randomly.
+type leastLoadedSelector struct {
+	prs repository.PullRequestRepository
+	rng *rand.Rand
+}
+
+func (s *leastLoadedSelector) Select(ctx context.Context, candidates []domain.User, count int) ([]domain.User, error) {
+	load, err := s.prs.GetOpenReviewCounts(ctx, userIDs(candidates))
+	if err != nil {
+		return nil, fmt.Errorf("least loaded: %w", err)
+	}
+
+	ordered, _ := randomSelector{rng: s.rng}.Select(ctx, candidates, len(candidates))
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return load[ordered[i].ID] < load[ordered[j].ID]
+	})
+
+	return takeUsers(ordered, count), nil
+}
+
+// takeUsers returns at most count first users of the list.
+func takeUsers(users []domain.User, count int) []domain.User {
+	if len(users) <= count {
+		return users
+	}
+	return users[:count]
+}
+
+// userIDs extracts IDs of the given users preserving their order.
+func userIDs(users []domain.User) []domain.UserID {
+	ids := make([]domain.UserID, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
+
+// lockedSource makes a rand.Source safe for concurrent use, so one generator
+// can be shared by all requests.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
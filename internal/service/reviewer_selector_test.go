@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/repository/mocks"
+)
+
+func testCandidates() []domain.User {
+	return []domain.User{
+		{ID: "u1", Username: "U1", IsActive: true},
+		{ID: "u2", Username: "U2", IsActive: true},
+		{ID: "u3", Username: "U3", IsActive: true},
+	}
+}
+
+func TestNewReviewerSelector_FallsBackToRandom(t *testing.T) {
+	if _, ok := newReviewerSelector("unknown", nil, nil).(randomSelector); !ok {
+		t.Fatal("expected random selector for unknown strategy")
+	}
+	if _, ok := newReviewerSelector("", nil, nil).(randomSelector); !ok {
+		t.Fatal("expected random selector for empty strategy")
+	}
+}
+
+func TestRandomSelector_PicksDistinctCandidates(t *testing.T) {
+	candidates := testCandidates()
+
+	picked, err := randomSelector{}.Select(context.Background(), candidates, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(picked) != 2 {
+		t.Fatalf("expected 2 reviewers, got %d", len(picked))
+	}
+	if picked[0].ID == picked[1].ID {
+		t.Fatalf("expected distinct reviewers, got %v", picked)
+	}
+}
+
+func TestRoundRobinSelector_PrefersLeastRecentlyAssigned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	now := time.Now().UTC()
+
+	prRepo.EXPECT().
+		GetLastAssignedAt(gomock.Any(), []domain.UserID{"u1", "u2", "u3"}).
+		Return(map[domain.UserID]time.Time{
+			"u1": now,
+			"u2": now.Add(-time.Hour),
+		}, nil)
+
+	sel := newReviewerSelector(domain.ReviewerStrategyRoundRobin, prRepo, nil)
+
+	picked, err := sel.Select(context.Background(), testCandidates(), 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []domain.UserID{"u3", "u2"}
+	if got := userIDs(picked); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestLeastLoadedSelector_PrefersFewestOpenReviews(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+
+	prRepo.EXPECT().
+		GetOpenReviewCounts(gomock.Any(), []domain.UserID{"u1", "u2", "u3"}).
+		Return(map[domain.UserID]int{"u1": 5, "u2": 0, "u3": 2}, nil)
+
+	sel := newReviewerSelector(domain.ReviewerStrategyLeastLoaded, prRepo, nil)
+
+	picked, err := sel.Select(context.Background(), testCandidates(), 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []domain.UserID{"u2", "u3"}
+	if got := userIDs(picked); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestLeastLoadedSelector_RepoError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	repoErr := errors.New("db error")
+
+	prRepo.EXPECT().
+		GetOpenReviewCounts(gomock.Any(), gomock.Any()).
+		Return(nil, repoErr)
+
+	sel := newReviewerSelector(domain.ReviewerStrategyLeastLoaded, prRepo, nil)
+
+	if _, err := sel.Select(context.Background(), testCandidates(), 2); !errors.Is(err, repoErr) {
+		t.Fatalf("expected %v, got %v", repoErr, err)
+	}
+}
+
+func TestLeastLoadedSelector_BreaksTiesAmongEquallyLoaded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+
+	prRepo.EXPECT().
+		GetOpenReviewCounts(gomock.Any(), gomock.Any()).
+		Return(map[domain.UserID]int{"u1": 3, "u2": 1, "u3": 1}, nil).
+		AnyTimes()
+
+	sel := newReviewerSelector(domain.ReviewerStrategyLeastLoaded, prRepo, nil)
+
+	for i := 0; i < 20; i++ {
+		picked, err := sel.Select(context.Background(), testCandidates(), 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if picked[0].ID == "u1" {
+			t.Fatalf("most loaded candidate must not be picked while others are free")
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+)
+
+// TopUpReviewers finds OPEN pull requests that have fewer reviewers than their
+// author's team target, adjusted by the team's review rules, and fills the
+// missing slots using the regular selection rules, including fallback teams.
+// Pull requests that still have no candidates are left untouched. It returns a
+// report of every pull request it changed.
+func (s *PullRequestService) TopUpReviewers(ctx context.Context) ([]domain.ReviewerTopUp, error) {
+	ctx = domain.WithReason(ctx, domain.ReasonTopUp)
+
+	stats, err := s.prs.ListUnderstaffedOpen(ctx)
+	if err != nil {
+		s.log.Error("TopUpReviewers: ListUnderstaffedOpen failed",
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	teamsByAuthor := make(map[domain.UserID]*domain.Team)
+	report := make([]domain.ReviewerTopUp, 0)
+
+	for _, st := range stats {
+		team, ok := teamsByAuthor[st.AuthorID]
+		if !ok {
+			team, err = s.teams.GetByMemberID(ctx, st.AuthorID)
+			if err != nil {
+				s.log.Warn("TopUpReviewers: author team not found, skipping",
+					slog.String("pull_request_id", string(st.PullRequestID)),
+					slog.String("author_id", string(st.AuthorID)),
+					slog.String("error_code", ErrorCode(err)),
+				)
+				continue
+			}
+			teamsByAuthor[st.AuthorID] = team
+		}
+
+		added, err := s.topUpPullRequest(ctx, st.PullRequestID, team)
+		if err != nil {
+			return report, err
+		}
+		if len(added) == 0 {
+			continue
+		}
+
+		s.log.Info("pull request reviewers topped up",
+			slog.String("pull_request_id", string(st.PullRequestID)),
+			slog.String("team_name", team.Name),
+			slog.Any("added_reviewers", added),
+		)
+		report = append(report, domain.ReviewerTopUp{
+			PullRequestID: st.PullRequestID,
+			Added:         added,
+		})
+	}
+
+	return report, nil
+}
+
+// topUpPullRequest adds reviewers to a single pull request up to the team target
+// and returns IDs of the added reviewers.
+func (s *PullRequestService) topUpPullRequest(
+	ctx context.Context,
+	id domain.PullRequestID,
+	team *domain.Team,
+) ([]domain.UserID, error) {
+	pr, err := s.prs.GetByID(ctx, id)
+	if err != nil {
+		s.log.Error("TopUpReviewers: GetByID failed",
+			slog.String("pull_request_id", string(id)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+
+	target, _ := team.ReviewRequirements(pr.Labels, pr.Priority)
+	missing := target - len(pr.AssignedReviewers)
+	if pr.Status != domain.PRStatusOpen || missing <= 0 {
+		return nil, nil
+	}
+
+	pick, err := s.forPullRequest(id, pr.AssignedReviewers...).
+		pickReviewersFromTeam(ctx, team, pr.AuthorID, pr.AssignedReviewers, missing)
+	if err != nil {
+		s.log.Error("TopUpReviewers: pick reviewers failed",
+			slog.String("pull_request_id", string(id)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+	if len(pick.reviewers) == 0 {
+		return nil, nil
+	}
+
+	pr.AssignedReviewers = append(pr.AssignedReviewers, userIDs(pick.reviewers)...)
+	pick.annotate(pr)
+
+	// Reviewers are added one by one so that concurrent manual changes of the
+	// same pull request are not overwritten.
+	now := s.clock.Now()
+	added := make([]domain.UserID, 0, len(pick.reviewers))
+	for _, r := range pick.reviewers {
+		err := s.prs.AddReviewer(ctx, pr, r.ID, now)
+		switch {
+		case err == nil:
+			added = append(added, r.ID)
+		case errors.Is(err, domain.ErrReviewerAlreadyAssigned):
+			continue
+		case errors.Is(err, domain.ErrPullRequestAlreadyMerged),
+			errors.Is(err, domain.ErrPullRequestNotOpen):
+			return added, nil
+		case errors.Is(err, domain.ErrVersionConflict):
+			// The pull request was changed meanwhile; the next run tops it up
+			// from its new state.
+			return added, nil
+		default:
+			s.log.Error("TopUpReviewers: AddReviewer failed",
+				slog.String("pull_request_id", string(id)),
+				slog.String("reviewer_id", string(r.ID)),
+				slog.String("error_code", ErrorCode(err)),
+				slog.Any("err", err),
+			)
+			return nil, err
+		}
+	}
+
+	return added, nil
+}
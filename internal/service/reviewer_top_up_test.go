@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/repository/mocks"
+)
+
+func TestPullRequestService_TopUpReviewers_FillsMissingSlots(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+
+	authorID := domain.UserID("author")
+	now := time.Date(2025, 10, 20, 9, 0, 0, 0, time.UTC)
+
+	team := &domain.Team{
+		Name: "backend",
+		Members: []domain.User{
+			{ID: authorID, Username: "Author", IsActive: true},
+			{ID: "u1", Username: "U1", IsActive: true},
+			{ID: "u2", Username: "U2", IsActive: true},
+		},
+		Settings: domain.TeamSettings{ReviewersCount: 2},
+	}
+
+	prRepo.EXPECT().
+		ListUnderstaffedOpen(gomock.Any()).
+		Return([]domain.UnderstaffedPullRequest{
+			{PullRequestID: "pr-1", AuthorID: authorID, ReviewersCount: 1, ReviewersTarget: 2},
+		}, nil)
+
+	teamRepo.EXPECT().
+		GetByMemberID(gomock.Any(), authorID).
+		Return(team, nil)
+
+	prRepo.EXPECT().
+		GetByID(gomock.Any(), domain.PullRequestID("pr-1")).
+		Return(&domain.PullRequest{
+			ID:                "pr-1",
+			AuthorID:          authorID,
+			Status:            domain.PRStatusOpen,
+			AssignedReviewers: []domain.UserID{"u1"},
+			CreatedAt:         time.Now().UTC(),
+		}, nil)
+
+	prRepo.EXPECT().
+		AddReviewer(gomock.Any(), gomock.AssignableToTypeOf(&domain.PullRequest{}), domain.UserID("u2"), now).
+		DoAndReturn(func(_ context.Context, pr *domain.PullRequest, _ domain.UserID, _ time.Time) error {
+			want := []domain.UserID{"u1", "u2"}
+			if !reflect.DeepEqual(pr.AssignedReviewers, want) {
+				t.Fatalf("expected reviewers %v, got %v", want, pr.AssignedReviewers)
+			}
+			return nil
+		})
+
+	svc := &PullRequestService{
+		log:   newTestLogger(),
+		teams: teamRepo,
+		prs:   prRepo,
+		clock: func() time.Time { return now },
+	}
+
+	report, err := svc.TopUpReviewers(context.Background())
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	want := []domain.ReviewerTopUp{{PullRequestID: "pr-1", Added: []domain.UserID{"u2"}}}
+	if !reflect.DeepEqual(report, want) {
+		t.Fatalf("unexpected report:\n got:  %+v\n want: %+v", report, want)
+	}
+}
+
+func TestPullRequestService_TopUpReviewers_SkipsConcurrentlyChangedPR(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+
+	authorID := domain.UserID("author")
+
+	team := &domain.Team{
+		Name: "backend",
+		Members: []domain.User{
+			{ID: authorID, Username: "Author", IsActive: true},
+			{ID: "u1", Username: "U1", IsActive: true},
+			{ID: "u2", Username: "U2", IsActive: true},
+		},
+		Settings: domain.TeamSettings{ReviewersCount: 2},
+	}
+
+	prRepo.EXPECT().
+		ListUnderstaffedOpen(gomock.Any()).
+		Return([]domain.UnderstaffedPullRequest{
+			{PullRequestID: "pr-1", AuthorID: authorID, ReviewersCount: 1, ReviewersTarget: 2},
+		}, nil)
+	teamRepo.EXPECT().
+		GetByMemberID(gomock.Any(), authorID).
+		Return(team, nil)
+	prRepo.EXPECT().
+		GetByID(gomock.Any(), domain.PullRequestID("pr-1")).
+		Return(&domain.PullRequest{
+			ID:                "pr-1",
+			AuthorID:          authorID,
+			Status:            domain.PRStatusOpen,
+			Version:           2,
+			AssignedReviewers: []domain.UserID{"u1"},
+		}, nil)
+	prRepo.EXPECT().
+		AddReviewer(gomock.Any(), gomock.Any(), domain.UserID("u2"), gomock.Any()).
+		Return(domain.ErrVersionConflict)
+
+	svc := &PullRequestService{
+		log:   newTestLogger(),
+		teams: teamRepo,
+		prs:   prRepo,
+	}
+
+	report, err := svc.TopUpReviewers(context.Background())
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if len(report) != 0 {
+		t.Fatalf("expected empty report, got %+v", report)
+	}
+}
+
+func TestPullRequestService_TopUpReviewers_NoCandidatesLeavesPRUntouched(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+
+	authorID := domain.UserID("author")
+
+	team := &domain.Team{
+		Name: "backend",
+		Members: []domain.User{
+			{ID: authorID, Username: "Author", IsActive: true},
+			{ID: "u1", Username: "U1", IsActive: false},
+		},
+	}
+
+	prRepo.EXPECT().
+		ListUnderstaffedOpen(gomock.Any()).
+		Return([]domain.UnderstaffedPullRequest{
+			{PullRequestID: "pr-1", AuthorID: authorID, ReviewersCount: 0, ReviewersTarget: 2},
+		}, nil)
+	teamRepo.EXPECT().
+		GetByMemberID(gomock.Any(), authorID).
+		Return(team, nil)
+	prRepo.EXPECT().
+		GetByID(gomock.Any(), domain.PullRequestID("pr-1")).
+		Return(&domain.PullRequest{ID: "pr-1", AuthorID: authorID, Status: domain.PRStatusOpen}, nil)
+
+	svc := &PullRequestService{
+		log:   newTestLogger(),
+		teams: teamRepo,
+		prs:   prRepo,
+	}
+
+	report, err := svc.TopUpReviewers(context.Background())
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if len(report) != 0 {
+		t.Fatalf("expected empty report, got %+v", report)
+	}
+}
+
+func TestPullRequestService_TopUpReviewers_StatsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	repoErr := errors.New("db error")
+
+	prRepo.EXPECT().
+		ListUnderstaffedOpen(gomock.Any()).
+		Return(nil, repoErr)
+
+	svc := &PullRequestService{
+		log: newTestLogger(),
+		prs: prRepo,
+	}
+
+	if _, err := svc.TopUpReviewers(context.Background()); !errors.Is(err, repoErr) {
+		t.Fatalf("expected %v, got %v", repoErr, err)
+	}
+}
@@ -2,14 +2,57 @@ package service
 
 import (
 	"log/slog"
+	"math/rand"
+	"time"
 
+	"github.com/juzu400/avito-internship/internal/domain"
 	"github.com/juzu400/avito-internship/internal/repository"
 )
 
+// Config holds deployment-level options of the services.
+type Config struct {
+	// ReviewerStrategy selects the algorithm used to pick reviewers.
+	// Empty or unknown values fall back to random selection.
+	ReviewerStrategy domain.ReviewerStrategy
+	// Clock returns the current time. Defaults to time.Now.
+	Clock func() time.Time
+	// RandSource feeds random reviewer selection. Defaults to a time-seeded
+	// source. It is guarded by a mutex, so it does not have to be concurrency-safe.
+	RandSource rand.Source
+	// SeedByPullRequest seeds reviewer selection from the pull request ID instead
+	// of RandSource, so replaying the same input yields the same reviewers.
+	SeedByPullRequest bool
+	// StaleReviewAge is how long a pull request may wait for reviews before it
+	// is reported as stale. Defaults to defaultStaleAfter.
+	StaleReviewAge time.Duration
+	// Notifier delivers stale review reminders and digests. Defaults to a LogNotifier.
+	Notifier Notifier
+	// IdempotencyTTL is how long responses to requests with an idempotency key
+	// are replayed. Defaults to defaultIdempotencyTTL.
+	IdempotencyTTL time.Duration
+}
+
+// clock returns the current time in UTC. A nil clock uses time.Now.
+type clock func() time.Time
+
+func (c clock) Now() time.Time {
+	if c == nil {
+		return time.Now().UTC()
+	}
+	return c().UTC()
+}
+
 type UsersService struct {
-	log   *slog.Logger
-	users repository.UserRepository
-	prs   repository.PullRequestRepository
+	log      *slog.Logger
+	users    repository.UserRepository
+	teams    repository.TeamRepository
+	prs      repository.PullRequestRepository
+	absences repository.AbsenceRepository
+	// delegations hand reviews of a user over to a named substitute.
+	delegations repository.DelegationRepository
+	clock       clock
+	// pullRequests picks replacement reviewers during bulk deactivation.
+	pullRequests *PullRequestService
 }
 
 type TeamsService struct {
@@ -18,10 +61,19 @@ type TeamsService struct {
 }
 
 type PullRequestService struct {
-	log   *slog.Logger
-	users repository.UserRepository
-	teams repository.TeamRepository
-	prs   repository.PullRequestRepository
+	log      *slog.Logger
+	users    repository.UserRepository
+	teams    repository.TeamRepository
+	prs      repository.PullRequestRepository
+	reviews  repository.ReviewRepository
+	events   repository.EventRepository
+	strategy domain.ReviewerStrategy
+	selector ReviewerSelector
+	clock    clock
+	// rng, when set, is the source of all random choices (see seeded).
+	rng *rand.Rand
+	// seedByID makes selection for a pull request use a generator seeded from its ID.
+	seedByID bool
 }
 
 // Services groups all application services for convenient wiring in main and transport layers.
@@ -29,24 +81,97 @@ type Services struct {
 	Users        *UsersService
 	Teams        *TeamsService
 	PullRequests *PullRequestService
+	Digests      *DigestService
+	Idempotency  *IdempotencyService
 }
 
-func NewServices(log *slog.Logger, repos *repository.Repositories) *Services {
+func NewServices(log *slog.Logger, repos *repository.Repositories, cfg Config) *Services {
+	if cfg.ReviewerStrategy != "" && !cfg.ReviewerStrategy.IsValid() {
+		log.Warn("unknown reviewer strategy, falling back to random",
+			slog.String("strategy", string(cfg.ReviewerStrategy)),
+		)
+	}
+
+	pullRequests := newPullRequestService(log, repos, cfg)
+
 	return &Services{
 		Users: &UsersService{
-			log:   log.With(slog.String("service", "users")),
-			users: repos.Users,
-			prs:   repos.PullRequests,
+			log:          log.With(slog.String("service", "users")),
+			users:        repos.Users,
+			teams:        repos.Teams,
+			prs:          repos.PullRequests,
+			absences:     repos.Absences,
+			delegations:  repos.Delegations,
+			clock:        cfg.Clock,
+			pullRequests: pullRequests,
 		},
 		Teams: &TeamsService{
 			log:   log.With(slog.String("service", "teams")),
 			teams: repos.Teams,
 		},
-		PullRequests: &PullRequestService{
-			log:   log.With(slog.String("service", "pull_requests")),
-			users: repos.Users,
-			teams: repos.Teams,
-			prs:   repos.PullRequests,
-		},
+		PullRequests: pullRequests,
+		Digests:      newDigestService(log, repos, cfg),
+		Idempotency:  newIdempotencyService(log, repos, cfg),
+	}
+}
+
+// newIdempotencyService builds an IdempotencyService from cfg, filling in the default TTL.
+func newIdempotencyService(log *slog.Logger, repos *repository.Repositories, cfg Config) *IdempotencyService {
+	ttl := cfg.IdempotencyTTL
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+
+	return &IdempotencyService{
+		log:   log.With(slog.String("service", "idempotency")),
+		keys:  repos.Idempotency,
+		clock: cfg.Clock,
+		ttl:   ttl,
+	}
+}
+
+// newDigestService builds a DigestService from cfg, filling in defaults for the
+// stale threshold and the notifier.
+func newDigestService(log *slog.Logger, repos *repository.Repositories, cfg Config) *DigestService {
+	staleAfter := cfg.StaleReviewAge
+	if staleAfter <= 0 {
+		staleAfter = defaultStaleAfter
+	}
+	notifier := cfg.Notifier
+	if notifier == nil {
+		notifier = NewLogNotifier(log)
+	}
+
+	return &DigestService{
+		log:        log.With(slog.String("service", "digests")),
+		teams:      repos.Teams,
+		prs:        repos.PullRequests,
+		notifier:   notifier,
+		clock:      cfg.Clock,
+		staleAfter: staleAfter,
+	}
+}
+
+// newPullRequestService builds a PullRequestService from cfg, filling in
+// production defaults for the clock and the random source.
+func newPullRequestService(log *slog.Logger, repos *repository.Repositories, cfg Config) *PullRequestService {
+	src := cfg.RandSource
+	if src == nil {
+		src = rand.NewSource(time.Now().UnixNano())
+	}
+	rng := rand.New(&lockedSource{src: src})
+
+	return &PullRequestService{
+		log:      log.With(slog.String("service", "pull_requests")),
+		users:    repos.Users,
+		teams:    repos.Teams,
+		prs:      repos.PullRequests,
+		reviews:  repos.Reviews,
+		events:   repos.Events,
+		strategy: cfg.ReviewerStrategy,
+		selector: newReviewerSelector(cfg.ReviewerStrategy, repos.PullRequests, rng),
+		clock:    cfg.Clock,
+		rng:      rng,
+		seedByID: cfg.SeedByPullRequest,
 	}
 }
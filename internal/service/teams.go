@@ -2,12 +2,19 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 
 	"github.com/juzu400/avito-internship/internal/domain"
 )
 
+// maxReviewersCount is the upper bound for the per-team number of reviewers.
+const maxReviewersCount = 10
+
+// maxReviewSLAHours is the upper bound for the per-team review SLA (30 days).
+const maxReviewSLAHours = 720
+
 // UpsertTeam validates the team and ensures each member belongs to at most one team,
 // then creates or updates the team in the repository. If any member already belongs
 // to a different team, ErrValidation is returned.
@@ -151,3 +158,162 @@ func (s *TeamsService) GetByMemberID(ctx context.Context, userID domain.UserID)
 	}
 	return team, nil
 }
+
+// GetSettings returns reviewer assignment settings of the team.
+// If the name is empty, ErrValidation is returned. If the team does not exist,
+// domain.ErrNotFound is returned from the repository.
+func (s *TeamsService) GetSettings(ctx context.Context, name string) (*domain.TeamSettings, error) {
+	if name == "" {
+		err := fmt.Errorf("%w: team_name is empty", domain.ErrValidation)
+		s.log.Warn("validate GetSettings failed",
+			slog.String("error_code", ErrCodeValidation),
+			slog.String("reason", "empty team_name"),
+		)
+		return nil, err
+	}
+
+	s.log.Info("get team settings", slog.String("team_name", name))
+
+	settings, err := s.teams.GetSettings(ctx, name)
+	if err != nil {
+		s.log.Error("GetSettings failed",
+			slog.String("team_name", name),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+	return settings, nil
+}
+
+// UpdateSettings applies upd to the stored reviewer assignment settings of the team
+// and returns the result; fields not set in upd keep their values.
+// In the resulting settings the reviewers count must be between 1 and
+// maxReviewersCount, the minimum and the required approvals must not exceed it,
+// the strategy must be empty or a known one, the review SLA must be between 0
+// (disabled) and maxReviewSLAHours, fallback teams must be distinct from each other
+// and from the team itself and every review rule must name a label or a priority
+// and keep its counts in the same bounds, otherwise ErrValidation is returned and
+// nothing changes. Labels of review rules are normalized.
+func (s *TeamsService) UpdateSettings(
+	ctx context.Context,
+	name string,
+	upd domain.TeamSettingsUpdate,
+) (*domain.TeamSettings, error) {
+	if name == "" {
+		s.log.Warn("validate UpdateSettings failed",
+			slog.String("error_code", ErrCodeValidation),
+			slog.String("reason", "empty team_name"),
+		)
+		return nil, fmt.Errorf("%w: empty team_name", domain.ErrValidation)
+	}
+
+	settings, err := s.teams.UpdateSettings(ctx, name, func(settings *domain.TeamSettings) error {
+		return s.applySettings(name, settings, upd)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			s.log.Warn("validate UpdateSettings failed",
+				slog.String("error_code", ErrCodeValidation),
+				slog.String("reason", err.Error()),
+				slog.String("team_name", name),
+			)
+			return nil, err
+		}
+		s.log.Error("UpdateSettings failed",
+			slog.String("team_name", name),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, err
+	}
+	return settings, nil
+}
+
+// applySettings applies upd to settings of the named team and validates the
+// result as described in UpdateSettings. Invalid settings are left unchanged.
+func (s *TeamsService) applySettings(name string, settings *domain.TeamSettings, upd domain.TeamSettingsUpdate) error {
+	next := upd.Apply(*settings)
+
+	var reason string
+	switch {
+	case next.ReviewersCount < 1 || next.ReviewersCount > maxReviewersCount:
+		reason = fmt.Sprintf("reviewers_count must be between 1 and %d", maxReviewersCount)
+	case next.MinReviewers < 0 || next.MinReviewers > next.ReviewersCount:
+		reason = "min_reviewers must be between 0 and reviewers_count"
+	case next.RequiredApprovals < 0 || next.RequiredApprovals > next.ReviewersCount:
+		reason = "required_approvals must be between 0 and reviewers_count"
+	case next.Strategy != "" && !next.Strategy.IsValid():
+		reason = "unknown reviewer_strategy"
+	case next.ReviewSLAHours < 0 || next.ReviewSLAHours > maxReviewSLAHours:
+		reason = fmt.Sprintf("review_sla_hours must be between 0 and %d", maxReviewSLAHours)
+	default:
+		reason = validateFallbackTeams(name, next.FallbackTeams)
+		if reason == "" {
+			next.ReviewRules, reason = normalizeReviewRules(next.ReviewRules)
+		}
+	}
+	if reason != "" {
+		return fmt.Errorf("%w: %s", domain.ErrValidation, reason)
+	}
+
+	s.log.Info("updating team settings",
+		slog.String("team_name", name),
+		slog.Int("reviewers_count", next.ReviewersCount),
+		slog.Int("min_reviewers", next.MinReviewers),
+		slog.Int("required_approvals", next.RequiredApprovals),
+		slog.String("reviewer_strategy", string(next.Strategy)),
+		slog.Int("review_sla_hours", next.ReviewSLAHours),
+		slog.Any("fallback_teams", next.FallbackTeams),
+		slog.Int("review_rules", len(next.ReviewRules)),
+	)
+
+	*settings = next
+	return nil
+}
+
+// normalizeReviewRules checks the review rules and returns a copy with normalized
+// labels, or a non-empty reason if a rule is invalid.
+func normalizeReviewRules(rules []domain.ReviewRule) ([]domain.ReviewRule, string) {
+	var res []domain.ReviewRule
+	for i, rule := range rules {
+		if rule.Label != "" {
+			labels, err := domain.NormalizeLabels([]string{rule.Label})
+			if err != nil {
+				return nil, fmt.Sprintf("review_rules[%d]: invalid label", i)
+			}
+			rule.Label = labels[0]
+		}
+		switch {
+		case rule.Label == "" && rule.Priority == "":
+			return nil, fmt.Sprintf("review_rules[%d]: label or priority is required", i)
+		case rule.Priority != "" && !rule.Priority.IsValid():
+			return nil, fmt.Sprintf("review_rules[%d]: unknown priority %q", i, rule.Priority)
+		case rule.ReviewersCount < 1 || rule.ReviewersCount > maxReviewersCount:
+			return nil, fmt.Sprintf("review_rules[%d]: reviewers_count must be between 1 and %d", i, maxReviewersCount)
+		case rule.RequiredApprovals < 0 || rule.RequiredApprovals > rule.ReviewersCount:
+			return nil, fmt.Sprintf("review_rules[%d]: required_approvals must be between 0 and reviewers_count", i)
+		}
+		res = append(res, rule)
+	}
+	return res, ""
+}
+
+// validateFallbackTeams checks the list of fallback teams and returns a non-empty
+// reason if it is invalid.
+func validateFallbackTeams(teamName string, fallbacks []string) string {
+	seen := make(map[string]struct{}, len(fallbacks))
+	for _, name := range fallbacks {
+		switch {
+		case name == "":
+			return "empty fallback team name"
+		case name == teamName:
+			return "team cannot be its own fallback"
+		}
+		if _, ok := seen[name]; ok {
+			return "duplicate fallback team " + name
+		}
+		seen[name] = struct{}{}
+	}
+	return ""
+}
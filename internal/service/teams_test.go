@@ -3,11 +3,13 @@ package service
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 
 	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/repository"
 	"github.com/juzu400/avito-internship/internal/repository/mocks"
 )
 
@@ -344,3 +346,183 @@ func TestTeamsService_UpsertTeam_UserAlreadyInAnotherTeam(t *testing.T) {
 		t.Fatalf("expected validation error, got %v", err)
 	}
 }
+
+// storedSettings makes a TeamRepository.UpdateSettings stub that applies the
+// update to a copy of current, as the repository does with stored settings.
+func storedSettings(current domain.TeamSettings) func(context.Context, string, repository.TeamSettingsUpdater) (*domain.TeamSettings, error) {
+	return func(_ context.Context, _ string, update repository.TeamSettingsUpdater) (*domain.TeamSettings, error) {
+		settings := current
+		if err := update(&settings); err != nil {
+			return nil, err
+		}
+		return &settings, nil
+	}
+}
+
+// fullUpdate returns an update that sets every field to its value in settings.
+func fullUpdate(settings domain.TeamSettings) domain.TeamSettingsUpdate {
+	return domain.TeamSettingsUpdate{
+		ReviewersCount:        &settings.ReviewersCount,
+		MinReviewers:          &settings.MinReviewers,
+		Strategy:              &settings.Strategy,
+		RequiredApprovals:     &settings.RequiredApprovals,
+		FallbackTeams:         &settings.FallbackTeams,
+		ReviewSLAHours:        &settings.ReviewSLAHours,
+		ReviewSLABusinessDays: &settings.ReviewSLABusinessDays,
+		ReviewSLAAutoReassign: &settings.ReviewSLAAutoReassign,
+		ReviewRules:           &settings.ReviewRules,
+	}
+}
+
+func TestTeamsService_UpdateSettings_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, teamRepo := newTestTeamsService(ctrl)
+	teamRepo.EXPECT().
+		UpdateSettings(gomock.Any(), "backend", gomock.Any()).
+		DoAndReturn(storedSettings(domain.DefaultTeamSettings())).
+		AnyTimes()
+
+	tests := []struct {
+		name     string
+		team     string
+		settings domain.TeamSettings
+	}{
+		{"empty team name", "", domain.TeamSettings{ReviewersCount: 2}},
+		{"zero reviewers", "backend", domain.TeamSettings{ReviewersCount: 0}},
+		{"too many reviewers", "backend", domain.TeamSettings{ReviewersCount: maxReviewersCount + 1}},
+		{"min above count", "backend", domain.TeamSettings{ReviewersCount: 1, MinReviewers: 2}},
+		{"negative min", "backend", domain.TeamSettings{ReviewersCount: 1, MinReviewers: -1}},
+		{"approvals above count", "backend", domain.TeamSettings{ReviewersCount: 1, RequiredApprovals: 2}},
+		{"negative approvals", "backend", domain.TeamSettings{ReviewersCount: 1, RequiredApprovals: -1}},
+		{"unknown strategy", "backend", domain.TeamSettings{ReviewersCount: 1, Strategy: "fastest"}},
+		{"negative sla", "backend", domain.TeamSettings{ReviewersCount: 1, ReviewSLAHours: -1}},
+		{"sla too long", "backend", domain.TeamSettings{ReviewersCount: 1, ReviewSLAHours: maxReviewSLAHours + 1}},
+		{"self fallback", "backend", domain.TeamSettings{ReviewersCount: 1, FallbackTeams: []string{"backend"}}},
+		{"duplicate fallback", "backend", domain.TeamSettings{ReviewersCount: 1, FallbackTeams: []string{"qa", "qa"}}},
+		{"rule without label or priority", "backend", domain.TeamSettings{ReviewersCount: 1,
+			ReviewRules: []domain.ReviewRule{{ReviewersCount: 1}}}},
+		{"rule with unknown priority", "backend", domain.TeamSettings{ReviewersCount: 1,
+			ReviewRules: []domain.ReviewRule{{Priority: "URGENT", ReviewersCount: 1}}}},
+		{"rule without reviewers", "backend", domain.TeamSettings{ReviewersCount: 1,
+			ReviewRules: []domain.ReviewRule{{Label: "typo", ReviewersCount: 0}}}},
+		{"rule approvals above count", "backend", domain.TeamSettings{ReviewersCount: 1,
+			ReviewRules: []domain.ReviewRule{{Label: "hotfix", ReviewersCount: 2, RequiredApprovals: 3}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.UpdateSettings(context.Background(), tt.team, fullUpdate(tt.settings))
+			if !errors.Is(err, domain.ErrValidation) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTeamsService_UpdateSettings_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, teamRepo := newTestTeamsService(ctrl)
+
+	settings := domain.TeamSettings{
+		ReviewersCount: 3,
+		MinReviewers:   2,
+		Strategy:       domain.ReviewerStrategyLeastLoaded,
+	}
+
+	teamRepo.EXPECT().
+		UpdateSettings(gomock.Any(), "platform", gomock.Any()).
+		DoAndReturn(storedSettings(domain.DefaultTeamSettings()))
+
+	got, err := svc.UpdateSettings(context.Background(), "platform", fullUpdate(settings))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !reflect.DeepEqual(*got, settings) {
+		t.Fatalf("expected settings %+v, got %+v", settings, *got)
+	}
+}
+
+func TestTeamsService_UpdateSettings_KeepsOmittedFields(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, teamRepo := newTestTeamsService(ctrl)
+
+	stored := domain.TeamSettings{
+		ReviewersCount:        3,
+		MinReviewers:          2,
+		RequiredApprovals:     1,
+		Strategy:              domain.ReviewerStrategyLeastLoaded,
+		FallbackTeams:         []string{"backend"},
+		ReviewSLAHours:        24,
+		ReviewSLABusinessDays: true,
+		ReviewRules:           []domain.ReviewRule{{Label: "hotfix", ReviewersCount: 3, RequiredApprovals: 2}},
+	}
+
+	teamRepo.EXPECT().
+		UpdateSettings(gomock.Any(), "platform", gomock.Any()).
+		DoAndReturn(storedSettings(stored))
+
+	hours := 48
+	got, err := svc.UpdateSettings(context.Background(), "platform", domain.TeamSettingsUpdate{ReviewSLAHours: &hours})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	want := stored
+	want.ReviewSLAHours = 48
+	if !reflect.DeepEqual(*got, want) {
+		t.Fatalf("expected settings %+v, got %+v", want, *got)
+	}
+}
+
+func TestTeamsService_UpdateSettings_NormalizesRuleLabels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, teamRepo := newTestTeamsService(ctrl)
+
+	rules := []domain.ReviewRule{
+		{Label: " HotFix ", ReviewersCount: 3, RequiredApprovals: 2},
+		{Priority: domain.PriorityCritical, ReviewersCount: 3, RequiredApprovals: 3},
+	}
+	want := []domain.ReviewRule{
+		{Label: "hotfix", ReviewersCount: 3, RequiredApprovals: 2},
+		{Priority: domain.PriorityCritical, ReviewersCount: 3, RequiredApprovals: 3},
+	}
+
+	teamRepo.EXPECT().
+		UpdateSettings(gomock.Any(), "platform", gomock.Any()).
+		DoAndReturn(storedSettings(domain.DefaultTeamSettings()))
+
+	got, err := svc.UpdateSettings(context.Background(), "platform", domain.TeamSettingsUpdate{ReviewRules: &rules})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !reflect.DeepEqual(got.ReviewRules, want) {
+		t.Fatalf("expected rules %+v, got %+v", want, got.ReviewRules)
+	}
+}
+
+func TestTeamsService_GetSettings_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, teamRepo := newTestTeamsService(ctrl)
+
+	teamRepo.EXPECT().
+		GetSettings(gomock.Any(), "backend").
+		Return(nil, domain.ErrNotFound)
+
+	got, err := svc.GetSettings(context.Background(), "backend")
+	if got != nil {
+		t.Fatalf("expected nil settings, got %#v", got)
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -2,10 +2,13 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/repository"
 )
 
 // validateUserID checks that user ID is not empty and logs validation errors
@@ -46,6 +49,37 @@ func (s *UsersService) SetIsActive(ctx context.Context, id domain.UserID, active
 	return nil
 }
 
+// SetMaxOpenReviews sets the limit of OPEN pull requests the user may review at once.
+// A nil limit removes it. If the user does not exist, domain.ErrNotFound is returned.
+func (s *UsersService) SetMaxOpenReviews(ctx context.Context, id domain.UserID, limit *int) error {
+	if err := s.validateUserID("SetMaxOpenReviews", id); err != nil {
+		return err
+	}
+	if limit != nil && *limit < 0 {
+		s.log.Warn("validate SetMaxOpenReviews failed",
+			slog.String("error_code", ErrCodeValidation),
+			slog.String("reason", "negative max_open_reviews"),
+		)
+		return fmt.Errorf("%w: max_open_reviews must not be negative", domain.ErrValidation)
+	}
+
+	s.log.Info("setting user review capacity",
+		slog.String("user_id", string(id)),
+		slog.Any("max_open_reviews", limit),
+	)
+
+	if err := s.users.SetMaxOpenReviews(ctx, id, limit); err != nil {
+		s.log.Error("SetMaxOpenReviews failed",
+			slog.String("user_id", string(id)),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return err
+	}
+
+	return nil
+}
+
 // GetByID returns a user by ID.
 // If the user does not exist, domain.ErrNotFound is returned.
 func (s *UsersService) GetByID(ctx context.Context, id domain.UserID) (*domain.User, error) {
@@ -68,16 +102,45 @@ func (s *UsersService) GetByID(ctx context.Context, id domain.UserID) (*domain.U
 	return u, nil
 }
 
-// GetReviews returns pull requests where the given user is assigned as a reviewer.
-// It first ensures that the user exists by calling GetByID.
-func (s *UsersService) GetReviews(ctx context.Context, id domain.UserID) ([]*domain.PullRequest, error) {
+// GetReviews returns one page of pull requests where the given user is assigned
+// as a reviewer, newest first. It first ensures that the user exists by calling GetByID.
+// Without limit and cursor every such pull request is returned, as before paging
+// was added; a cursor without limit uses the default page size. Statuses, limit
+// and cursor are validated like in PullRequestService.List.
+func (s *UsersService) GetReviews(
+	ctx context.Context,
+	id domain.UserID,
+	q domain.ReviewListQuery,
+) (*domain.PullRequestPage, error) {
 	if _, err := s.GetByID(ctx, id); err != nil {
 		return nil, err
 	}
 
+	unbounded := q.Limit == 0 && q.After == nil
+	if q.Limit == 0 {
+		q.Limit = defaultListLimit
+	}
+	if reason := validateListQuery(domain.PullRequestListQuery{
+		Filter: domain.PullRequestFilter{Statuses: q.Statuses},
+		SortBy: domain.PRSortCreatedAt,
+		Order:  domain.SortDesc,
+		Limit:  q.Limit,
+		After:  q.After,
+	}); reason != "" {
+		s.log.Warn("validate GetReviews failed",
+			slog.String("error_code", ErrCodeValidation),
+			slog.String("reason", reason),
+			slog.String("user_id", string(id)),
+		)
+		return nil, fmt.Errorf("%w: %s", domain.ErrValidation, reason)
+	}
+	if unbounded {
+		q.Limit = 0
+	}
+
 	s.log.Info("listing reviews for user", slog.String("user_id", string(id)))
 
-	prs, err := s.prs.ListByReviewer(ctx, id)
+	page, err := s.prs.ListByReviewer(ctx, id, q)
 	if err != nil {
 		s.log.Error("GetReviews failed",
 			slog.String("user_id", string(id)),
@@ -87,5 +150,188 @@ func (s *UsersService) GetReviews(ctx context.Context, id domain.UserID) ([]*dom
 		return nil, err
 	}
 
-	return prs, nil
+	return page, nil
+}
+
+const (
+	// maxBulkDeactivateUsers limits the number of users deactivated by one request.
+	maxBulkDeactivateUsers = 1000
+	// bulkDeactivateTimeout bounds the time spent in a single bulk deactivation.
+	bulkDeactivateTimeout = 30 * time.Second
+)
+
+// DeactivateUsers deactivates the given users and, if teamName is set, all members
+// of that team in one transaction. Their reviews on OPEN pull requests are handed
+// over to candidates picked like in ReassignReviewer, so a deactivated team passes
+// its reviews to its fallback teams; when nobody is available the slot is dropped.
+// It returns IDs of the deactivated users and a report of every reassignment.
+func (s *UsersService) DeactivateUsers(
+	ctx context.Context,
+	teamName string,
+	ids []domain.UserID,
+) ([]domain.UserID, []domain.ReviewerReassignment, error) {
+	if teamName == "" && len(ids) == 0 {
+		s.log.Warn("validate DeactivateUsers failed",
+			slog.String("error_code", ErrCodeValidation),
+			slog.String("reason", "neither team_name nor user_ids given"),
+		)
+		return nil, nil, fmt.Errorf("%w: team_name or user_ids is required", domain.ErrValidation)
+	}
+
+	targets := make([]domain.UserID, 0, len(ids))
+	seen := make(map[domain.UserID]struct{}, len(ids))
+	add := func(id domain.UserID) {
+		if _, ok := seen[id]; ok {
+			return
+		}
+		seen[id] = struct{}{}
+		targets = append(targets, id)
+	}
+
+	for _, id := range ids {
+		if id == "" {
+			s.log.Warn("validate DeactivateUsers failed",
+				slog.String("error_code", ErrCodeValidation),
+				slog.String("reason", "empty user_id"),
+			)
+			return nil, nil, fmt.Errorf("%w: user_id is empty", domain.ErrValidation)
+		}
+		add(id)
+	}
+
+	if teamName != "" {
+		team, err := s.teams.GetByName(ctx, teamName)
+		if err != nil {
+			s.log.Error("DeactivateUsers: GetByName failed",
+				slog.String("team_name", teamName),
+				slog.String("error_code", ErrorCode(err)),
+				slog.Any("err", err),
+			)
+			return nil, nil, err
+		}
+		for _, m := range team.Members {
+			add(m.ID)
+		}
+	}
+
+	if len(targets) > maxBulkDeactivateUsers {
+		s.log.Warn("validate DeactivateUsers failed",
+			slog.String("error_code", ErrCodeValidation),
+			slog.String("reason", "too many users"),
+			slog.Int("users_count", len(targets)),
+		)
+		return nil, nil, fmt.Errorf("%w: at most %d users can be deactivated at once",
+			domain.ErrValidation, maxBulkDeactivateUsers)
+	}
+	if len(targets) == 0 {
+		return targets, []domain.ReviewerReassignment{}, nil
+	}
+
+	s.log.Info("deactivating users",
+		slog.String("team_name", teamName),
+		slog.Int("users_count", len(targets)),
+	)
+
+	ctx, cancel := context.WithTimeout(ctx, bulkDeactivateTimeout)
+	defer cancel()
+
+	report, err := s.users.DeactivateUsers(ctx, targets, s.replacementPicker(), s.clock.Now())
+	if err != nil {
+		s.log.Error("DeactivateUsers failed",
+			slog.String("team_name", teamName),
+			slog.String("error_code", ErrorCode(err)),
+			slog.Any("err", err),
+		)
+		return nil, nil, err
+	}
+
+	s.log.Info("users deactivated",
+		slog.Int("users_count", len(targets)),
+		slog.Int("reassignments_count", len(report)),
+	)
+
+	return targets, report, nil
+}
+
+// replacementPicker returns a picker that hands a released review over the way
+// ReassignReviewer does: to a member of the old reviewer's team chosen by the
+// team's strategy, then to its fallback teams, with absent members swapped for
+// their delegates. Reviewers without a team lose the slot. Each team is loaded
+// once per deactivation.
+func (s *UsersService) replacementPicker() repository.ReplacementPicker {
+	var teams *cachedTeams
+
+	return func(
+		ctx context.Context,
+		repos *repository.Repositories,
+		pr *domain.PullRequest,
+		oldReviewer domain.UserID,
+	) (domain.UserID, error) {
+		if teams == nil {
+			teams = newCachedTeams(repos.Teams)
+		}
+
+		team, err := teams.GetByMemberID(ctx, oldReviewer)
+		if errors.Is(err, domain.ErrNotFound) {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		cached := *repos
+		cached.Teams = teams
+		pick, err := s.pullRequests.withRepositories(&cached).forPullRequest(pr.ID, oldReviewer).
+			pickReviewersFromTeam(ctx, team, pr.AuthorID, pr.AssignedReviewers, 1)
+		if err != nil {
+			return "", err
+		}
+		if len(pick.reviewers) == 0 {
+			return "", nil
+		}
+
+		pick.annotate(pr)
+		return pick.reviewers[0].ID, nil
+	}
+}
+
+// cachedTeams remembers teams read through the embedded repository, so that a
+// bulk change loads every team once. It must only be used while the teams do
+// not change, e.g. within one transaction.
+type cachedTeams struct {
+	repository.TeamRepository
+	byName   map[string]*domain.Team
+	byMember map[domain.UserID]*domain.Team
+}
+
+func newCachedTeams(teams repository.TeamRepository) *cachedTeams {
+	return &cachedTeams{
+		TeamRepository: teams,
+		byName:         make(map[string]*domain.Team),
+		byMember:       make(map[domain.UserID]*domain.Team),
+	}
+}
+
+func (c *cachedTeams) GetByName(ctx context.Context, name string) (*domain.Team, error) {
+	if team, ok := c.byName[name]; ok {
+		return team, nil
+	}
+	team, err := c.TeamRepository.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	c.byName[name] = team
+	return team, nil
+}
+
+func (c *cachedTeams) GetByMemberID(ctx context.Context, userID domain.UserID) (*domain.Team, error) {
+	if team, ok := c.byMember[userID]; ok {
+		return team, nil
+	}
+	team, err := c.TeamRepository.GetByMemberID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	c.byMember[userID] = team
+	return team, nil
 }
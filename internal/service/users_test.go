@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
 	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/repository"
 	"github.com/juzu400/avito-internship/internal/repository/mocks"
 )
 
@@ -16,9 +18,10 @@ func newTestUsersService(ctrl *gomock.Controller) (*UsersService, *mocks.MockUse
 	prRepo := mocks.NewMockPullRequestRepository(ctrl)
 
 	svc := &UsersService{
-		log:   newTestLogger(),
-		users: userRepo,
-		prs:   prRepo,
+		log:          newTestLogger(),
+		users:        userRepo,
+		prs:          prRepo,
+		pullRequests: &PullRequestService{log: newTestLogger()},
 	}
 
 	return svc, userRepo, prRepo
@@ -82,7 +85,7 @@ func TestUsersService_GetReviews_ValidationEmptyUserID(t *testing.T) {
 
 	svc, _, _ := newTestUsersService(ctrl)
 
-	prs, err := svc.GetReviews(context.Background(), "")
+	prs, err := svc.GetReviews(context.Background(), "", domain.ReviewListQuery{})
 	if prs != nil {
 		t.Fatalf("expected nil prs, got %#v", prs)
 	}
@@ -105,6 +108,7 @@ func TestUsersService_GetReviews_Success(t *testing.T) {
 	pr1 := &domain.PullRequest{}
 	pr2 := &domain.PullRequest{}
 	expected := []*domain.PullRequest{pr1, pr2}
+	query := domain.ReviewListQuery{}
 
 	gomock.InOrder(
 		userRepo.EXPECT().
@@ -116,24 +120,45 @@ func TestUsersService_GetReviews_Success(t *testing.T) {
 			}, nil),
 
 		prRepo.EXPECT().
-			ListByReviewer(gomock.Any(), userID).
-			Return(expected, nil),
+			ListByReviewer(gomock.Any(), userID, query).
+			Return(&domain.PullRequestPage{Items: expected}, nil),
 	)
 
-	got, err := svc.GetReviews(context.Background(), userID)
+	got, err := svc.GetReviews(context.Background(), userID, domain.ReviewListQuery{})
 	if err != nil {
 		t.Fatalf("expected nil error, got %v", err)
 	}
-	if len(got) != len(expected) {
-		t.Fatalf("expected %d prs, got %d", len(expected), len(got))
+	if len(got.Items) != len(expected) {
+		t.Fatalf("expected %d prs, got %d", len(expected), len(got.Items))
 	}
 	for i := range expected {
-		if got[i] != expected[i] {
-			t.Fatalf("expected prs[%d] == %p, got %p", i, expected[i], got[i])
+		if got.Items[i] != expected[i] {
+			t.Fatalf("expected prs[%d] == %p, got %p", i, expected[i], got.Items[i])
 		}
 	}
 }
 
+func TestUsersService_GetReviews_CursorWithoutLimitUsesDefaultPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, userRepo, prRepo := newTestUsersService(ctrl)
+
+	userID := domain.UserID("u1")
+	cursor := &domain.PullRequestCursor{SortBy: domain.PRSortCreatedAt, Order: domain.SortDesc, ID: "pr-1"}
+
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), userID).
+		Return(&domain.User{ID: userID, IsActive: true}, nil)
+	prRepo.EXPECT().
+		ListByReviewer(gomock.Any(), userID, domain.ReviewListQuery{Limit: defaultListLimit, After: cursor}).
+		Return(&domain.PullRequestPage{}, nil)
+
+	if _, err := svc.GetReviews(context.Background(), userID, domain.ReviewListQuery{After: cursor}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+}
+
 func TestUsersService_GetReviews_RepoErrorPropagated(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -153,11 +178,11 @@ func TestUsersService_GetReviews_RepoErrorPropagated(t *testing.T) {
 			}, nil),
 
 		prRepo.EXPECT().
-			ListByReviewer(gomock.Any(), userID).
+			ListByReviewer(gomock.Any(), userID, gomock.Any()).
 			Return(nil, repoErr),
 	)
 
-	got, err := svc.GetReviews(context.Background(), userID)
+	got, err := svc.GetReviews(context.Background(), userID, domain.ReviewListQuery{})
 	if got != nil {
 		t.Fatalf("expected nil prs, got %#v", got)
 	}
@@ -179,10 +204,10 @@ func TestUsersService_GetReviews_UserNotFound(t *testing.T) {
 		Return(nil, domain.ErrNotFound)
 
 	prRepo.EXPECT().
-		ListByReviewer(gomock.Any(), gomock.Any()).
+		ListByReviewer(gomock.Any(), gomock.Any(), gomock.Any()).
 		Times(0)
 
-	prs, err := svc.GetReviews(context.Background(), userID)
+	prs, err := svc.GetReviews(context.Background(), userID, domain.ReviewListQuery{})
 	if prs != nil {
 		t.Fatalf("expected nil prs, got %#v", prs)
 	}
@@ -190,3 +215,179 @@ func TestUsersService_GetReviews_UserNotFound(t *testing.T) {
 		t.Fatalf("expected ErrNotFound, got %v", err)
 	}
 }
+
+func TestUsersService_GetReviews_InvalidPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, userRepo, prRepo := newTestUsersService(ctrl)
+
+	userID := domain.UserID("u1")
+
+	tests := []struct {
+		name string
+		q    domain.ReviewListQuery
+	}{
+		{name: "unknown status", q: domain.ReviewListQuery{Statuses: []domain.PullRequestStatus{"PENDING"}}},
+		{name: "limit too large", q: domain.ReviewListQuery{Limit: maxListLimit + 1}},
+		{
+			name: "cursor from another order",
+			q: domain.ReviewListQuery{
+				After: &domain.PullRequestCursor{SortBy: domain.PRSortCreatedAt, Order: domain.SortAsc, ID: "pr-1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo.EXPECT().
+				GetByID(gomock.Any(), userID).
+				Return(&domain.User{ID: userID, IsActive: true}, nil)
+			prRepo.EXPECT().
+				ListByReviewer(gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(0)
+
+			if _, err := svc.GetReviews(context.Background(), userID, tt.q); !errors.Is(err, domain.ErrValidation) {
+				t.Fatalf("expected ErrValidation, got %v", err)
+			}
+		})
+	}
+}
+
+func TestUsersService_DeactivateUsers_ValidationNothingGiven(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, _, _ := newTestUsersService(ctrl)
+
+	_, _, err := svc.DeactivateUsers(context.Background(), "", nil)
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestUsersService_DeactivateUsers_HandsReviewsToFallbackTeam(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, userRepo, _ := newTestUsersService(ctrl)
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	svc.teams = teamRepo
+
+	teamRepo.EXPECT().
+		GetByName(gomock.Any(), "backend").
+		Return(&domain.Team{
+			Name:    "backend",
+			Members: []domain.User{{ID: "u1"}, {ID: "u2"}},
+		}, nil)
+
+	// Repositories of the deactivation transaction: the team is already inactive.
+	txTeams := mocks.NewMockTeamRepository(ctrl)
+	txPRs := mocks.NewMockPullRequestRepository(ctrl)
+	txRepos := &repository.Repositories{Teams: txTeams, PullRequests: txPRs}
+
+	txTeams.EXPECT().
+		GetByMemberID(gomock.Any(), domain.UserID("u1")).
+		Return(&domain.Team{
+			Name:    "backend",
+			Members: []domain.User{{ID: "u1"}, {ID: "u2"}},
+			Settings: domain.TeamSettings{
+				Strategy:      domain.ReviewerStrategyLeastLoaded,
+				FallbackTeams: []string{"platform"},
+			},
+		}, nil)
+	txTeams.EXPECT().
+		GetByName(gomock.Any(), "platform").
+		Return(&domain.Team{
+			Name:    "platform",
+			Members: []domain.User{{ID: "p1", IsActive: true}, {ID: "p2", IsActive: true}},
+		}, nil)
+	txPRs.EXPECT().
+		GetOpenReviewCounts(gomock.Any(), gomock.Any()).
+		Return(map[domain.UserID]int{"p1": 0, "p2": 1}, nil)
+
+	userRepo.EXPECT().
+		DeactivateUsers(gomock.Any(), []domain.UserID{"u2", "u1"}, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ []domain.UserID, pick repository.ReplacementPicker, _ time.Time) ([]domain.ReviewerReassignment, error) {
+			var report []domain.ReviewerReassignment
+			prs := []*domain.PullRequest{
+				{ID: "pr-1", AuthorID: "u5", AssignedReviewers: []domain.UserID{"u1"}},
+				{ID: "pr-2", AuthorID: "u5", AssignedReviewers: []domain.UserID{"u1", "p1"}},
+				{ID: "pr-3", AuthorID: "p2", AssignedReviewers: []domain.UserID{"u1", "p1"}},
+			}
+			for _, pr := range prs {
+				newID, err := pick(ctx, txRepos, pr, "u1")
+				if err != nil {
+					return nil, err
+				}
+				if newID != "" && pr.FallbackReviewers[newID] != "platform" {
+					t.Fatalf("%s: expected %s to be marked as taken from platform", pr.ID, newID)
+				}
+				report = append(report, domain.ReviewerReassignment{
+					PullRequestID: pr.ID,
+					OldReviewerID: "u1",
+					NewReviewerID: newID,
+				})
+			}
+			return report, nil
+		})
+
+	deactivated, report, err := svc.DeactivateUsers(context.Background(), "backend", []domain.UserID{"u2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(deactivated) != 2 {
+		t.Fatalf("expected 2 deactivated users, got %v", deactivated)
+	}
+
+	want := []domain.UserID{"p1", "p2", ""}
+	for i, ra := range report {
+		if ra.NewReviewerID != want[i] {
+			t.Fatalf("reassignment %d: expected %q, got %q", i, want[i], ra.NewReviewerID)
+		}
+	}
+}
+
+func TestUsersService_DeactivateUsers_RepoErrorPropagated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, userRepo, _ := newTestUsersService(ctrl)
+
+	userRepo.EXPECT().
+		DeactivateUsers(gomock.Any(), []domain.UserID{"u1"}, gomock.Any(), gomock.Any()).
+		Return(nil, domain.ErrNotFound)
+
+	_, _, err := svc.DeactivateUsers(context.Background(), "", []domain.UserID{"u1", "u1"})
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestUsersService_SetMaxOpenReviews_ValidationNegative(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, _, _ := newTestUsersService(ctrl)
+	limit := -1
+
+	err := svc.SetMaxOpenReviews(context.Background(), "u1", &limit)
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestUsersService_SetMaxOpenReviews_RemovesLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, userRepo, _ := newTestUsersService(ctrl)
+
+	userRepo.EXPECT().
+		SetMaxOpenReviews(gomock.Any(), domain.UserID("u1"), nil).
+		Return(nil)
+
+	if err := svc.SetMaxOpenReviews(context.Background(), "u1", nil); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"log/slog"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/service"
+)
+
+// AddAbsence handles POST /users/addAbsence.
+// It registers an out-of-office window for the user and returns the stored absence.
+func (h *Handler) AddAbsence(w http.ResponseWriter, r *http.Request) {
+	var req AddAbsenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Warn("AddAbsence: invalid json", slog.Any("err", err))
+		writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "invalid json")
+		return
+	}
+
+	absence := &domain.Absence{
+		UserID:     domain.UserID(req.UserID),
+		StartsAt:   req.StartsAt.UTC(),
+		EndsAt:     req.EndsAt.UTC(),
+		DelegateID: domain.UserID(req.DelegateID),
+	}
+
+	if err := h.services.Users.AddAbsence(r.Context(), absence); err != nil {
+		status, code := mapErrorToHTTP(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, AbsenceResponse{Absence: toAbsenceDTO(*absence)})
+}
+
+// GetUserAbsences handles GET /users/getAbsences.
+// It expects a "user_id" query parameter and returns all absences of the user.
+func (h *Handler) GetUserAbsences(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "user_id is required")
+		return
+	}
+
+	absences, err := h.services.Users.ListAbsences(r.Context(), domain.UserID(userID))
+	if err != nil {
+		status, code := mapErrorToHTTP(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	resp := GetUserAbsencesResponse{
+		UserID:   userID,
+		Absences: make([]AbsenceDTO, 0, len(absences)),
+	}
+	for _, a := range absences {
+		resp.Absences = append(resp.Absences, toAbsenceDTO(a))
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// CancelAbsence handles POST /users/cancelAbsence.
+// It cancels the absence and returns it with the cancellation time.
+func (h *Handler) CancelAbsence(w http.ResponseWriter, r *http.Request) {
+	var req CancelAbsenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Warn("CancelAbsence: invalid json", slog.Any("err", err))
+		writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "invalid json")
+		return
+	}
+
+	absence, err := h.services.Users.CancelAbsence(r.Context(), domain.AbsenceID(req.AbsenceID))
+	if err != nil {
+		status, code := mapErrorToHTTP(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, AbsenceResponse{Absence: toAbsenceDTO(*absence)})
+}
+
+// toAbsenceDTO maps a domain absence to its HTTP representation.
+func toAbsenceDTO(a domain.Absence) AbsenceDTO {
+	return AbsenceDTO{
+		AbsenceID:   int64(a.ID),
+		UserID:      string(a.UserID),
+		StartsAt:    a.StartsAt,
+		EndsAt:      a.EndsAt,
+		DelegateID:  string(a.DelegateID),
+		CreatedAt:   a.CreatedAt,
+		CancelledAt: a.CancelledAt,
+	}
+}
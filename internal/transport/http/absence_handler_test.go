@@ -0,0 +1,39 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAddAbsence_ValidationError(t *testing.T) {
+	h, _, _, _ := newTestHandler(t)
+
+	body := `{"user_id": "u1", "starts_at": "2030-01-02T00:00:00Z", "ends_at": "2030-01-01T00:00:00Z"}`
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users/addAbsence", strings.NewReader(body))
+
+	h.AddAbsence(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+	code, _ := decodeError(t, rr)
+	if code != codeValidationErr {
+		t.Fatalf("expected error code VALIDATION_ERROR, got %q", code)
+	}
+}
+
+func TestGetUserAbsences_MissingUserID(t *testing.T) {
+	h, _, _, _ := newTestHandler(t)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users/getAbsences", nil)
+
+	h.GetUserAbsences(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"log/slog"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/service"
+)
+
+// AddDelegation handles POST /users/addDelegation.
+// It hands the reviews of a user over to a substitute for a period and returns
+// the stored delegation.
+func (h *Handler) AddDelegation(w http.ResponseWriter, r *http.Request) {
+	var req AddDelegationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Warn("AddDelegation: invalid json", slog.Any("err", err))
+		writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "invalid json")
+		return
+	}
+
+	delegation := &domain.Delegation{
+		FromUserID: domain.UserID(req.FromUserID),
+		ToUserID:   domain.UserID(req.ToUserID),
+		StartsAt:   req.StartsAt.UTC(),
+		EndsAt:     req.EndsAt.UTC(),
+	}
+
+	if err := h.services.Users.AddDelegation(r.Context(), delegation); err != nil {
+		status, code := mapErrorToHTTP(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, DelegationResponse{Delegation: toDelegationDTO(*delegation)})
+}
+
+// GetUserDelegations handles GET /users/getDelegations.
+// It expects a "user_id" query parameter and returns all delegations made by the user.
+func (h *Handler) GetUserDelegations(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "user_id is required")
+		return
+	}
+
+	delegations, err := h.services.Users.ListDelegations(r.Context(), domain.UserID(userID))
+	if err != nil {
+		status, code := mapErrorToHTTP(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	resp := GetUserDelegationsResponse{
+		UserID:      userID,
+		Delegations: make([]DelegationDTO, 0, len(delegations)),
+	}
+	for _, d := range delegations {
+		resp.Delegations = append(resp.Delegations, toDelegationDTO(d))
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// CancelDelegation handles POST /users/cancelDelegation.
+// It cancels the delegation and returns it with the cancellation time.
+func (h *Handler) CancelDelegation(w http.ResponseWriter, r *http.Request) {
+	var req CancelDelegationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Warn("CancelDelegation: invalid json", slog.Any("err", err))
+		writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "invalid json")
+		return
+	}
+
+	delegation, err := h.services.Users.CancelDelegation(r.Context(), domain.DelegationID(req.DelegationID))
+	if err != nil {
+		status, code := mapErrorToHTTP(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, DelegationResponse{Delegation: toDelegationDTO(*delegation)})
+}
+
+// toDelegationDTO maps a domain delegation to its HTTP representation.
+func toDelegationDTO(d domain.Delegation) DelegationDTO {
+	dto := DelegationDTO{
+		DelegationID: int64(d.ID),
+		FromUserID:   string(d.FromUserID),
+		ToUserID:     string(d.ToUserID),
+		StartsAt:     d.StartsAt,
+		EndsAt:       d.EndsAt,
+		CreatedAt:    d.CreatedAt,
+		CancelledAt:  d.CancelledAt,
+	}
+	if d.AbsenceID != nil {
+		id := int64(*d.AbsenceID)
+		dto.AbsenceID = &id
+	}
+	return dto
+}
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAddDelegation_ValidationError(t *testing.T) {
+	h, _, _, _ := newTestHandler(t)
+
+	body := `{"from_user_id": "u1", "to_user_id": "u1", "starts_at": "2030-01-01T00:00:00Z", "ends_at": "2030-01-02T00:00:00Z"}`
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users/addDelegation", strings.NewReader(body))
+
+	h.AddDelegation(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+	code, _ := decodeError(t, rr)
+	if code != codeValidationErr {
+		t.Fatalf("expected error code VALIDATION_ERROR, got %q", code)
+	}
+}
+
+func TestGetUserDelegations_MissingUserID(t *testing.T) {
+	h, _, _, _ := newTestHandler(t)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users/getDelegations", nil)
+
+	h.GetUserDelegations(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
@@ -31,18 +31,110 @@ type TeamAddResponse struct {
 	Team TeamDTO `json:"team"`
 }
 
+// TeamSettingsDTO represents reviewer assignment settings of a team
+// in HTTP requests and responses.
+type TeamSettingsDTO struct {
+	TeamName          string   `json:"team_name"`
+	ReviewersCount    int      `json:"reviewers_count"`
+	MinReviewers      int      `json:"min_reviewers"`
+	RequiredApprovals int      `json:"required_approvals"`
+	ReviewerStrategy  string   `json:"reviewer_strategy"`
+	FallbackTeams     []string `json:"fallback_teams"`
+	// ReviewSLAHours is the time reviewers have for the first review; 0 disables the SLA.
+	ReviewSLAHours        int  `json:"review_sla_hours"`
+	ReviewSLABusinessDays bool `json:"review_sla_business_days"`
+	ReviewSLAAutoReassign bool `json:"review_sla_auto_reassign"`
+	// ReviewRules override reviewers_count and required_approvals for pull requests
+	// with matching labels or priority.
+	ReviewRules []ReviewRuleDTO `json:"review_rules"`
+}
+
+// TeamSettingsUpdateDTO is the request body of POST /team/settings. Omitted
+// fields keep their stored values.
+type TeamSettingsUpdateDTO struct {
+	TeamName              string           `json:"team_name"`
+	ReviewersCount        *int             `json:"reviewers_count"`
+	MinReviewers          *int             `json:"min_reviewers"`
+	RequiredApprovals     *int             `json:"required_approvals"`
+	ReviewerStrategy      *string          `json:"reviewer_strategy"`
+	FallbackTeams         *[]string        `json:"fallback_teams"`
+	ReviewSLAHours        *int             `json:"review_sla_hours"`
+	ReviewSLABusinessDays *bool            `json:"review_sla_business_days"`
+	ReviewSLAAutoReassign *bool            `json:"review_sla_auto_reassign"`
+	ReviewRules           *[]ReviewRuleDTO `json:"review_rules"`
+}
+
+// ReviewRuleDTO is a per-team review rule. At least one of Label and Priority is set.
+type ReviewRuleDTO struct {
+	Label             string `json:"label,omitempty"`
+	Priority          string `json:"priority,omitempty"`
+	ReviewersCount    int    `json:"reviewers_count"`
+	RequiredApprovals int    `json:"required_approvals"`
+}
+
+// TeamDigestDTO is the JSON form of a team digest returned by GET /team/digest.
+type TeamDigestDTO struct {
+	TeamName        string                `json:"team_name"`
+	GeneratedAt     time.Time             `json:"generated_at"`
+	StaleAfterHours int                   `json:"stale_after_hours"`
+	PullRequests    []StalePullRequestDTO `json:"pull_requests"`
+}
+
+// StalePullRequestDTO describes a pull request waiting for reviews too long.
+type StalePullRequestDTO struct {
+	PullRequestID   string    `json:"pull_request_id"`
+	PullRequestName string    `json:"pull_request_name"`
+	AuthorID        string    `json:"author_id"`
+	CreatedAt       time.Time `json:"createdAt"`
+	// WaitingSince is when the longest waiting pending reviewer was assigned.
+	WaitingSince time.Time `json:"waiting_since"`
+	// AgeHours is the number of whole hours since WaitingSince.
+	AgeHours         int      `json:"age_hours"`
+	PendingReviewers []string `json:"pending_reviewers"`
+}
+
+// DeactivateUsersRequest is the request body for POST /team/deactivateUsers.
+// Either TeamName, UserIDs or both must be set.
+type DeactivateUsersRequest struct {
+	TeamName string   `json:"team_name"`
+	UserIDs  []string `json:"user_ids"`
+}
+
+// ReviewerReassignmentDTO describes a review handed over from a deactivated user.
+// NewUserID is empty when the review slot was dropped.
+type ReviewerReassignmentDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldUserID     string `json:"old_user_id"`
+	NewUserID     string `json:"new_user_id,omitempty"`
+	Dropped       bool   `json:"dropped"`
+}
+
+// DeactivateUsersResponse is the response body for POST /team/deactivateUsers.
+type DeactivateUsersResponse struct {
+	DeactivatedUsers []string                  `json:"deactivated_users"`
+	Reassignments    []ReviewerReassignmentDTO `json:"reassignments"`
+}
+
 // SetUserActiveRequest is the request body for toggling user activity.
 type SetUserActiveRequest struct {
 	UserID   string `json:"user_id"`
 	IsActive bool   `json:"is_active"`
 }
 
+// SetUserMaxOpenReviewsRequest is the request body for changing user review capacity.
+// A null MaxOpenReviews removes the limit.
+type SetUserMaxOpenReviewsRequest struct {
+	UserID         string `json:"user_id"`
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+}
+
 // UserDTO represents a user together with their team in HTTP responses.
 type UserDTO struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	UserID         string `json:"user_id"`
+	Username       string `json:"username"`
+	TeamName       string `json:"team_name"`
+	IsActive       bool   `json:"is_active"`
+	MaxOpenReviews *int   `json:"max_open_reviews"`
 }
 
 // UserResponse wraps a single user under the "user" field.
@@ -50,6 +142,78 @@ type UserResponse struct {
 	User UserDTO `json:"user"`
 }
 
+// AddAbsenceRequest is the request body for POST /users/addAbsence.
+type AddAbsenceRequest struct {
+	UserID     string    `json:"user_id"`
+	StartsAt   time.Time `json:"starts_at"`
+	EndsAt     time.Time `json:"ends_at"`
+	DelegateID string    `json:"delegate_id"`
+}
+
+// CancelAbsenceRequest is the request body for POST /users/cancelAbsence.
+type CancelAbsenceRequest struct {
+	AbsenceID int64 `json:"absence_id"`
+}
+
+// AbsenceDTO represents an out-of-office window of a user.
+type AbsenceDTO struct {
+	AbsenceID   int64      `json:"absence_id"`
+	UserID      string     `json:"user_id"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	DelegateID  string     `json:"delegate_id,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CancelledAt *time.Time `json:"cancelled_at"`
+}
+
+// AbsenceResponse wraps a single absence under the "absence" field.
+type AbsenceResponse struct {
+	Absence AbsenceDTO `json:"absence"`
+}
+
+// GetUserAbsencesResponse is the response body for GET /users/getAbsences.
+type GetUserAbsencesResponse struct {
+	UserID   string       `json:"user_id"`
+	Absences []AbsenceDTO `json:"absences"`
+}
+
+// AddDelegationRequest is the request body for POST /users/addDelegation.
+type AddDelegationRequest struct {
+	FromUserID string    `json:"from_user_id"`
+	ToUserID   string    `json:"to_user_id"`
+	StartsAt   time.Time `json:"starts_at"`
+	EndsAt     time.Time `json:"ends_at"`
+}
+
+// CancelDelegationRequest is the request body for POST /users/cancelDelegation.
+type CancelDelegationRequest struct {
+	DelegationID int64 `json:"delegation_id"`
+}
+
+// DelegationDTO represents a delegation of a user's reviews to a substitute.
+type DelegationDTO struct {
+	DelegationID int64     `json:"delegation_id"`
+	FromUserID   string    `json:"from_user_id"`
+	ToUserID     string    `json:"to_user_id"`
+	StartsAt     time.Time `json:"starts_at"`
+	EndsAt       time.Time `json:"ends_at"`
+	// AbsenceID is set for delegations created by /users/addAbsence.
+	AbsenceID   *int64     `json:"absence_id,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CancelledAt *time.Time `json:"cancelled_at"`
+}
+
+// DelegationResponse wraps a single delegation under the "delegation" field.
+type DelegationResponse struct {
+	Delegation DelegationDTO `json:"delegation"`
+}
+
+// GetUserDelegationsResponse is the response body for GET /users/getDelegations.
+type GetUserDelegationsResponse struct {
+	UserID      string          `json:"user_id"`
+	Delegations []DelegationDTO `json:"delegations"`
+}
+
 // PullRequestShortDTO is a compact representation of a pull request
 // used in lists, e.g. for user reviews.
 type PullRequestShortDTO struct {
@@ -60,40 +224,146 @@ type PullRequestShortDTO struct {
 }
 
 // GetUserReviewResponse is the response body for listing pull requests
-// where a user is assigned as a reviewer.
+// where a user is assigned as a reviewer. NextCursor is omitted on the last page.
 type GetUserReviewResponse struct {
 	UserID       string                `json:"user_id"`
 	PullRequests []PullRequestShortDTO `json:"pull_requests"`
+	NextCursor   string                `json:"next_cursor,omitempty"`
 }
 
 // CreatePullRequestRequest is the request body for creating a new pull request.
+// A draft pull request gets no reviewers until it is marked ready.
+// An omitted priority means NORMAL.
 type CreatePullRequestRequest struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
+	PullRequestID   string   `json:"pull_request_id"`
+	PullRequestName string   `json:"pull_request_name"`
+	AuthorID        string   `json:"author_id"`
+	Draft           bool     `json:"draft"`
+	Labels          []string `json:"labels"`
+	Priority        string   `json:"priority"`
+}
+
+// UpdatePullRequestRequest is the request body for POST /pullRequest/update.
+// Omitted fields keep their values; labels, when given, replace all labels.
+type UpdatePullRequestRequest struct {
+	PullRequestID   string    `json:"pull_request_id"`
+	PullRequestName *string   `json:"pull_request_name"`
+	Labels          *[]string `json:"labels"`
+	Priority        *string   `json:"priority"`
+}
+
+// PullRequestStatusRequest is the request body for the status transitions
+// markReady, close and reopen.
+type PullRequestStatusRequest struct {
+	PullRequestID string `json:"pull_request_id"`
 }
 
 // MergePullRequestRequest is the request body for merging a pull request.
+// Force skips the required approvals check and is only allowed with the admin token.
 type MergePullRequestRequest struct {
 	PullRequestID string `json:"pull_request_id"`
+	Force         bool   `json:"force"`
+}
+
+// SubmitReviewRequest is the request body for POST /pullRequest/review.
+type SubmitReviewRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+	Decision      string `json:"decision"`
+}
+
+// ReviewDTO represents the latest decision of a reviewer on a pull request.
+type ReviewDTO struct {
+	PullRequestID string    `json:"pull_request_id"`
+	UserID        string    `json:"user_id"`
+	Decision      string    `json:"decision"`
+	SubmittedAt   time.Time `json:"submitted_at"`
+}
+
+// ReviewResponse wraps a single review under the "review" field.
+type ReviewResponse struct {
+	Review ReviewDTO `json:"review"`
 }
 
 // ReassignReviewerRequest is the request body for reassigning a reviewer
-// on a pull request.
+// on a pull request. When NewUserID is set, that user becomes the reviewer
+// instead of an automatically picked one.
 type ReassignReviewerRequest struct {
 	PullRequestID string `json:"pull_request_id"`
 	OldUserID     string `json:"old_user_id"`
+	NewUserID     string `json:"new_user_id"`
+}
+
+// PullRequestReviewerRequest is the request body for manually adding or
+// removing a single reviewer of a pull request.
+type PullRequestReviewerRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+}
+
+// PullRequestDependencyRequest is the request body for adding or removing a
+// dependency of one pull request on another.
+type PullRequestDependencyRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	DependsOn     string `json:"depends_on"`
 }
 
 // PullRequestDTO represents a detailed pull request in HTTP responses.
 type PullRequestDTO struct {
-	PullRequestID     string     `json:"pull_request_id"`
-	PullRequestName   string     `json:"pull_request_name"`
-	AuthorID          string     `json:"author_id"`
-	Status            string     `json:"status"`
-	AssignedReviewers []string   `json:"assigned_reviewers"`
-	CreatedAt         time.Time  `json:"createdAt"`
-	MergedAt          *time.Time `json:"mergedAt"`
+	PullRequestID   string   `json:"pull_request_id"`
+	PullRequestName string   `json:"pull_request_name"`
+	AuthorID        string   `json:"author_id"`
+	Status          string   `json:"status"`
+	Labels          []string `json:"labels"`
+	Priority        string   `json:"priority"`
+	// Version is also sent as the ETag header; it is omitted for previews.
+	Version           int64    `json:"version,omitempty"`
+	AssignedReviewers []string `json:"assigned_reviewers"`
+	// FallbackReviewers maps reviewers taken from a fallback team to that team's name.
+	FallbackReviewers map[string]string `json:"fallback_reviewers,omitempty"`
+	// DelegatedReviewers maps reviewers assigned as delegates to the absent users they replace.
+	DelegatedReviewers map[string]string `json:"delegated_reviewers,omitempty"`
+	// ReviewDueAt maps reviewers to their review deadline under the team's SLA.
+	ReviewDueAt map[string]time.Time `json:"review_due_at,omitempty"`
+	// OverdueReviewers lists reviewers who missed their review deadline.
+	OverdueReviewers []string `json:"overdue_reviewers,omitempty"`
+	// AssignmentExplanation is only filled when the client asks for it with ?explain=true.
+	AssignmentExplanation []CandidateExplanationDTO `json:"assignment_explanation,omitempty"`
+	// Dependencies is only filled by endpoints that return a single pull request
+	// with its dependency graph, such as GET /pullRequest/get.
+	Dependencies *DependencyGraphDTO `json:"dependencies,omitempty"`
+	CreatedAt    time.Time           `json:"createdAt"`
+	MergedAt     *time.Time          `json:"mergedAt"`
+	ClosedAt     *time.Time          `json:"closedAt,omitempty"`
+}
+
+// DependencyGraphDTO is the stack of pull requests connected by dependencies.
+type DependencyGraphDTO struct {
+	Nodes []DependencyNodeDTO `json:"nodes"`
+	Edges []DependencyEdgeDTO `json:"edges"`
+}
+
+// DependencyNodeDTO is a pull request in a dependency graph.
+type DependencyNodeDTO struct {
+	PullRequestID   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+	Status          string `json:"status"`
+}
+
+// DependencyEdgeDTO says that PullRequestID cannot be merged before DependsOn.
+type DependencyEdgeDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	DependsOn     string `json:"depends_on"`
+}
+
+// CandidateExplanationDTO describes why a candidate was picked or skipped as a reviewer.
+type CandidateExplanationDTO struct {
+	UserID        string `json:"user_id"`
+	TeamName      string `json:"team_name,omitempty"`
+	Picked        bool   `json:"picked"`
+	Reason        string `json:"reason"`
+	DelegateID    string `json:"delegate_id,omitempty"`
+	DelegatedFrom string `json:"delegated_from,omitempty"`
 }
 
 // PullRequestResponse wraps a single pull request under the "pr" field.
@@ -101,6 +371,35 @@ type PullRequestResponse struct {
 	PR PullRequestDTO `json:"pr"`
 }
 
+// PullRequestEventDTO is one entry of the pull request history.
+// Fields that do not apply to the event type are omitted.
+type PullRequestEventDTO struct {
+	ID            int64     `json:"id"`
+	Type          string    `json:"type"`
+	ActorID       string    `json:"actor_id,omitempty"`
+	OldReviewerID string    `json:"old_reviewer_id,omitempty"`
+	NewReviewerID string    `json:"new_reviewer_id,omitempty"`
+	OldStatus     string    `json:"old_status,omitempty"`
+	NewStatus     string    `json:"new_status,omitempty"`
+	Decision      string    `json:"decision,omitempty"`
+	Reason        string    `json:"reason,omitempty"`
+	Details       string    `json:"details,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// PullRequestHistoryResponse is the response body for GET /pullRequest/history.
+type PullRequestHistoryResponse struct {
+	PullRequestID string                `json:"pull_request_id"`
+	Events        []PullRequestEventDTO `json:"events"`
+}
+
+// ListPullRequestsResponse is the response body for GET /pullRequests.
+// NextCursor is omitted on the last page.
+type ListPullRequestsResponse struct {
+	Items      []PullRequestDTO `json:"items"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
 // ReassignReviewerResponse is the response body for successful reviewer reassignment.
 type ReassignReviewerResponse struct {
 	PR         PullRequestDTO `json:"pr"`
@@ -111,6 +410,7 @@ type ReassignReviewerResponse struct {
 type ReviewerStatsItemDTO struct {
 	ReviewerID  string `json:"reviewer_id"`
 	Assignments int    `json:"assignments"`
+	Overdue     int    `json:"overdue"`
 }
 
 // ReviewerStatsResponse is the response body for reviewer statistics.
@@ -6,6 +6,9 @@ import (
 	"github.com/juzu400/avito-internship/internal/service"
 )
 
+// errCodeUnauthorized is returned when an admin-only action lacks a valid admin token.
+const errCodeUnauthorized = "UNAUTHORIZED"
+
 // mapErrorToHTTP converts a domain/service error into an HTTP status code
 // and a stable error code used in JSON responses.
 func mapErrorToHTTP(err error) (status int, code string) {
@@ -24,9 +27,20 @@ func mapErrorToHTTP(err error) (status int, code string) {
 		return http.StatusBadRequest, code
 	case service.ErrCodePullRequestAlreadyExists,
 		service.ErrCodePullRequestAlreadyMerged,
+		service.ErrCodePullRequestNotOpen,
+		service.ErrCodeInvalidStatusTransition,
 		service.ErrCodeReviewerNotAssigned,
-		service.ErrCodeNoReviewerCandidates:
+		service.ErrCodeReviewerAlreadyAssigned,
+		service.ErrCodeNoReviewerCandidates,
+		service.ErrCodeReviewerAtCapacity,
+		service.ErrCodeNotApproved,
+		service.ErrCodeDependencyNotMerged,
+		service.ErrCodeDependencyCycle,
+		service.ErrCodeVersionConflict,
+		service.ErrCodeIdempotencyInProgress:
 		return http.StatusConflict, code
+	case service.ErrCodeIdempotencyKeyReused:
+		return http.StatusUnprocessableEntity, code
 	default:
 		return http.StatusInternalServerError, service.ErrCodeInternal
 	}
@@ -1,8 +1,15 @@
 package http
 
 import (
+	"crypto/subtle"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/juzu400/avito-internship/internal/domain"
 )
 
 // writeJSON writes the given value as a JSON response with the provided HTTP status code.
@@ -12,6 +19,25 @@ func writeJSON(w http.ResponseWriter, status int, v any) {
 	_ = json.NewEncoder(w).Encode(v)
 }
 
+// writePullRequest writes dto as a PullRequestResponse with the ETag of pr.
+func writePullRequest(w http.ResponseWriter, status int, pr *domain.PullRequest, dto PullRequestDTO) {
+	setETag(w, pr)
+	writeJSON(w, status, PullRequestResponse{PR: dto})
+}
+
+// setETag sets the ETag header to the version of pr. Clients send it back in
+// If-Match to change the pull request only if nobody changed it meanwhile.
+func setETag(w http.ResponseWriter, pr *domain.PullRequest) {
+	setVersionETag(w, pr.Version)
+}
+
+// setVersionETag sets the ETag header to the given pull request version.
+func setVersionETag(w http.ResponseWriter, version int64) {
+	if version > 0 {
+		w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+	}
+}
+
 // writeError builds a standard JSON error response with the given status, code and message
 func writeError(w http.ResponseWriter, status int, code, message string) {
 	resp := ErrorResponse{
@@ -23,3 +49,76 @@ func writeError(w http.ResponseWriter, status int, code, message string) {
 
 	writeJSON(w, status, resp)
 }
+
+// explainRequested reports whether the request asks for an assignment explanation
+// via the "explain" query flag. Values that are not valid booleans count as false.
+func explainRequested(r *http.Request) bool {
+	explain, err := strconv.ParseBool(r.URL.Query().Get("explain"))
+	return err == nil && explain
+}
+
+// isAdmin reports whether the request carries the admin token as
+// "Authorization: Bearer <token>". It is always false when no token is configured.
+func (h *Handler) isAdmin(r *http.Request) bool {
+	if h.adminToken == "" {
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) == 1
+}
+
+// parseStatuses collects pull request statuses from the "status" query parameter,
+// which may be repeated or comma-separated. Values are upper-cased; unknown ones are
+// left for the service to reject.
+func parseStatuses(values url.Values) []domain.PullRequestStatus {
+	var res []domain.PullRequestStatus
+	for _, st := range splitParam(values, "status") {
+		res = append(res, domain.PullRequestStatus(strings.ToUpper(st)))
+	}
+	return res
+}
+
+// parsePriorities collects pull request priorities from the "priority" query
+// parameter the same way parseStatuses does.
+func parsePriorities(values url.Values) []domain.PullRequestPriority {
+	var res []domain.PullRequestPriority
+	for _, p := range splitParam(values, "priority") {
+		res = append(res, domain.PullRequestPriority(strings.ToUpper(p)))
+	}
+	return res
+}
+
+// splitParam returns the non-empty values of a query parameter that may be
+// repeated or comma-separated.
+func splitParam(values url.Values, name string) []string {
+	var res []string
+	for _, v := range values[name] {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				res = append(res, part)
+			}
+		}
+	}
+	return res
+}
+
+// parsePage reads the "limit" and "cursor" query parameters of a paginated listing.
+// Missing values are returned as zero; malformed ones as domain.ErrValidation.
+func parsePage(values url.Values) (int, *domain.PullRequestCursor, error) {
+	var limit int
+	if v := values.Get("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil {
+			return 0, nil, fmt.Errorf("%w: limit must be an integer", domain.ErrValidation)
+		}
+	}
+
+	var cursor *domain.PullRequestCursor
+	if v := values.Get("cursor"); v != "" {
+		var err error
+		if cursor, err = domain.DecodePullRequestCursor(v); err != nil {
+			return 0, nil, err
+		}
+	}
+	return limit, cursor, nil
+}
@@ -46,7 +46,7 @@ func newTestHandler(t *testing.T) (*Handler,
 	}
 
 	log := newTestLogger()
-	services := service.NewServices(log, repos)
+	services := service.NewServices(log, repos, service.Config{})
 
 	h := &Handler{
 		log:      log.With(slog.String("layer", "http")),
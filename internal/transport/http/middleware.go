@@ -0,0 +1,214 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/service"
+)
+
+// actorHeader names the user on whose behalf a request is made.
+const actorHeader = "X-Actor-ID"
+
+// withActor attributes changes made by the request to the user from the
+// X-Actor-ID header, so they show up as the actor in pull request history.
+// Requests without the header keep the per-operation default actor.
+func withActor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if actor := r.Header.Get(actorHeader); actor != "" {
+			r = r.WithContext(domain.WithActor(r.Context(), domain.UserID(actor)))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ifMatchHeader carries the ETag of the pull request version a change is based on.
+const ifMatchHeader = "If-Match"
+
+// withIfMatch makes changes of a pull request conditional on the ETag from the
+// If-Match header: if the pull request has another version by the time it is
+// changed, the request fails with VERSION_CONFLICT. Requests without the header
+// or with "If-Match: *" are not checked.
+func withIfMatch(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if value := r.Header.Get(ifMatchHeader); value != "" && value != "*" {
+			version, ok := parseETag(value)
+			if !ok {
+				writeError(w, http.StatusBadRequest, service.ErrCodeValidation,
+					`If-Match must be a single ETag such as "3"`)
+				return
+			}
+			r = r.WithContext(domain.WithExpectedVersion(r.Context(), version))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// parseETag extracts the pull request version from a strong ETag.
+func parseETag(value string) (int64, bool) {
+	value = strings.TrimSpace(value)
+	unquoted, err := strconv.Unquote(value)
+	if err != nil || !strings.HasPrefix(value, `"`) {
+		return 0, false
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version < 1 {
+		return 0, false
+	}
+	return version, true
+}
+
+const (
+	// idempotencyKeyHeader lets clients retry a POST request without applying it twice.
+	idempotencyKeyHeader = "Idempotency-Key"
+	// replayedHeader marks a response that was stored for an earlier request with the same key.
+	replayedHeader = "Idempotent-Replayed"
+	// maxIdempotentBodySize caps the body of a request that is read and hashed
+	// before it is handled.
+	maxIdempotentBodySize = 1 << 20
+)
+
+// replayedHeaders lists the response headers that are stored and replayed with the body.
+var replayedHeaders = []string{"Content-Type", "ETag"}
+
+// withIdempotencyKey makes POST requests with an Idempotency-Key header safe to
+// retry: the first request is handled and its response stored, and a retry with
+// the same key gets the stored response without being handled again. A key is
+// scoped by the method, path and X-Actor-ID of the request. Reusing a key in that
+// scope with another query, If-Match or body yields 422 IDEMPOTENCY_KEY_REUSED,
+// and a retry that arrives while the first request is still running yields
+// 409 IDEMPOTENCY_IN_PROGRESS. Responses with 5xx status and panics are not
+// stored, so a request that failed that way is handled again on retry. Bodies
+// larger than maxIdempotentBodySize are rejected with 413.
+func (h *Handler) withIdempotencyKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientKey := r.Header.Get(idempotencyKeyHeader)
+		if r.Method != http.MethodPost || clientKey == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key := domain.IdempotencyKey{
+			Method:  r.Method,
+			Path:    r.URL.Path,
+			ActorID: domain.UserID(r.Header.Get(actorHeader)),
+			Key:     clientKey,
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodySize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				writeError(w, http.StatusRequestEntityTooLarge, service.ErrCodeValidation,
+					fmt.Sprintf("request body is larger than %d bytes", tooLarge.Limit))
+				return
+			}
+			h.log.Warn("withIdempotencyKey: read body failed", slog.Any("err", err))
+			writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "cannot read request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// The outcome is recorded even if the client gives up waiting.
+		ctx := context.WithoutCancel(r.Context())
+		stored, err := h.services.Idempotency.Begin(ctx, key, requestHash(r, body))
+		if err != nil {
+			status, code := mapErrorToHTTP(err)
+			writeError(w, status, code, err.Error())
+			return
+		}
+		if stored != nil {
+			for name, value := range stored.Header {
+				w.Header().Set(name, value)
+			}
+			w.Header().Set(replayedHeader, "true")
+			w.WriteHeader(stored.StatusCode)
+			_, _ = w.Write(stored.Body)
+			return
+		}
+
+		defer func() {
+			if p := recover(); p != nil {
+				h.releaseIdempotencyKey(ctx, r, key)
+				panic(p)
+			}
+		}()
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		if rec.status >= http.StatusInternalServerError {
+			h.releaseIdempotencyKey(ctx, r, key)
+			return
+		}
+		header := make(map[string]string, len(replayedHeaders))
+		for _, name := range replayedHeaders {
+			if value := rec.Header().Get(name); value != "" {
+				header[name] = value
+			}
+		}
+		err = h.services.Idempotency.Complete(ctx, key, domain.IdempotentResponse{
+			StatusCode: rec.status,
+			Header:     header,
+			Body:       rec.body.Bytes(),
+		})
+		if err != nil {
+			_, code := mapErrorToHTTP(err)
+			h.log.Error("withIdempotencyKey: Complete failed",
+				slog.String("idempotency_key", key.Key),
+				slog.String("path", r.URL.Path),
+				slog.String("error_code", code),
+				slog.Any("err", err),
+			)
+		}
+	})
+}
+
+// releaseIdempotencyKey frees key after a request whose response must not be replayed.
+func (h *Handler) releaseIdempotencyKey(ctx context.Context, r *http.Request, key domain.IdempotencyKey) {
+	if err := h.services.Idempotency.Release(ctx, key); err != nil {
+		_, code := mapErrorToHTTP(err)
+		h.log.Error("withIdempotencyKey: Release failed",
+			slog.String("idempotency_key", key.Key),
+			slog.String("path", r.URL.Path),
+			slog.String("error_code", code),
+			slog.Any("err", err),
+		)
+	}
+}
+
+// requestHash fingerprints the method, path with query, If-Match header and body
+// of the request.
+func requestHash(r *http.Request, body []byte) string {
+	sum := sha256.New()
+	sum.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	sum.Write([]byte(ifMatchHeader + ": " + r.Header.Get(ifMatchHeader) + "\n"))
+	sum.Write(body)
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// responseRecorder passes a response through while keeping its status and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
@@ -0,0 +1,290 @@
+package http
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/repository"
+	"github.com/juzu400/avito-internship/internal/repository/mocks"
+	"github.com/juzu400/avito-internship/internal/service"
+)
+
+func TestWithActor_SetsActorFromHeader(t *testing.T) {
+	var got domain.UserID
+	next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = domain.AuditFromContext(r.Context()).ActorID
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/reassign", nil)
+	req.Header.Set("X-Actor-ID", "u1")
+
+	withActor(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	if got != "u1" {
+		t.Fatalf("expected actor u1, got %q", got)
+	}
+}
+
+func TestWithIfMatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+		wantOK     bool
+		want       int64
+	}{
+		{name: "no header", wantStatus: http.StatusOK},
+		{name: "any version", header: "*", wantStatus: http.StatusOK},
+		{name: "etag", header: `"4"`, wantStatus: http.StatusOK, wantOK: true, want: 4},
+		{name: "unquoted", header: "4", wantStatus: http.StatusBadRequest},
+		{name: "weak", header: `W/"4"`, wantStatus: http.StatusBadRequest},
+		{name: "list", header: `"4", "5"`, wantStatus: http.StatusBadRequest},
+		{name: "zero", header: `"0"`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got int64
+			var ok bool
+			next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				got, ok = domain.ExpectedVersion(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/pullRequest/reassign", nil)
+			if tt.header != "" {
+				req.Header.Set("If-Match", tt.header)
+			}
+			rr := httptest.NewRecorder()
+
+			withIfMatch(next).ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+			if ok != tt.wantOK || got != tt.want {
+				t.Fatalf("expected version %d (%v), got %d (%v)", tt.want, tt.wantOK, got, ok)
+			}
+		})
+	}
+}
+
+func newIdempotencyHandler(t *testing.T) (*Handler, *mocks.MockIdempotencyRepository) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	keys := mocks.NewMockIdempotencyRepository(ctrl)
+	log := newTestLogger()
+	services := service.NewServices(log, &repository.Repositories{Idempotency: keys}, service.Config{})
+
+	return &Handler{log: log.With(slog.String("layer", "http")), services: services}, keys
+}
+
+// retryKey is the scoped key of requests made by newIdempotentRequest.
+var retryKey = domain.IdempotencyKey{Method: http.MethodPost, Path: "/pullRequest/reassign", Key: "retry-1"}
+
+func newIdempotentRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/reassign", strings.NewReader(body))
+	req.Header.Set("Idempotency-Key", "retry-1")
+	return req
+}
+
+func TestWithIdempotencyKey_StoresFirstResponse(t *testing.T) {
+	h, keys := newIdempotencyHandler(t)
+
+	keys.EXPECT().Reserve(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+	keys.EXPECT().
+		Complete(gomock.Any(), retryKey, domain.IdempotentResponse{
+			StatusCode: http.StatusOK,
+			Header:     map[string]string{"Content-Type": "application/json", "ETag": `"2"`},
+			Body:       []byte(`{"replaced_by":"u3"}` + "\n"),
+		}).
+		Return(nil)
+
+	calls := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.Header().Set("ETag", `"2"`)
+		writeJSON(w, http.StatusOK, map[string]string{"replaced_by": "u3"})
+	})
+
+	rr := httptest.NewRecorder()
+	h.withIdempotencyKey(next).ServeHTTP(rr, newIdempotentRequest(`{"pull_request_id":"pr-1"}`))
+
+	if calls != 1 || rr.Code != http.StatusOK {
+		t.Fatalf("expected one call with status 200, got %d calls with status %d", calls, rr.Code)
+	}
+	if rr.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatal("first response must not be marked as replayed")
+	}
+}
+
+func TestWithIdempotencyKey_ReplaysStoredResponse(t *testing.T) {
+	h, keys := newIdempotencyHandler(t)
+
+	var hash string
+	keys.EXPECT().
+		Reserve(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *domain.IdempotentRequest, _ any) (*domain.IdempotentRequest, error) {
+			hash = req.RequestHash
+			return &domain.IdempotentRequest{
+				Key:         req.Key,
+				RequestHash: req.RequestHash,
+				Response: &domain.IdempotentResponse{
+					StatusCode: http.StatusOK,
+					Header:     map[string]string{"Content-Type": "application/json"},
+					Body:       []byte(`{"replaced_by":"u3"}`),
+				},
+			}, nil
+		})
+
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Fatal("retried request must not be handled again")
+	})
+
+	rr := httptest.NewRecorder()
+	h.withIdempotencyKey(next).ServeHTTP(rr, newIdempotentRequest(`{"pull_request_id":"pr-1"}`))
+
+	if hash == "" {
+		t.Fatal("expected request hash to be passed")
+	}
+	if rr.Code != http.StatusOK || rr.Body.String() != `{"replaced_by":"u3"}` {
+		t.Fatalf("expected stored response, got %d %s", rr.Code, rr.Body.String())
+	}
+	if rr.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatal("expected replayed response to be marked")
+	}
+}
+
+func TestWithIdempotencyKey_ReusedKeyReturns422(t *testing.T) {
+	h, keys := newIdempotencyHandler(t)
+
+	keys.EXPECT().
+		Reserve(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&domain.IdempotentRequest{Key: retryKey, RequestHash: "other"}, nil)
+
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Fatal("request with a reused key must not be handled")
+	})
+
+	rr := httptest.NewRecorder()
+	h.withIdempotencyKey(next).ServeHTTP(rr, newIdempotentRequest(`{"pull_request_id":"pr-2"}`))
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+	code, _ := decodeError(t, rr)
+	if code != "IDEMPOTENCY_KEY_REUSED" {
+		t.Fatalf("expected error code IDEMPOTENCY_KEY_REUSED, got %q", code)
+	}
+}
+
+func TestWithIdempotencyKey_ReleasesKeyOnServerError(t *testing.T) {
+	h, keys := newIdempotencyHandler(t)
+
+	keys.EXPECT().Reserve(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+	keys.EXPECT().Release(gomock.Any(), retryKey).Return(nil)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeError(w, http.StatusInternalServerError, service.ErrCodeInternal, "db down")
+	})
+
+	rr := httptest.NewRecorder()
+	h.withIdempotencyKey(next).ServeHTTP(rr, newIdempotentRequest(`{}`))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, rr.Code)
+	}
+}
+
+func TestWithIdempotencyKey_ReleasesKeyOnPanic(t *testing.T) {
+	h, keys := newIdempotencyHandler(t)
+
+	keys.EXPECT().Reserve(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+	keys.EXPECT().Release(gomock.Any(), retryKey).Return(nil)
+
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	})
+
+	defer func() {
+		if p := recover(); p != "boom" {
+			t.Fatalf("expected the panic to be re-raised, got %v", p)
+		}
+	}()
+	h.withIdempotencyKey(next).ServeHTTP(httptest.NewRecorder(), newIdempotentRequest(`{}`))
+}
+
+func TestWithIdempotencyKey_ScopesKeyByActor(t *testing.T) {
+	h, keys := newIdempotencyHandler(t)
+
+	want := retryKey
+	want.ActorID = "u1"
+	keys.EXPECT().
+		Reserve(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *domain.IdempotentRequest, _ any) (*domain.IdempotentRequest, error) {
+			if req.Key != want {
+				t.Fatalf("expected key %+v, got %+v", want, req.Key)
+			}
+			return nil, nil
+		})
+	keys.EXPECT().Complete(gomock.Any(), want, gomock.Any()).Return(nil)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := newIdempotentRequest(`{}`)
+	req.Header.Set("X-Actor-ID", "u1")
+	h.withIdempotencyKey(next).ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestRequestHash_IncludesIfMatch(t *testing.T) {
+	body := []byte(`{"pull_request_id":"pr-1"}`)
+
+	req := newIdempotentRequest(string(body))
+	req.Header.Set("If-Match", `"1"`)
+	first := requestHash(req, body)
+
+	req.Header.Set("If-Match", `"2"`)
+	if requestHash(req, body) == first {
+		t.Fatal("expected requests with different If-Match to hash differently")
+	}
+}
+
+func TestWithIdempotencyKey_RejectsLargeBody(t *testing.T) {
+	h, _ := newIdempotencyHandler(t)
+
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Fatal("request with a too large body must not be handled")
+	})
+
+	rr := httptest.NewRecorder()
+	h.withIdempotencyKey(next).ServeHTTP(rr, newIdempotentRequest(strings.Repeat("x", maxIdempotentBodySize+1)))
+
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected status %d, got %d", http.StatusRequestEntityTooLarge, rr.Code)
+	}
+}
+
+func TestWithIdempotencyKey_WithoutKeyPassesThrough(t *testing.T) {
+	h, _ := newIdempotencyHandler(t)
+
+	calls := 0
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) { calls++ })
+
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", strings.NewReader(`{}`))
+	h.withIdempotencyKey(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	if calls != 1 {
+		t.Fatalf("expected request to be handled once, got %d", calls)
+	}
+}
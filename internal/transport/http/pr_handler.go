@@ -1,8 +1,13 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"log/slog"
 
@@ -12,7 +17,9 @@ import (
 
 // CreatePullRequest handles POST /pullRequest/create.
 // It decodes the request body, delegates creation to the PullRequestService
-// and returns the created pull request on success.
+// and returns the created pull request on success. With "draft": true the pull
+// request is created as DRAFT without reviewers. With ?explain=true the
+// response also explains the reviewer choice.
 func (h *Handler) CreatePullRequest(w http.ResponseWriter, r *http.Request) {
 	var req CreatePullRequestRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -21,11 +28,17 @@ func (h *Handler) CreatePullRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, err := h.services.PullRequests.Create(
+	create := h.services.PullRequests.Create
+	if req.Draft {
+		create = h.services.PullRequests.CreateDraft
+	}
+
+	pr, err := create(
 		r.Context(),
 		domain.PullRequestID(req.PullRequestID),
 		req.PullRequestName,
 		domain.UserID(req.AuthorID),
+		req.attributes(),
 	)
 	if err != nil {
 		status, code := mapErrorToHTTP(err)
@@ -40,13 +53,228 @@ func (h *Handler) CreatePullRequest(w http.ResponseWriter, r *http.Request) {
 	}
 
 	prDTO := toPullRequestDTO(pr)
-	resp := PullRequestResponse{PR: prDTO}
-	writeJSON(w, http.StatusCreated, resp)
+	if explainRequested(r) {
+		prDTO.AssignmentExplanation = toExplanationDTO(pr.AssignmentExplanation)
+	}
+	writePullRequest(w, http.StatusCreated, pr, prDTO)
+}
+
+// PreviewAssignment handles POST /pullRequest/previewAssignment.
+// It accepts the same body as /pullRequest/create and returns the pull request
+// that would be created, with its reviewers, without storing it.
+// With ?explain=true the response also explains the reviewer choice.
+func (h *Handler) PreviewAssignment(w http.ResponseWriter, r *http.Request) {
+	var req CreatePullRequestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Warn("PreviewAssignment: invalid json", slog.Any("err", err))
+		writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "invalid json")
+		return
+	}
+
+	pr, err := h.services.PullRequests.PreviewAssignment(
+		r.Context(),
+		domain.PullRequestID(req.PullRequestID),
+		req.PullRequestName,
+		domain.UserID(req.AuthorID),
+		req.attributes(),
+	)
+	if err != nil {
+		status, code := mapErrorToHTTP(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	prDTO := toPullRequestDTO(pr)
+	if explainRequested(r) {
+		prDTO.AssignmentExplanation = toExplanationDTO(pr.AssignmentExplanation)
+	}
+	writeJSON(w, http.StatusOK, PullRequestResponse{PR: prDTO})
+}
+
+// attributes returns the optional labels and priority of the new pull request.
+// The priority is upper-cased; validation is left to the service.
+func (req CreatePullRequestRequest) attributes() domain.PullRequestAttributes {
+	return domain.PullRequestAttributes{
+		Labels:   req.Labels,
+		Priority: domain.PullRequestPriority(strings.ToUpper(req.Priority)),
+	}
+}
+
+// GetPullRequest handles GET /pullRequest/get.
+// It expects a "pull_request_id" query parameter and returns the pull request
+// with its dependency graph.
+func (h *Handler) GetPullRequest(w http.ResponseWriter, r *http.Request) {
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "pull_request_id is required")
+		return
+	}
+
+	pr, err := h.services.PullRequests.Get(r.Context(), domain.PullRequestID(prID))
+	if err != nil {
+		status, code := mapErrorToHTTP(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	writePullRequest(w, http.StatusOK, pr, toPullRequestDTO(pr))
+}
+
+// GetPullRequestHistory handles GET /pullRequest/history.
+// It expects a "pull_request_id" query parameter and returns every recorded
+// change of the pull request, oldest first.
+func (h *Handler) GetPullRequestHistory(w http.ResponseWriter, r *http.Request) {
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "pull_request_id is required")
+		return
+	}
+
+	events, err := h.services.PullRequests.History(r.Context(), domain.PullRequestID(prID))
+	if err != nil {
+		status, code := mapErrorToHTTP(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	resp := PullRequestHistoryResponse{
+		PullRequestID: prID,
+		Events:        make([]PullRequestEventDTO, 0, len(events)),
+	}
+	for _, ev := range events {
+		resp.Events = append(resp.Events, PullRequestEventDTO{
+			ID:            int64(ev.ID),
+			Type:          string(ev.Type),
+			ActorID:       string(ev.ActorID),
+			OldReviewerID: string(ev.OldReviewerID),
+			NewReviewerID: string(ev.NewReviewerID),
+			OldStatus:     string(ev.OldStatus),
+			NewStatus:     string(ev.NewStatus),
+			Decision:      string(ev.Decision),
+			Reason:        string(ev.Reason),
+			Details:       ev.Details,
+			CreatedAt:     ev.CreatedAt,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ListPullRequests handles GET /pullRequests.
+// It returns one page of pull requests matching the query filters together with
+// a cursor for the next page.
+func (h *Handler) ListPullRequests(w http.ResponseWriter, r *http.Request) {
+	q, err := parsePullRequestListQuery(r.URL.Query())
+	if err != nil {
+		status, code := mapErrorToHTTP(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	page, err := h.services.PullRequests.List(r.Context(), q)
+	if err != nil {
+		status, code := mapErrorToHTTP(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	resp := ListPullRequestsResponse{Items: make([]PullRequestDTO, 0, len(page.Items))}
+	for _, pr := range page.Items {
+		resp.Items = append(resp.Items, toPullRequestDTO(pr))
+	}
+	if page.NextCursor != nil {
+		resp.NextCursor = page.NextCursor.Encode()
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// parsePullRequestListQuery builds a listing query from URL parameters. Statuses may be
+// repeated or comma-separated; dates are RFC 3339. Unset parameters are left for the
+// service to default. Malformed values are reported as domain.ErrValidation.
+func parsePullRequestListQuery(values url.Values) (domain.PullRequestListQuery, error) {
+	q := domain.PullRequestListQuery{
+		Filter: domain.PullRequestFilter{
+			AuthorID:     domain.UserID(values.Get("author_id")),
+			ReviewerID:   domain.UserID(values.Get("reviewer_id")),
+			TeamName:     values.Get("team_name"),
+			NameContains: values.Get("name"),
+		},
+		SortBy: domain.PullRequestSortField(values.Get("sort")),
+		Order:  domain.SortOrder(values.Get("order")),
+	}
+
+	q.Filter.Statuses = parseStatuses(values)
+	q.Filter.Priorities = parsePriorities(values)
+	q.Filter.Labels = splitParam(values, "label")
+
+	times := []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"created_from", &q.Filter.CreatedFrom},
+		{"created_to", &q.Filter.CreatedTo},
+		{"merged_from", &q.Filter.MergedFrom},
+		{"merged_to", &q.Filter.MergedTo},
+	}
+	for _, t := range times {
+		v := values.Get(t.name)
+		if v == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return q, fmt.Errorf("%w: %s must be an RFC 3339 timestamp", domain.ErrValidation, t.name)
+		}
+		*t.dst = &parsed
+	}
+
+	var err error
+	if q.Limit, q.After, err = parsePage(values); err != nil {
+		return q, err
+	}
+
+	return q, nil
+}
+
+// UpdatePullRequest handles POST /pullRequest/update.
+// It changes mutable metadata of the pull request and returns its new state.
+func (h *Handler) UpdatePullRequest(w http.ResponseWriter, r *http.Request) {
+	var req UpdatePullRequestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Warn("UpdatePullRequest: invalid json", slog.Any("err", err))
+		writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "invalid json")
+		return
+	}
+
+	upd := domain.PullRequestUpdate{
+		Name:   req.PullRequestName,
+		Labels: req.Labels,
+	}
+	if req.Priority != nil {
+		priority := domain.PullRequestPriority(strings.ToUpper(*req.Priority))
+		upd.Priority = &priority
+	}
+
+	pr, err := h.services.PullRequests.Update(r.Context(), domain.PullRequestID(req.PullRequestID), upd)
+	if err != nil {
+		status, code := mapErrorToHTTP(err)
+		h.log.Error("UpdatePullRequest failed",
+			slog.String("pull_request_id", req.PullRequestID),
+			slog.String("error_code", code),
+			slog.Any("err", err),
+		)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	writePullRequest(w, http.StatusOK, pr, toPullRequestDTO(pr))
 }
 
 // MergePullRequest handles POST /pullRequest/merge.
 // It decodes the request body, calls PullRequestService.Merge and returns
-// the resulting pull request state.
+// the resulting pull request state. With "force": true it calls ForceMerge
+// instead, which requires the admin token.
 func (h *Handler) MergePullRequest(w http.ResponseWriter, r *http.Request) {
 	var req MergePullRequestRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -55,21 +283,65 @@ func (h *Handler) MergePullRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, err := h.services.PullRequests.Merge(r.Context(), domain.PullRequestID(req.PullRequestID))
+	merge := h.services.PullRequests.Merge
+	if req.Force {
+		if !h.isAdmin(r) {
+			h.log.Warn("MergePullRequest: force without admin token",
+				slog.String("pull_request_id", req.PullRequestID),
+			)
+			writeError(w, http.StatusUnauthorized, errCodeUnauthorized, "admin token required for force merge")
+			return
+		}
+		merge = h.services.PullRequests.ForceMerge
+	}
+
+	pr, err := merge(r.Context(), domain.PullRequestID(req.PullRequestID))
 	if err != nil {
 		status, code := mapErrorToHTTP(err)
 		writeError(w, status, code, err.Error())
 		return
 	}
 
-	prDTO := toPullRequestDTO(pr)
-	resp := PullRequestResponse{PR: prDTO}
-	writeJSON(w, http.StatusOK, resp)
+	writePullRequest(w, http.StatusOK, pr, toPullRequestDTO(pr))
+}
+
+// SubmitReview handles POST /pullRequest/review.
+// It stores the decision of an assigned reviewer and returns the saved review
+// with the new pull request version as the ETag.
+func (h *Handler) SubmitReview(w http.ResponseWriter, r *http.Request) {
+	var req SubmitReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Warn("SubmitReview: invalid json", slog.Any("err", err))
+		writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "invalid json")
+		return
+	}
+
+	review, err := h.services.PullRequests.SubmitReview(
+		r.Context(),
+		domain.PullRequestID(req.PullRequestID),
+		domain.UserID(req.UserID),
+		domain.ReviewDecision(req.Decision),
+	)
+	if err != nil {
+		status, code := mapErrorToHTTP(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	setVersionETag(w, review.PullRequestVersion)
+	writeJSON(w, http.StatusOK, ReviewResponse{Review: ReviewDTO{
+		PullRequestID: string(review.PullRequestID),
+		UserID:        string(review.ReviewerID),
+		Decision:      string(review.Decision),
+		SubmittedAt:   review.SubmittedAt,
+	}})
 }
 
 // ReassignReviewer handles POST /pullRequest/reassign.
-// It decodes the request body, calls PullRequestService.ReassignReviewer and
-// returns the updated pull request together with the new reviewer ID.
+// It decodes the request body, calls PullRequestService.ReassignReviewer (or
+// ReassignReviewerTo when new_user_id is given) and returns the updated pull
+// request together with the new reviewer ID.
+// With ?explain=true the response also explains the reviewer choice.
 func (h *Handler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 	var req ReassignReviewerRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -77,16 +349,32 @@ func (h *Handler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "invalid json")
 		return
 	}
-	pr, newReviewer, err := h.services.PullRequests.ReassignReviewer(
-		r.Context(),
-		domain.PullRequestID(req.PullRequestID),
-		domain.UserID(req.OldUserID),
+
+	var (
+		pr          *domain.PullRequest
+		newReviewer *domain.User
+		err         error
 	)
+	if req.NewUserID != "" {
+		pr, newReviewer, err = h.services.PullRequests.ReassignReviewerTo(
+			r.Context(),
+			domain.PullRequestID(req.PullRequestID),
+			domain.UserID(req.OldUserID),
+			domain.UserID(req.NewUserID),
+		)
+	} else {
+		pr, newReviewer, err = h.services.PullRequests.ReassignReviewer(
+			r.Context(),
+			domain.PullRequestID(req.PullRequestID),
+			domain.UserID(req.OldUserID),
+		)
+	}
 	if err != nil {
 		status, code := mapErrorToHTTP(err)
 		h.log.Error("ReassignReviewer failed",
 			slog.String("pull_request_id", req.PullRequestID),
 			slog.String("old_user_id", req.OldUserID),
+			slog.String("new_user_id", req.NewUserID),
 			slog.String("error_code", code),
 			slog.Any("err", err),
 		)
@@ -98,9 +386,172 @@ func (h *Handler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 		PR:         toPullRequestDTO(pr),
 		ReplacedBy: string(newReviewer.ID),
 	}
+	if explainRequested(r) {
+		resp.PR.AssignmentExplanation = toExplanationDTO(pr.AssignmentExplanation)
+	}
+	setETag(w, pr)
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// AddReviewer handles POST /pullRequest/addReviewer.
+// It manually assigns one more reviewer and returns the updated pull request.
+func (h *Handler) AddReviewer(w http.ResponseWriter, r *http.Request) {
+	var req PullRequestReviewerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Warn("AddReviewer: invalid json", slog.Any("err", err))
+		writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "invalid json")
+		return
+	}
+
+	pr, err := h.services.PullRequests.AddReviewer(
+		r.Context(),
+		domain.PullRequestID(req.PullRequestID),
+		domain.UserID(req.UserID),
+	)
+	if err != nil {
+		status, code := mapErrorToHTTP(err)
+		h.log.Error("AddReviewer failed",
+			slog.String("pull_request_id", req.PullRequestID),
+			slog.String("user_id", req.UserID),
+			slog.String("error_code", code),
+			slog.Any("err", err),
+		)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	writePullRequest(w, http.StatusOK, pr, toPullRequestDTO(pr))
+}
+
+// RemoveReviewer handles POST /pullRequest/removeReviewer.
+// It removes a reviewer without picking a replacement and returns the updated
+// pull request.
+func (h *Handler) RemoveReviewer(w http.ResponseWriter, r *http.Request) {
+	var req PullRequestReviewerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Warn("RemoveReviewer: invalid json", slog.Any("err", err))
+		writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "invalid json")
+		return
+	}
+
+	pr, err := h.services.PullRequests.RemoveReviewer(
+		r.Context(),
+		domain.PullRequestID(req.PullRequestID),
+		domain.UserID(req.UserID),
+	)
+	if err != nil {
+		status, code := mapErrorToHTTP(err)
+		h.log.Error("RemoveReviewer failed",
+			slog.String("pull_request_id", req.PullRequestID),
+			slog.String("user_id", req.UserID),
+			slog.String("error_code", code),
+			slog.Any("err", err),
+		)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	writePullRequest(w, http.StatusOK, pr, toPullRequestDTO(pr))
+}
+
+// AddDependency handles POST /pullRequest/addDependency.
+// It records that the pull request cannot be merged before another one and
+// returns the pull request with its dependency graph.
+func (h *Handler) AddDependency(w http.ResponseWriter, r *http.Request) {
+	h.changeDependency(w, r, "AddDependency", h.services.PullRequests.AddDependency)
+}
+
+// RemoveDependency handles POST /pullRequest/removeDependency.
+// It deletes a dependency and returns the pull request with its dependency graph.
+func (h *Handler) RemoveDependency(w http.ResponseWriter, r *http.Request) {
+	h.changeDependency(w, r, "RemoveDependency", h.services.PullRequests.RemoveDependency)
+}
+
+// changeDependency decodes a PullRequestDependencyRequest, applies change and
+// writes the resulting pull request.
+func (h *Handler) changeDependency(
+	w http.ResponseWriter,
+	r *http.Request,
+	op string,
+	change func(ctx context.Context, id, dependsOn domain.PullRequestID) (*domain.PullRequest, error),
+) {
+	var req PullRequestDependencyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Warn(op+": invalid json", slog.Any("err", err))
+		writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "invalid json")
+		return
+	}
+
+	pr, err := change(r.Context(), domain.PullRequestID(req.PullRequestID), domain.PullRequestID(req.DependsOn))
+	if err != nil {
+		status, code := mapErrorToHTTP(err)
+		h.log.Error(op+" failed",
+			slog.String("pull_request_id", req.PullRequestID),
+			slog.String("depends_on", req.DependsOn),
+			slog.String("error_code", code),
+			slog.Any("err", err),
+		)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	writePullRequest(w, http.StatusOK, pr, toPullRequestDTO(pr))
+}
+
+// MarkPullRequestReady handles POST /pullRequest/markReady.
+// It moves a DRAFT pull request to OPEN and assigns reviewers.
+// With ?explain=true the response also explains the reviewer choice.
+func (h *Handler) MarkPullRequestReady(w http.ResponseWriter, r *http.Request) {
+	h.changePullRequestStatus(w, r, "MarkPullRequestReady", h.services.PullRequests.MarkReady)
+}
+
+// ClosePullRequest handles POST /pullRequest/close.
+// It abandons a DRAFT or OPEN pull request and releases its reviewers.
+func (h *Handler) ClosePullRequest(w http.ResponseWriter, r *http.Request) {
+	h.changePullRequestStatus(w, r, "ClosePullRequest", h.services.PullRequests.Close)
+}
+
+// ReopenPullRequest handles POST /pullRequest/reopen.
+// It moves a CLOSED pull request back to OPEN with freshly assigned reviewers.
+// With ?explain=true the response also explains the reviewer choice.
+func (h *Handler) ReopenPullRequest(w http.ResponseWriter, r *http.Request) {
+	h.changePullRequestStatus(w, r, "ReopenPullRequest", h.services.PullRequests.Reopen)
+}
+
+// changePullRequestStatus decodes a PullRequestStatusRequest, applies the given
+// transition and writes the resulting pull request.
+func (h *Handler) changePullRequestStatus(
+	w http.ResponseWriter,
+	r *http.Request,
+	op string,
+	transition func(context.Context, domain.PullRequestID) (*domain.PullRequest, error),
+) {
+	var req PullRequestStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Warn(op+": invalid json", slog.Any("err", err))
+		writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "invalid json")
+		return
+	}
+
+	pr, err := transition(r.Context(), domain.PullRequestID(req.PullRequestID))
+	if err != nil {
+		status, code := mapErrorToHTTP(err)
+		h.log.Error(op+" failed",
+			slog.String("pull_request_id", req.PullRequestID),
+			slog.String("error_code", code),
+			slog.Any("err", err),
+		)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	prDTO := toPullRequestDTO(pr)
+	if explainRequested(r) {
+		prDTO.AssignmentExplanation = toExplanationDTO(pr.AssignmentExplanation)
+	}
+	writePullRequest(w, http.StatusOK, pr, prDTO)
+}
+
 // toPullRequestDTO maps a domain PullRequest to its HTTP representation.
 func toPullRequestDTO(pr *domain.PullRequest) PullRequestDTO {
 	dto := PullRequestDTO{
@@ -108,12 +559,79 @@ func toPullRequestDTO(pr *domain.PullRequest) PullRequestDTO {
 		PullRequestName:   pr.Name,
 		AuthorID:          string(pr.AuthorID),
 		Status:            string(pr.Status),
+		Labels:            make([]string, 0, len(pr.Labels)),
+		Priority:          string(pr.Priority),
+		Version:           pr.Version,
 		AssignedReviewers: make([]string, 0, len(pr.AssignedReviewers)),
 		CreatedAt:         pr.CreatedAt,
 		MergedAt:          pr.MergedAt,
+		ClosedAt:          pr.ClosedAt,
 	}
+	dto.Labels = append(dto.Labels, pr.Labels...)
 	for _, rid := range pr.AssignedReviewers {
 		dto.AssignedReviewers = append(dto.AssignedReviewers, string(rid))
 	}
+	if len(pr.FallbackReviewers) > 0 {
+		dto.FallbackReviewers = make(map[string]string, len(pr.FallbackReviewers))
+		for rid, team := range pr.FallbackReviewers {
+			dto.FallbackReviewers[string(rid)] = team
+		}
+	}
+	if len(pr.DelegatedReviewers) > 0 {
+		dto.DelegatedReviewers = make(map[string]string, len(pr.DelegatedReviewers))
+		for rid, from := range pr.DelegatedReviewers {
+			dto.DelegatedReviewers[string(rid)] = string(from)
+		}
+	}
+	if len(pr.ReviewDueAt) > 0 {
+		dto.ReviewDueAt = make(map[string]time.Time, len(pr.ReviewDueAt))
+		for rid, due := range pr.ReviewDueAt {
+			dto.ReviewDueAt[string(rid)] = due
+		}
+	}
+	for _, rid := range pr.OverdueReviewers {
+		dto.OverdueReviewers = append(dto.OverdueReviewers, string(rid))
+	}
+	if pr.Dependencies != nil {
+		dto.Dependencies = toDependencyGraphDTO(pr.Dependencies)
+	}
+	return dto
+}
+
+// toDependencyGraphDTO maps a dependency graph to its HTTP representation.
+func toDependencyGraphDTO(graph *domain.DependencyGraph) *DependencyGraphDTO {
+	dto := &DependencyGraphDTO{
+		Nodes: make([]DependencyNodeDTO, 0, len(graph.Nodes)),
+		Edges: make([]DependencyEdgeDTO, 0, len(graph.Edges)),
+	}
+	for _, n := range graph.Nodes {
+		dto.Nodes = append(dto.Nodes, DependencyNodeDTO{
+			PullRequestID:   string(n.ID),
+			PullRequestName: n.Name,
+			Status:          string(n.Status),
+		})
+	}
+	for _, e := range graph.Edges {
+		dto.Edges = append(dto.Edges, DependencyEdgeDTO{
+			PullRequestID: string(e.PullRequestID),
+			DependsOn:     string(e.DependsOnID),
+		})
+	}
 	return dto
 }
+
+// toExplanationDTO maps an assignment explanation to its HTTP representation.
+func toExplanationDTO(explanation []domain.CandidateExplanation) []CandidateExplanationDTO {
+	res := make([]CandidateExplanationDTO, 0, len(explanation))
+	for _, e := range explanation {
+		res = append(res, CandidateExplanationDTO{
+			UserID:        string(e.UserID),
+			TeamName:      e.TeamName,
+			Picked:        e.Picked,
+			Reason:        string(e.Reason),
+			DelegateID:    string(e.DelegateID),
+			DelegatedFrom: string(e.DelegatedFrom),
+		})
+	}
+	return res
+}
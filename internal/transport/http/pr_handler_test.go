@@ -1,14 +1,22 @@
 package http
 
 import (
+	"context"
+	"encoding/json"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
 	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/repository"
+	"github.com/juzu400/avito-internship/internal/repository/mocks"
+	"github.com/juzu400/avito-internship/internal/service"
 )
 
 func TestCreatePullRequest_InvalidJSON(t *testing.T) {
@@ -53,3 +61,510 @@ func TestReassignReviewer_MergedPR_ReturnsConflict(t *testing.T) {
 		t.Fatalf("expected error code PR_MERGED, got %q", code)
 	}
 }
+
+func TestCreatePullRequest_ExplainFlag(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		url     string
+		explain bool
+	}{
+		{"default", "/pullRequest/create", false},
+		{"explain", "/pullRequest/create?explain=true", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h, _, teamRepo, prRepo := newTestHandler(t)
+
+			teamRepo.EXPECT().
+				GetByMemberID(gomock.Any(), domain.UserID("author")).
+				Return(&domain.Team{
+					Name: "backend",
+					Members: []domain.User{
+						{ID: "author", IsActive: true},
+						{ID: "u1", IsActive: true},
+						{ID: "u2", IsActive: false},
+					},
+				}, nil)
+			prRepo.EXPECT().
+				Create(gomock.Any(), gomock.Any()).
+				Return(nil)
+
+			body := `{"pull_request_id": "pr-1", "pull_request_name": "Add search", "author_id": "author"}`
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, tc.url, strings.NewReader(body))
+
+			h.CreatePullRequest(rr, req)
+
+			if rr.Code != http.StatusCreated {
+				t.Fatalf("expected status %d, got %d", http.StatusCreated, rr.Code)
+			}
+
+			var resp struct {
+				PR struct {
+					AssignmentExplanation []CandidateExplanationDTO `json:"assignment_explanation"`
+				} `json:"pr"`
+			}
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode body: %v", err)
+			}
+
+			if !tc.explain {
+				if resp.PR.AssignmentExplanation != nil {
+					t.Fatalf("expected no explanation, got %v", resp.PR.AssignmentExplanation)
+				}
+				return
+			}
+
+			reasons := make(map[string]string)
+			for _, e := range resp.PR.AssignmentExplanation {
+				reasons[e.UserID] = e.Reason
+			}
+			want := map[string]string{"author": "author", "u1": "selected", "u2": "inactive"}
+			for id, reason := range want {
+				if reasons[id] != reason {
+					t.Fatalf("expected %s to have reason %q, got %q", id, reason, reasons[id])
+				}
+			}
+		})
+	}
+}
+
+func TestRemoveReviewer_NotAssigned_ReturnsConflict(t *testing.T) {
+	h, _, _, prRepo := newTestHandler(t)
+
+	pr := &domain.PullRequest{ID: "pr-1", Status: domain.PRStatusOpen}
+	prRepo.EXPECT().
+		GetByID(gomock.Any(), domain.PullRequestID("pr-1")).
+		Return(pr, nil)
+	prRepo.EXPECT().
+		RemoveReviewer(gomock.Any(), pr, domain.UserID("u9")).
+		Return(domain.ErrReviewerNotAssigned)
+
+	body := `{"pull_request_id": "pr-1", "user_id": "u9"}`
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/removeReviewer", strings.NewReader(body))
+
+	h.RemoveReviewer(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, rr.Code)
+	}
+	code, _ := decodeError(t, rr)
+	if code != "NOT_ASSIGNED" {
+		t.Fatalf("expected error code NOT_ASSIGNED, got %q", code)
+	}
+}
+
+func TestMergePullRequest_Force(t *testing.T) {
+	h, _, _, prRepo := newTestHandler(t)
+	h.adminToken = "secret"
+
+	body := `{"pull_request_id": "pr-1", "force": true}`
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer wrong")
+
+	h.MergePullRequest(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, rr.Code)
+	}
+
+	prRepo.EXPECT().
+		Merge(gomock.Any(), domain.PullRequestID("pr-1"), gomock.Any(), true).
+		Return(&domain.PullRequest{ID: "pr-1", Status: domain.PRStatusMerged}, nil)
+
+	rr = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/pullRequest/merge", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer secret")
+
+	h.MergePullRequest(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+}
+
+func TestMergePullRequest_NotApproved_ReturnsConflict(t *testing.T) {
+	h, _, _, prRepo := newTestHandler(t)
+
+	prRepo.EXPECT().
+		Merge(gomock.Any(), domain.PullRequestID("pr-1"), gomock.Any(), false).
+		Return(nil, domain.ErrNotApproved)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", strings.NewReader(`{"pull_request_id": "pr-1"}`))
+
+	h.MergePullRequest(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, rr.Code)
+	}
+	code, _ := decodeError(t, rr)
+	if code != "NOT_APPROVED" {
+		t.Fatalf("expected error code NOT_APPROVED, got %q", code)
+	}
+}
+
+func TestMergePullRequest_DependencyNotMerged_ReturnsConflict(t *testing.T) {
+	h, _, _, prRepo := newTestHandler(t)
+
+	prRepo.EXPECT().
+		Merge(gomock.Any(), domain.PullRequestID("pr-2"), gomock.Any(), false).
+		Return(nil, domain.ErrDependencyNotMerged)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", strings.NewReader(`{"pull_request_id": "pr-2"}`))
+
+	h.MergePullRequest(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, rr.Code)
+	}
+	code, _ := decodeError(t, rr)
+	if code != "DEPENDENCY_NOT_MERGED" {
+		t.Fatalf("expected error code DEPENDENCY_NOT_MERGED, got %q", code)
+	}
+}
+
+func TestGetPullRequest_DependencyGraph(t *testing.T) {
+	h, _, _, prRepo := newTestHandler(t)
+
+	prRepo.EXPECT().
+		GetByID(gomock.Any(), domain.PullRequestID("pr-2")).
+		Return(&domain.PullRequest{ID: "pr-2", Status: domain.PRStatusOpen}, nil)
+	prRepo.EXPECT().
+		GetDependencyGraph(gomock.Any(), domain.PullRequestID("pr-2")).
+		Return(&domain.DependencyGraph{
+			Nodes: []domain.DependencyNode{
+				{ID: "pr-1", Name: "Base", Status: domain.PRStatusMerged},
+				{ID: "pr-2", Name: "On top", Status: domain.PRStatusOpen},
+			},
+			Edges: []domain.DependencyEdge{{PullRequestID: "pr-2", DependsOnID: "pr-1"}},
+		}, nil)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/pullRequest/get?pull_request_id=pr-2", nil)
+
+	h.GetPullRequest(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d, body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var resp PullRequestResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	want := &DependencyGraphDTO{
+		Nodes: []DependencyNodeDTO{
+			{PullRequestID: "pr-1", PullRequestName: "Base", Status: "MERGED"},
+			{PullRequestID: "pr-2", PullRequestName: "On top", Status: "OPEN"},
+		},
+		Edges: []DependencyEdgeDTO{{PullRequestID: "pr-2", DependsOn: "pr-1"}},
+	}
+	if !reflect.DeepEqual(resp.PR.Dependencies, want) {
+		t.Fatalf("expected dependencies %+v, got %+v", want, resp.PR.Dependencies)
+	}
+}
+
+func TestGetPullRequest_SetsETag(t *testing.T) {
+	h, _, _, prRepo := newTestHandler(t)
+
+	prRepo.EXPECT().
+		GetByID(gomock.Any(), domain.PullRequestID("pr-1")).
+		Return(&domain.PullRequest{ID: "pr-1", Status: domain.PRStatusOpen, Version: 7}, nil)
+	prRepo.EXPECT().
+		GetDependencyGraph(gomock.Any(), domain.PullRequestID("pr-1")).
+		Return(&domain.DependencyGraph{}, nil)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/pullRequest/get?pull_request_id=pr-1", nil)
+
+	h.GetPullRequest(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d, body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if got := rr.Header().Get("ETag"); got != `"7"` {
+		t.Fatalf(`expected ETag "7", got %s`, got)
+	}
+	var resp PullRequestResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.PR.Version != 7 {
+		t.Fatalf("expected version 7, got %d", resp.PR.Version)
+	}
+}
+
+func TestSubmitReview_SetsETag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	reviews := mocks.NewMockReviewRepository(ctrl)
+	reviews.EXPECT().
+		Save(gomock.Any(), gomock.AssignableToTypeOf(&domain.Review{})).
+		DoAndReturn(func(_ context.Context, review *domain.Review) error {
+			review.PullRequestVersion = 4
+			return nil
+		})
+
+	log := newTestLogger()
+	h := &Handler{
+		log:      log.With(slog.String("layer", "http")),
+		services: service.NewServices(log, &repository.Repositories{Reviews: reviews}, service.Config{}),
+	}
+
+	body := `{"pull_request_id": "pr-1", "user_id": "u2", "decision": "APPROVED"}`
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/review", strings.NewReader(body))
+
+	h.SubmitReview(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d, body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if got := rr.Header().Get("ETag"); got != `"4"` {
+		t.Fatalf(`expected ETag "4", got %s`, got)
+	}
+}
+
+func TestAddDependency_Cycle_ReturnsConflict(t *testing.T) {
+	h, _, _, prRepo := newTestHandler(t)
+
+	prRepo.EXPECT().
+		AddDependency(gomock.Any(), domain.PullRequestID("pr-1"), domain.PullRequestID("pr-2")).
+		Return(domain.ErrDependencyCycle)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/addDependency",
+		strings.NewReader(`{"pull_request_id": "pr-1", "depends_on": "pr-2"}`))
+
+	h.AddDependency(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, rr.Code)
+	}
+	code, _ := decodeError(t, rr)
+	if code != "DEPENDENCY_CYCLE" {
+		t.Fatalf("expected error code DEPENDENCY_CYCLE, got %q", code)
+	}
+}
+
+func TestClosePullRequest_Merged_ReturnsConflict(t *testing.T) {
+	h, _, _, prRepo := newTestHandler(t)
+
+	prRepo.EXPECT().
+		GetByID(gomock.Any(), domain.PullRequestID("pr-1")).
+		Return(&domain.PullRequest{ID: "pr-1", Status: domain.PRStatusMerged}, nil)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/close", strings.NewReader(`{"pull_request_id": "pr-1"}`))
+
+	h.ClosePullRequest(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, rr.Code)
+	}
+	code, _ := decodeError(t, rr)
+	if code != "INVALID_TRANSITION" {
+		t.Fatalf("expected error code INVALID_TRANSITION, got %q", code)
+	}
+}
+
+func TestGetPullRequest_MissingID(t *testing.T) {
+	h, _, _, _ := newTestHandler(t)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/pullRequest/get", nil)
+
+	h.GetPullRequest(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestUpdatePullRequest_Merged_ReturnsConflict(t *testing.T) {
+	h, _, _, prRepo := newTestHandler(t)
+
+	prRepo.EXPECT().
+		UpdateMetadata(gomock.Any(), domain.PullRequestID("pr-1"), gomock.Any()).
+		Return(nil, domain.ErrPullRequestAlreadyMerged)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/update",
+		strings.NewReader(`{"pull_request_id": "pr-1", "pull_request_name": "Renamed"}`))
+
+	h.UpdatePullRequest(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, rr.Code)
+	}
+	code, _ := decodeError(t, rr)
+	if code != "PR_MERGED" {
+		t.Fatalf("expected error code PR_MERGED, got %q", code)
+	}
+}
+
+func TestUpdatePullRequest_StaleIfMatch_ReturnsConflict(t *testing.T) {
+	h, _, _, prRepo := newTestHandler(t)
+
+	prRepo.EXPECT().
+		UpdateMetadata(gomock.Any(), domain.PullRequestID("pr-1"), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ domain.PullRequestID, _ domain.PullRequestUpdate) (*domain.PullRequest, error) {
+			if v, ok := domain.ExpectedVersion(ctx); !ok || v != 3 {
+				t.Fatalf("expected version 3 in context, got %d (%v)", v, ok)
+			}
+			return nil, domain.ErrVersionConflict
+		})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/update",
+		strings.NewReader(`{"pull_request_id": "pr-1", "pull_request_name": "Renamed"}`))
+	req.Header.Set("If-Match", `"3"`)
+
+	withIfMatch(http.HandlerFunc(h.UpdatePullRequest)).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, rr.Code)
+	}
+	code, _ := decodeError(t, rr)
+	if code != "VERSION_CONFLICT" {
+		t.Fatalf("expected error code VERSION_CONFLICT, got %q", code)
+	}
+}
+
+func TestUpdatePullRequest_LabelsAndPriority(t *testing.T) {
+	h, _, _, prRepo := newTestHandler(t)
+
+	prRepo.EXPECT().
+		UpdateMetadata(gomock.Any(), domain.PullRequestID("pr-1"), gomock.Any()).
+		DoAndReturn(func(_ context.Context, id domain.PullRequestID, upd domain.PullRequestUpdate) (*domain.PullRequest, error) {
+			if upd.Name != nil {
+				t.Errorf("expected name to be left unchanged, got %q", *upd.Name)
+			}
+			if upd.Labels == nil || !reflect.DeepEqual(*upd.Labels, []string{"hotfix"}) {
+				t.Errorf("unexpected labels %v", upd.Labels)
+			}
+			if upd.Priority == nil || *upd.Priority != domain.PriorityCritical {
+				t.Errorf("unexpected priority %v", upd.Priority)
+			}
+			return &domain.PullRequest{
+				ID:       id,
+				Status:   domain.PRStatusOpen,
+				Labels:   *upd.Labels,
+				Priority: *upd.Priority,
+			}, nil
+		})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/update",
+		strings.NewReader(`{"pull_request_id": "pr-1", "labels": ["HotFix", "hotfix"], "priority": "critical"}`))
+
+	h.UpdatePullRequest(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d, body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var resp PullRequestResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !reflect.DeepEqual(resp.PR.Labels, []string{"hotfix"}) || resp.PR.Priority != "CRITICAL" {
+		t.Fatalf("unexpected pull request %+v", resp.PR)
+	}
+}
+
+func TestListPullRequests_FiltersAndCursor(t *testing.T) {
+	h, _, _, prRepo := newTestHandler(t)
+
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	last := &domain.PullRequest{ID: "pr-2", Name: "Second", AuthorID: "u1", Status: domain.PRStatusOpen, CreatedAt: createdAt}
+
+	prRepo.EXPECT().
+		List(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, q domain.PullRequestListQuery) (*domain.PullRequestPage, error) {
+			f := q.Filter
+			if len(f.Statuses) != 2 || f.Statuses[0] != domain.PRStatusOpen || f.Statuses[1] != domain.PRStatusMerged {
+				t.Errorf("unexpected statuses %v", f.Statuses)
+			}
+			if f.ReviewerID != "u2" || f.TeamName != "backend" || f.NameContains != "search" {
+				t.Errorf("unexpected filter %+v", f)
+			}
+			if !reflect.DeepEqual(f.Labels, []string{"backend", "hotfix"}) {
+				t.Errorf("unexpected labels %v", f.Labels)
+			}
+			if len(f.Priorities) != 1 || f.Priorities[0] != domain.PriorityHigh {
+				t.Errorf("unexpected priorities %v", f.Priorities)
+			}
+			if f.CreatedFrom == nil || !f.CreatedFrom.Equal(createdAt) {
+				t.Errorf("unexpected created_from %v", f.CreatedFrom)
+			}
+			if q.Limit != 1 {
+				t.Errorf("expected limit 1, got %d", q.Limit)
+			}
+			return &domain.PullRequestPage{
+				Items:      []*domain.PullRequest{last},
+				NextCursor: domain.NewPullRequestCursor(q.SortBy, q.Order, last),
+			}, nil
+		})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet,
+		"/pullRequests?status=open,MERGED&reviewer_id=u2&team_name=backend&name=search&label=Hotfix,backend&priority=high&created_from=2025-01-02T03:04:05Z&limit=1", nil)
+
+	h.ListPullRequests(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d, body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var resp ListPullRequestsResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].PullRequestID != "pr-2" {
+		t.Fatalf("unexpected items %+v", resp.Items)
+	}
+
+	cursor, err := domain.DecodePullRequestCursor(resp.NextCursor)
+	if err != nil {
+		t.Fatalf("decode next_cursor: %v", err)
+	}
+	if cursor.ID != "pr-2" || !cursor.CreatedAt.Equal(createdAt) || cursor.SortBy != domain.PRSortCreatedAt {
+		t.Fatalf("unexpected cursor %+v", cursor)
+	}
+}
+
+func TestListPullRequests_InvalidCursor(t *testing.T) {
+	h, _, _, _ := newTestHandler(t)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/pullRequests?cursor=not-a-cursor", nil)
+
+	h.ListPullRequests(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestGetPullRequestHistory_NotFound(t *testing.T) {
+	h, _, _, prRepo := newTestHandler(t)
+
+	prRepo.EXPECT().
+		GetByID(gomock.Any(), domain.PullRequestID("pr-404")).
+		Return(nil, domain.ErrNotFound)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/pullRequest/history?pull_request_id=pr-404", nil)
+
+	h.GetPullRequestHistory(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
@@ -13,30 +13,63 @@ import (
 type Handler struct {
 	log      *slog.Logger
 	services *service.Services
+	// adminToken authorizes admin-only actions; empty disables them.
+	adminToken string
 }
 
 // NewRouter constructs an HTTP router with all API routes registered.
 // It wires the given logger and services into the Handler and returns
 // a chi-based http.Handler ready to be passed to http.Server.
-func NewRouter(log *slog.Logger, services *service.Services) http.Handler {
+// adminToken is the bearer token required for admin-only actions such as forced merges.
+func NewRouter(log *slog.Logger, services *service.Services, adminToken string) http.Handler {
 	h := &Handler{
-		log:      log.With(slog.String("layer", "http")),
-		services: services,
+		log:        log.With(slog.String("layer", "http")),
+		services:   services,
+		adminToken: adminToken,
 	}
 
 	r := chi.NewRouter()
+	r.Use(withActor)
+	r.Use(h.withIdempotencyKey)
 
 	r.Get("/health", h.Health)
 
 	r.Post("/team/add", h.AddTeam)
 	r.Get("/team/get", h.GetTeam)
+	r.Get("/team/settings", h.GetTeamSettings)
+	r.Post("/team/settings", h.UpdateTeamSettings)
+	r.Get("/team/digest", h.GetTeamDigest)
+	r.Post("/team/deactivateUsers", h.DeactivateUsers)
 
 	r.Post("/users/setIsActive", h.SetUserActive)
+	r.Post("/users/setMaxOpenReviews", h.SetUserMaxOpenReviews)
 	r.Get("/users/getReview", h.GetUserReview)
+	r.Post("/users/addAbsence", h.AddAbsence)
+	r.Get("/users/getAbsences", h.GetUserAbsences)
+	r.Post("/users/cancelAbsence", h.CancelAbsence)
+	r.Post("/users/addDelegation", h.AddDelegation)
+	r.Get("/users/getDelegations", h.GetUserDelegations)
+	r.Post("/users/cancelDelegation", h.CancelDelegation)
 
 	r.Post("/pullRequest/create", h.CreatePullRequest)
-	r.Post("/pullRequest/merge", h.MergePullRequest)
-	r.Post("/pullRequest/reassign", h.ReassignReviewer)
+	r.Get("/pullRequest/get", h.GetPullRequest)
+	r.Get("/pullRequest/history", h.GetPullRequestHistory)
+	r.Get("/pullRequests", h.ListPullRequests)
+	r.Post("/pullRequest/previewAssignment", h.PreviewAssignment)
+	r.Group(func(r chi.Router) {
+		r.Use(withIfMatch)
+		r.Post("/pullRequest/update", h.UpdatePullRequest)
+		r.Post("/pullRequest/merge", h.MergePullRequest)
+		r.Post("/pullRequest/markReady", h.MarkPullRequestReady)
+		r.Post("/pullRequest/close", h.ClosePullRequest)
+		r.Post("/pullRequest/reopen", h.ReopenPullRequest)
+		r.Post("/pullRequest/review", h.SubmitReview)
+		r.Post("/pullRequest/reassign", h.ReassignReviewer)
+		r.Post("/pullRequest/addReviewer", h.AddReviewer)
+		r.Post("/pullRequest/removeReviewer", h.RemoveReviewer)
+		r.Post("/pullRequest/addDependency", h.AddDependency)
+		r.Post("/pullRequest/removeDependency", h.RemoveDependency)
+	})
 
 	r.Get("/users/stats", h.GetReviewerStats)
 	r.Get("/pullRequests/stats", h.GetPullRequestStats)
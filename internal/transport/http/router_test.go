@@ -27,8 +27,8 @@ func TestRouter_AllRoutesRegistered(t *testing.T) {
 		PullRequests: prRepo,
 	}
 
-	services := service.NewServices(log, repos)
-	r := NewRouter(log, services)
+	services := service.NewServices(log, repos, service.Config{})
+	r := NewRouter(log, services, "")
 
 	tests := []struct {
 		method string
@@ -38,11 +38,32 @@ func TestRouter_AllRoutesRegistered(t *testing.T) {
 		{"GET", "/users/stats"},
 		{"POST", "/team/add"},
 		{"GET", "/team/get"},
+		{"GET", "/team/settings"},
+		{"POST", "/team/settings"},
+		{"GET", "/team/digest"},
+		{"POST", "/team/deactivateUsers"},
 		{"POST", "/users/setIsActive"},
+		{"POST", "/users/setMaxOpenReviews"},
 		{"GET", "/users/getReview"},
+		{"POST", "/users/addAbsence"},
+		{"GET", "/users/getAbsences"},
+		{"POST", "/users/cancelAbsence"},
 		{"POST", "/pullRequest/create"},
+		{"GET", "/pullRequest/get"},
+		{"GET", "/pullRequest/history"},
+		{"GET", "/pullRequests"},
+		{"POST", "/pullRequest/update"},
+		{"POST", "/pullRequest/previewAssignment"},
 		{"POST", "/pullRequest/merge"},
+		{"POST", "/pullRequest/markReady"},
+		{"POST", "/pullRequest/close"},
+		{"POST", "/pullRequest/reopen"},
+		{"POST", "/pullRequest/review"},
 		{"POST", "/pullRequest/reassign"},
+		{"POST", "/pullRequest/addReviewer"},
+		{"POST", "/pullRequest/removeReviewer"},
+		{"POST", "/pullRequest/addDependency"},
+		{"POST", "/pullRequest/removeDependency"},
 		{"GET", "/pullRequests/stats"},
 	}
 
@@ -54,6 +75,12 @@ func TestRouter_AllRoutesRegistered(t *testing.T) {
 					Return([]domain.ReviewerAssignmentStat(nil), nil)
 			}
 
+			if tt.method == http.MethodGet && tt.path == "/pullRequests" {
+				prRepo.EXPECT().
+					List(gomock.Any(), gomock.Any()).
+					Return(&domain.PullRequestPage{}, nil)
+			}
+
 			if tt.method == http.MethodGet && tt.path == "/pullRequests/stats" {
 				prRepo.EXPECT().
 					GetPullRequestReviewerStats(gomock.Any()).
@@ -74,8 +101,8 @@ func TestRouter_AllRoutesRegistered(t *testing.T) {
 
 func TestRouter_UnknownRouteReturns404(t *testing.T) {
 	log := newTestLogger()
-	services := service.NewServices(log, &repository.Repositories{})
-	r := NewRouter(log, services)
+	services := service.NewServices(log, &repository.Repositories{}, service.Config{})
+	r := NewRouter(log, services, "")
 
 	rr := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodGet, "/unknown/path", nil)
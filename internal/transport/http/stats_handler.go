@@ -29,6 +29,7 @@ func (h *Handler) GetReviewerStats(w http.ResponseWriter, r *http.Request) {
 		resp.Items = append(resp.Items, ReviewerStatsItemDTO{
 			ReviewerID:  string(s.ReviewerID),
 			Assignments: s.AssignmentsCount,
+			Overdue:     s.OverdueCount,
 		})
 	}
 
@@ -17,7 +17,7 @@ func TestGetReviewerStats_Success(t *testing.T) {
 	h, _, _, prRepo := newTestHandler(t)
 
 	stats := []domain.ReviewerAssignmentStat{
-		{ReviewerID: domain.UserID("u1"), AssignmentsCount: 3},
+		{ReviewerID: domain.UserID("u1"), AssignmentsCount: 3, OverdueCount: 2},
 		{ReviewerID: domain.UserID("u2"), AssignmentsCount: 1},
 	}
 
@@ -44,7 +44,8 @@ func TestGetReviewerStats_Success(t *testing.T) {
 	}
 
 	if resp.Items[0].ReviewerID != string(stats[0].ReviewerID) ||
-		resp.Items[0].Assignments != stats[0].AssignmentsCount {
+		resp.Items[0].Assignments != stats[0].AssignmentsCount ||
+		resp.Items[0].Overdue != stats[0].OverdueCount {
 		t.Fatalf("unexpected first item: %+v", resp.Items[0])
 	}
 }
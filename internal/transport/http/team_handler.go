@@ -3,6 +3,8 @@ package http
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
+	"time"
 
 	"log/slog"
 
@@ -85,3 +87,194 @@ func (h *Handler) GetTeam(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, http.StatusOK, resp)
 }
+
+// GetTeamSettings handles GET /team/settings.
+// It expects a "team_name" query parameter and returns reviewer assignment
+// settings of the team.
+func (h *Handler) GetTeamSettings(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "team_name is required")
+		return
+	}
+
+	settings, err := h.services.Teams.GetSettings(r.Context(), teamName)
+	if err != nil {
+		status, code := mapErrorToHTTP(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toTeamSettingsDTO(teamName, *settings))
+}
+
+// UpdateTeamSettings handles POST /team/settings.
+// It changes the reviewer assignment settings given in the request, keeping the
+// omitted ones, and returns the stored values.
+func (h *Handler) UpdateTeamSettings(w http.ResponseWriter, r *http.Request) {
+	var req TeamSettingsUpdateDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Warn("UpdateTeamSettings: invalid json", slog.Any("err", err))
+		writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "invalid json")
+		return
+	}
+
+	upd := domain.TeamSettingsUpdate{
+		ReviewersCount:    req.ReviewersCount,
+		MinReviewers:      req.MinReviewers,
+		RequiredApprovals: req.RequiredApprovals,
+		FallbackTeams:     req.FallbackTeams,
+
+		ReviewSLAHours:        req.ReviewSLAHours,
+		ReviewSLABusinessDays: req.ReviewSLABusinessDays,
+		ReviewSLAAutoReassign: req.ReviewSLAAutoReassign,
+	}
+	if req.ReviewerStrategy != nil {
+		strategy := domain.ReviewerStrategy(*req.ReviewerStrategy)
+		upd.Strategy = &strategy
+	}
+	if req.ReviewRules != nil {
+		rules := make([]domain.ReviewRule, 0, len(*req.ReviewRules))
+		for _, rule := range *req.ReviewRules {
+			rules = append(rules, domain.ReviewRule{
+				Label:             rule.Label,
+				Priority:          domain.PullRequestPriority(strings.ToUpper(rule.Priority)),
+				ReviewersCount:    rule.ReviewersCount,
+				RequiredApprovals: rule.RequiredApprovals,
+			})
+		}
+		upd.ReviewRules = &rules
+	}
+
+	settings, err := h.services.Teams.UpdateSettings(r.Context(), req.TeamName, upd)
+	if err != nil {
+		status, code := mapErrorToHTTP(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toTeamSettingsDTO(req.TeamName, *settings))
+}
+
+// toTeamSettingsDTO maps domain team settings to their HTTP representation.
+func toTeamSettingsDTO(teamName string, settings domain.TeamSettings) TeamSettingsDTO {
+	dto := TeamSettingsDTO{
+		TeamName:          teamName,
+		ReviewersCount:    settings.ReviewersCount,
+		MinReviewers:      settings.MinReviewers,
+		RequiredApprovals: settings.RequiredApprovals,
+		ReviewerStrategy:  string(settings.Strategy),
+		FallbackTeams:     make([]string, 0, len(settings.FallbackTeams)),
+
+		ReviewSLAHours:        settings.ReviewSLAHours,
+		ReviewSLABusinessDays: settings.ReviewSLABusinessDays,
+		ReviewSLAAutoReassign: settings.ReviewSLAAutoReassign,
+	}
+	dto.FallbackTeams = append(dto.FallbackTeams, settings.FallbackTeams...)
+	dto.ReviewRules = make([]ReviewRuleDTO, 0, len(settings.ReviewRules))
+	for _, rule := range settings.ReviewRules {
+		dto.ReviewRules = append(dto.ReviewRules, ReviewRuleDTO{
+			Label:             rule.Label,
+			Priority:          string(rule.Priority),
+			ReviewersCount:    rule.ReviewersCount,
+			RequiredApprovals: rule.RequiredApprovals,
+		})
+	}
+	return dto
+}
+
+// GetTeamDigest handles GET /team/digest.
+// It returns stale pull requests authored by members of the team as JSON or, with
+// ?format=markdown, as a Markdown document.
+func (h *Handler) GetTeamDigest(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format != "" && format != "json" && format != "markdown" {
+		writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "format must be json or markdown")
+		return
+	}
+
+	digest, err := h.services.Digests.TeamDigest(r.Context(), query.Get("team_name"))
+	if err != nil {
+		status, code := mapErrorToHTTP(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	if format == "markdown" {
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(service.RenderDigestMarkdown(*digest)))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toTeamDigestDTO(*digest))
+}
+
+// toTeamDigestDTO maps a team digest to its JSON representation.
+func toTeamDigestDTO(d domain.TeamDigest) TeamDigestDTO {
+	dto := TeamDigestDTO{
+		TeamName:        d.TeamName,
+		GeneratedAt:     d.GeneratedAt,
+		StaleAfterHours: int(d.StaleAfter / time.Hour),
+		PullRequests:    make([]StalePullRequestDTO, 0, len(d.PullRequests)),
+	}
+	for _, pr := range d.PullRequests {
+		item := StalePullRequestDTO{
+			PullRequestID:    string(pr.ID),
+			PullRequestName:  pr.Name,
+			AuthorID:         string(pr.AuthorID),
+			CreatedAt:        pr.CreatedAt,
+			WaitingSince:     pr.WaitingSince,
+			AgeHours:         int(pr.Age / time.Hour),
+			PendingReviewers: make([]string, 0, len(pr.PendingReviewers)),
+		}
+		for _, rid := range pr.PendingReviewers {
+			item.PendingReviewers = append(item.PendingReviewers, string(rid))
+		}
+		dto.PullRequests = append(dto.PullRequests, item)
+	}
+	return dto
+}
+
+// DeactivateUsers handles POST /team/deactivateUsers.
+// It deactivates the listed users and/or all members of the team in one transaction
+// and returns a report of every review handed over or dropped on OPEN pull requests.
+func (h *Handler) DeactivateUsers(w http.ResponseWriter, r *http.Request) {
+	var req DeactivateUsersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Warn("DeactivateUsers: invalid json", slog.Any("err", err))
+		writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "invalid json")
+		return
+	}
+
+	ids := make([]domain.UserID, 0, len(req.UserIDs))
+	for _, id := range req.UserIDs {
+		ids = append(ids, domain.UserID(id))
+	}
+
+	deactivated, report, err := h.services.Users.DeactivateUsers(r.Context(), req.TeamName, ids)
+	if err != nil {
+		status, code := mapErrorToHTTP(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	resp := DeactivateUsersResponse{
+		DeactivatedUsers: make([]string, 0, len(deactivated)),
+		Reassignments:    make([]ReviewerReassignmentDTO, 0, len(report)),
+	}
+	for _, id := range deactivated {
+		resp.DeactivatedUsers = append(resp.DeactivatedUsers, string(id))
+	}
+	for _, ra := range report {
+		resp.Reassignments = append(resp.Reassignments, ReviewerReassignmentDTO{
+			PullRequestID: string(ra.PullRequestID),
+			OldUserID:     string(ra.OldReviewerID),
+			NewUserID:     string(ra.NewReviewerID),
+			Dropped:       ra.NewReviewerID == "",
+		})
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -1,15 +1,19 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
 	"github.com/juzu400/avito-internship/internal/domain"
+	"github.com/juzu400/avito-internship/internal/repository"
+	"github.com/juzu400/avito-internship/internal/repository/mocks"
 )
 
 func TestAddTeam_InvalidJSON(t *testing.T) {
@@ -115,3 +119,192 @@ func TestGetTeam_NotFound(t *testing.T) {
 		t.Fatalf("expected error code NOT_FOUND, got %q", code)
 	}
 }
+
+func TestGetTeamSettings_Success(t *testing.T) {
+	h, _, teamRepo, _ := newTestHandler(t)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/team/settings?team_name=platform", nil)
+
+	teamRepo.EXPECT().
+		GetSettings(gomock.Any(), "platform").
+		Return(&domain.TeamSettings{ReviewersCount: 3, MinReviewers: 1}, nil)
+
+	h.GetTeamSettings(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	var resp TeamSettingsDTO
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.TeamName != "platform" || resp.ReviewersCount != 3 || resp.MinReviewers != 1 {
+		t.Fatalf("unexpected settings: %+v", resp)
+	}
+}
+
+func TestGetTeamDigest_Markdown(t *testing.T) {
+	h, _, teamRepo, prRepo := newTestHandler(t)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/team/digest?team_name=platform&format=markdown", nil)
+
+	teamRepo.EXPECT().
+		GetSettings(gomock.Any(), "platform").
+		Return(&domain.TeamSettings{ReviewersCount: 2}, nil)
+	prRepo.EXPECT().
+		ListStale(gomock.Any(), gomock.Any(), "platform").
+		Return([]domain.StalePullRequest{{
+			ID:               "pr-1",
+			Name:             "Add | search",
+			AuthorID:         "u1",
+			TeamName:         "platform",
+			CreatedAt:        time.Now().Add(-80 * time.Hour),
+			WaitingSince:     time.Now().Add(-50 * time.Hour),
+			PendingReviewers: []domain.UserID{"u2", "u3"},
+		}}, nil)
+
+	h.GetTeamDigest(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/markdown") {
+		t.Fatalf("expected markdown content type, got %q", ct)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{"# Review digest: platform", `Add \| search (pr-1)`, "| 2d 2h |", "u2, u3"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %q in digest:\n%s", want, body)
+		}
+	}
+}
+
+func TestGetTeamDigest_JSON(t *testing.T) {
+	h, _, teamRepo, prRepo := newTestHandler(t)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/team/digest?team_name=platform", nil)
+
+	teamRepo.EXPECT().
+		GetSettings(gomock.Any(), "platform").
+		Return(&domain.TeamSettings{ReviewersCount: 2}, nil)
+	prRepo.EXPECT().
+		ListStale(gomock.Any(), gomock.Any(), "platform").
+		Return([]domain.StalePullRequest{{
+			ID:               "pr-1",
+			AuthorID:         "u1",
+			CreatedAt:        time.Now().Add(-80 * time.Hour),
+			WaitingSince:     time.Now().Add(-50 * time.Hour),
+			PendingReviewers: []domain.UserID{"u2"},
+		}}, nil)
+
+	h.GetTeamDigest(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	var resp TeamDigestDTO
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.TeamName != "platform" || resp.StaleAfterHours != 48 || len(resp.PullRequests) != 1 {
+		t.Fatalf("unexpected digest: %+v", resp)
+	}
+	if pr := resp.PullRequests[0]; pr.AgeHours != 50 || len(pr.PendingReviewers) != 1 || pr.PendingReviewers[0] != "u2" {
+		t.Fatalf("unexpected stale pull request: %+v", pr)
+	}
+}
+
+func TestGetTeamDigest_UnknownFormat(t *testing.T) {
+	h, _, _, _ := newTestHandler(t)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/team/digest?team_name=platform&format=pdf", nil)
+
+	h.GetTeamDigest(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
+
+// stubUpdateSettings makes the team repository apply settings updates to a copy of stored.
+func stubUpdateSettings(teamRepo *mocks.MockTeamRepository, stored domain.TeamSettings) {
+	teamRepo.EXPECT().
+		UpdateSettings(gomock.Any(), "platform", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, update repository.TeamSettingsUpdater) (*domain.TeamSettings, error) {
+			settings := stored
+			if err := update(&settings); err != nil {
+				return nil, err
+			}
+			return &settings, nil
+		})
+}
+
+func TestUpdateTeamSettings_KeepsOmittedFields(t *testing.T) {
+	h, _, teamRepo, _ := newTestHandler(t)
+	stubUpdateSettings(teamRepo, domain.TeamSettings{
+		ReviewersCount: 3,
+		MinReviewers:   1,
+		FallbackTeams:  []string{"backend"},
+		ReviewSLAHours: 24,
+	})
+
+	body := `{"team_name": "platform", "review_sla_auto_reassign": true}`
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/team/settings", strings.NewReader(body))
+
+	h.UpdateTeamSettings(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	var resp TeamSettingsDTO
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.ReviewersCount != 3 || resp.MinReviewers != 1 || resp.ReviewSLAHours != 24 ||
+		len(resp.FallbackTeams) != 1 || !resp.ReviewSLAAutoReassign {
+		t.Fatalf("unexpected settings: %+v", resp)
+	}
+}
+
+func TestUpdateTeamSettings_ValidationError(t *testing.T) {
+	h, _, teamRepo, _ := newTestHandler(t)
+	stubUpdateSettings(teamRepo, domain.DefaultTeamSettings())
+
+	body := `{"team_name": "platform", "reviewers_count": 0}`
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/team/settings", strings.NewReader(body))
+
+	h.UpdateTeamSettings(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+	code, _ := decodeError(t, rr)
+	if code != codeValidationErr {
+		t.Fatalf("expected error code VALIDATION_ERROR, got %q", code)
+	}
+}
+
+func TestDeactivateUsers_ValidationError(t *testing.T) {
+	h, _, _, _ := newTestHandler(t)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/team/deactivateUsers", strings.NewReader(`{}`))
+
+	h.DeactivateUsers(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+	code, _ := decodeError(t, rr)
+	if code != codeValidationErr {
+		t.Fatalf("expected error code VALIDATION_ERROR, got %q", code)
+	}
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -30,40 +31,78 @@ func (h *Handler) SetUserActive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	u, err := h.services.Users.GetByID(r.Context(), userID)
+	resp, err := h.userResponse(r.Context(), userID)
 	if err != nil {
 		status, code := mapErrorToHTTP(err)
 		writeError(w, status, code, err.Error())
 		return
 	}
 
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// SetUserMaxOpenReviews handles POST /users/setMaxOpenReviews.
+// It sets or (with null) removes the user's limit of simultaneously open reviews
+// and returns the resulting user representation.
+func (h *Handler) SetUserMaxOpenReviews(w http.ResponseWriter, r *http.Request) {
+	var req SetUserMaxOpenReviewsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Warn("SetUserMaxOpenReviews: invalid json", slog.Any("err", err))
+		writeError(w, http.StatusBadRequest, service.ErrCodeValidation, "invalid json")
+		return
+	}
+
+	userID := domain.UserID(req.UserID)
+
+	if err := h.services.Users.SetMaxOpenReviews(r.Context(), userID, req.MaxOpenReviews); err != nil {
+		status, code := mapErrorToHTTP(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	resp, err := h.userResponse(r.Context(), userID)
+	if err != nil {
+		status, code := mapErrorToHTTP(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// userResponse fetches the user together with their team name (if any)
+// and builds the HTTP representation.
+func (h *Handler) userResponse(ctx context.Context, userID domain.UserID) (*UserResponse, error) {
+	u, err := h.services.Users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	var teamName string
-	team, err := h.services.Teams.GetByMemberID(r.Context(), userID)
+	team, err := h.services.Teams.GetByMemberID(ctx, userID)
 	if err != nil {
 		if !errors.Is(err, domain.ErrNotFound) {
-			status, code := mapErrorToHTTP(err)
-			writeError(w, status, code, err.Error())
-			return
+			return nil, err
 		}
 	} else {
 		teamName = team.Name
 	}
 
-	resp := UserResponse{
+	return &UserResponse{
 		User: UserDTO{
-			UserID:   string(u.ID),
-			Username: u.Username,
-			TeamName: teamName,
-			IsActive: u.IsActive,
+			UserID:         string(u.ID),
+			Username:       u.Username,
+			TeamName:       teamName,
+			IsActive:       u.IsActive,
+			MaxOpenReviews: u.MaxOpenReviews,
 		},
-	}
-
-	writeJSON(w, http.StatusOK, resp)
+	}, nil
 }
 
 // GetUserReview handles GET /users/getReview.
-// It expects a "user_id" query parameter, fetches pull requests where the user
-// is assigned as a reviewer, and returns them in a compact form.
+// It expects a "user_id" query parameter, fetches one page of pull requests where
+// the user is assigned as a reviewer, and returns them in a compact form.
+// Optional "status", "limit" and "cursor" parameters filter and page the result.
 func (h *Handler) GetUserReview(w http.ResponseWriter, r *http.Request) {
 	userID := r.URL.Query().Get("user_id")
 	if userID == "" {
@@ -71,7 +110,18 @@ func (h *Handler) GetUserReview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	prs, err := h.services.Users.GetReviews(r.Context(), domain.UserID(userID))
+	limit, cursor, err := parsePage(r.URL.Query())
+	if err != nil {
+		status, code := mapErrorToHTTP(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+
+	page, err := h.services.Users.GetReviews(r.Context(), domain.UserID(userID), domain.ReviewListQuery{
+		Statuses: parseStatuses(r.URL.Query()),
+		Limit:    limit,
+		After:    cursor,
+	})
 	if err != nil {
 		status, code := mapErrorToHTTP(err)
 		writeError(w, status, code, err.Error())
@@ -80,10 +130,13 @@ func (h *Handler) GetUserReview(w http.ResponseWriter, r *http.Request) {
 
 	resp := GetUserReviewResponse{
 		UserID:       userID,
-		PullRequests: make([]PullRequestShortDTO, 0, len(prs)),
+		PullRequests: make([]PullRequestShortDTO, 0, len(page.Items)),
+	}
+	if page.NextCursor != nil {
+		resp.NextCursor = page.NextCursor.Encode()
 	}
 
-	for _, pr := range prs {
+	for _, pr := range page.Items {
 		resp.PullRequests = append(resp.PullRequests, PullRequestShortDTO{
 			PullRequestID:   string(pr.ID),
 			PullRequestName: pr.Name,
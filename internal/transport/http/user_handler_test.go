@@ -91,6 +91,56 @@ func TestSetUserActive_Success(t *testing.T) {
 	}
 }
 
+func TestSetUserMaxOpenReviews_Success(t *testing.T) {
+	h, userRepo, teamRepo, _ := newTestHandler(t)
+
+	body := `{"user_id": "u1", "max_open_reviews": 2}`
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users/setMaxOpenReviews", strings.NewReader(body))
+
+	userID := domain.UserID("u1")
+	limit := 2
+
+	gomock.InOrder(
+		userRepo.EXPECT().
+			SetMaxOpenReviews(gomock.Any(), userID, &limit).
+			Return(nil),
+
+		userRepo.EXPECT().
+			GetByID(gomock.Any(), userID).
+			Return(&domain.User{
+				ID:             userID,
+				Username:       "Alice",
+				IsActive:       true,
+				MaxOpenReviews: &limit,
+			}, nil),
+
+		teamRepo.EXPECT().
+			GetByMemberID(gomock.Any(), userID).
+			Return(nil, domain.ErrNotFound),
+	)
+
+	h.SetUserMaxOpenReviews(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	var resp struct {
+		User struct {
+			MaxOpenReviews *int `json:"max_open_reviews"`
+		} `json:"user"`
+	}
+
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+
+	if resp.User.MaxOpenReviews == nil || *resp.User.MaxOpenReviews != 2 {
+		t.Fatalf("expected max_open_reviews 2, got %v", resp.User.MaxOpenReviews)
+	}
+}
+
 func TestGetUserReview_MissingUserID(t *testing.T) {
 	h, _, _, _ := newTestHandler(t)
 
@@ -128,3 +178,42 @@ func TestGetUserReview_UserNotFound(t *testing.T) {
 		t.Fatalf("expected error code NOT_FOUND, got %q", code)
 	}
 }
+
+func TestGetUserReview_Paginated(t *testing.T) {
+	h, userRepo, _, prRepo := newTestHandler(t)
+
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), domain.UserID("u1")).
+		Return(&domain.User{ID: "u1", IsActive: true}, nil)
+
+	last := &domain.PullRequest{ID: "pr-7", Name: "Seventh", AuthorID: "u2", Status: domain.PRStatusOpen}
+	prRepo.EXPECT().
+		ListByReviewer(gomock.Any(), domain.UserID("u1"), domain.ReviewListQuery{
+			Statuses: []domain.PullRequestStatus{domain.PRStatusOpen},
+			Limit:    1,
+		}).
+		Return(&domain.PullRequestPage{
+			Items:      []*domain.PullRequest{last},
+			NextCursor: domain.NewPullRequestCursor(domain.PRSortCreatedAt, domain.SortDesc, last),
+		}, nil)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users/getReview?user_id=u1&status=OPEN&limit=1", nil)
+
+	h.GetUserReview(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d, body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var resp GetUserReviewResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.PullRequests) != 1 || resp.PullRequests[0].PullRequestID != "pr-7" {
+		t.Fatalf("unexpected pull requests %+v", resp.PullRequests)
+	}
+	if resp.NextCursor == "" {
+		t.Fatal("expected next_cursor to be set")
+	}
+}
@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Job is a unit of background work executed periodically by Run.
+type Job func(ctx context.Context) error
+
+// Run executes job every interval until ctx is cancelled. Each run is limited
+// by the interval, so a slow run never overlaps with the next one. Errors are
+// logged and do not stop the loop. A non-positive interval disables the job.
+func Run(ctx context.Context, log *slog.Logger, name string, interval time.Duration, job Job) {
+	log = log.With(slog.String("worker", name))
+
+	if interval <= 0 {
+		log.Info("worker disabled")
+		return
+	}
+
+	log.Info("worker started", slog.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("worker stopped")
+			return
+		case <-ticker.C:
+			runOnce(ctx, log, interval, job)
+		}
+	}
+}
+
+// runOnce executes a single job run with a deadline equal to the interval.
+func runOnce(ctx context.Context, log *slog.Logger, timeout time.Duration, job Job) {
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	started := time.Now()
+	if err := job(runCtx); err != nil {
+		log.Error("worker run failed",
+			slog.Duration("took", time.Since(started)),
+			slog.Any("err", err),
+		)
+		return
+	}
+	log.Debug("worker run finished", slog.Duration("took", time.Since(started)))
+}
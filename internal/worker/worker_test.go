@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestRun_ExecutesJobUntilCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var calls atomic.Int32
+	done := make(chan struct{})
+
+	go func() {
+		Run(ctx, newTestLogger(), "test", 5*time.Millisecond, func(context.Context) error {
+			if calls.Add(1) == 3 {
+				cancel()
+			}
+			return errors.New("job error")
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("worker did not stop after context cancellation")
+	}
+
+	if got := calls.Load(); got < 3 {
+		t.Fatalf("expected at least 3 runs despite errors, got %d", got)
+	}
+}
+
+func TestRun_DisabledWithZeroInterval(t *testing.T) {
+	called := false
+
+	Run(context.Background(), newTestLogger(), "test", 0, func(context.Context) error {
+		called = true
+		return nil
+	})
+
+	if called {
+		t.Fatal("expected job not to be called when interval is zero")
+	}
+}
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS team_settings (
+    team_id           BIGINT PRIMARY KEY REFERENCES teams(id) ON DELETE CASCADE,
+    reviewers_count   INT  NOT NULL DEFAULT 2,
+    min_reviewers     INT  NOT NULL DEFAULT 0,
+    reviewer_strategy TEXT NOT NULL DEFAULT ''
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS team_fallbacks (
+    team_id          BIGINT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    fallback_team_id BIGINT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    position         INT    NOT NULL,
+    PRIMARY KEY (team_id, fallback_team_id),
+    CHECK (team_id <> fallback_team_id)
+);
+
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN IF NOT EXISTS fallback_team TEXT;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS absences (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      TEXT        NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    starts_at    TIMESTAMPTZ NOT NULL,
+    ends_at      TIMESTAMPTZ NOT NULL,
+    delegate_id  TEXT        REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    cancelled_at TIMESTAMPTZ,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_absences_user_id_window
+    ON absences (user_id, starts_at, ends_at)
+    WHERE cancelled_at IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN IF NOT EXISTS delegated_from TEXT REFERENCES users(user_id) ON DELETE SET NULL;
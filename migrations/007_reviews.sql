@@ -0,0 +1,17 @@
+ALTER TABLE team_settings
+    ADD COLUMN IF NOT EXISTS required_approvals INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS pull_request_reviews (
+    pull_request_id TEXT        NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    reviewer_id     TEXT        NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    decision        TEXT        NOT NULL,
+    submitted_at    TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (pull_request_id, reviewer_id)
+);
+
+CREATE TABLE IF NOT EXISTS forced_merges (
+    pull_request_id    TEXT        PRIMARY KEY REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    approvals          INT         NOT NULL,
+    required_approvals INT         NOT NULL,
+    forced_at          TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS pull_request_events (
+    id              BIGSERIAL PRIMARY KEY,
+    pull_request_id TEXT        NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    event_type      TEXT        NOT NULL,
+    actor_id        TEXT,
+    old_reviewer_id TEXT,
+    new_reviewer_id TEXT,
+    old_status      TEXT,
+    new_status      TEXT,
+    decision        TEXT,
+    reason          TEXT,
+    details         TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pull_request_events_pull_request_id
+    ON pull_request_events (pull_request_id, id);
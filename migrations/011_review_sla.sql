@@ -0,0 +1,12 @@
+ALTER TABLE team_settings
+    ADD COLUMN IF NOT EXISTS review_sla_hours         INT     NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS review_sla_business_days BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS review_sla_auto_reassign BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN IF NOT EXISTS review_due_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS overdue_at    TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_pull_request_reviewers_review_due_at
+    ON pull_request_reviewers (review_due_at)
+    WHERE overdue_at IS NULL;
@@ -0,0 +1,25 @@
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS priority TEXT NOT NULL DEFAULT 'NORMAL';
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_priority_created_at_id
+    ON pull_requests (priority, created_at, pull_request_id);
+
+CREATE TABLE IF NOT EXISTS pull_request_labels (
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    label           TEXT NOT NULL,
+    PRIMARY KEY (pull_request_id, label)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pull_request_labels_label
+    ON pull_request_labels (label, pull_request_id);
+
+CREATE TABLE IF NOT EXISTS team_review_rules (
+    team_id            BIGINT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    position           INT    NOT NULL,
+    label              TEXT,
+    priority           TEXT,
+    reviewers_count    INT    NOT NULL,
+    required_approvals INT    NOT NULL DEFAULT 0,
+    PRIMARY KEY (team_id, position),
+    CHECK (label IS NOT NULL OR priority IS NOT NULL)
+);
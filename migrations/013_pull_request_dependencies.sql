@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS pull_request_dependencies (
+    pull_request_id TEXT        NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    depends_on_id   TEXT        NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (pull_request_id, depends_on_id),
+    CHECK (pull_request_id <> depends_on_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pull_request_dependencies_depends_on
+    ON pull_request_dependencies (depends_on_id, pull_request_id);
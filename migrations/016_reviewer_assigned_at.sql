@@ -0,0 +1,12 @@
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMPTZ;
+
+UPDATE pull_request_reviewers prr
+SET assigned_at = p.created_at
+FROM pull_requests p
+WHERE p.pull_request_id = prr.pull_request_id
+  AND prr.assigned_at IS NULL;
+
+ALTER TABLE pull_request_reviewers
+    ALTER COLUMN assigned_at SET DEFAULT now(),
+    ALTER COLUMN assigned_at SET NOT NULL;
@@ -0,0 +1,7 @@
+DELETE FROM pull_request_reviews rv
+WHERE NOT EXISTS (
+    SELECT 1
+    FROM pull_request_reviewers prr
+    WHERE prr.pull_request_id = rv.pull_request_id
+      AND prr.reviewer_id = rv.reviewer_id
+);
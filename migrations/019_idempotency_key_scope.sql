@@ -0,0 +1,12 @@
+ALTER TABLE idempotency_keys
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS method       TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS path         TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS actor_id     TEXT        NOT NULL DEFAULT '';
+
+ALTER TABLE idempotency_keys
+    ALTER COLUMN locked_until DROP DEFAULT,
+    DROP CONSTRAINT IF EXISTS idempotency_keys_pkey;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_idempotency_keys_scope
+    ON idempotency_keys (method, path, actor_id, idempotency_key);
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS delegations (
+    id           BIGSERIAL PRIMARY KEY,
+    from_user_id TEXT        NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    to_user_id   TEXT        NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    starts_at    TIMESTAMPTZ NOT NULL,
+    ends_at      TIMESTAMPTZ NOT NULL,
+    absence_id   BIGINT      REFERENCES absences(id) ON DELETE CASCADE,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    cancelled_at TIMESTAMPTZ,
+    CHECK (ends_at > starts_at),
+    CHECK (from_user_id <> to_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_delegations_from_user_id_window
+    ON delegations (from_user_id, starts_at, ends_at)
+    WHERE cancelled_at IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_delegations_absence_id
+    ON delegations (absence_id);
+
+INSERT INTO delegations (from_user_id, to_user_id, starts_at, ends_at, absence_id, created_at, cancelled_at)
+SELECT ab.user_id, ab.delegate_id, ab.starts_at, ab.ends_at, ab.id, ab.created_at, ab.cancelled_at
+FROM absences ab
+WHERE ab.delegate_id IS NOT NULL
+  AND ab.delegate_id <> ab.user_id
+  AND NOT EXISTS (
+      SELECT 1
+      FROM delegations d
+      WHERE d.absence_id = ab.id
+  );
+
+UPDATE absences
+SET delegate_id = NULL
+WHERE delegate_id IS NOT NULL;
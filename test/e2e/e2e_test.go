@@ -21,6 +21,9 @@ import (
 	httptransport "github.com/juzu400/avito-internship/internal/transport/http"
 )
 
+// testAdminToken authorizes admin-only actions such as forced merges in tests.
+const testAdminToken = "e2e-admin"
+
 func newTestServer(t *testing.T) (*httptest.Server, *repository.DB) {
 	t.Helper()
 
@@ -50,8 +53,8 @@ func newTestServer(t *testing.T) (*httptest.Server, *repository.DB) {
 	}
 
 	repos := repository.NewRepositories(db)
-	services := service.NewServices(log, repos)
-	router := httptransport.NewRouter(log, services)
+	services := service.NewServices(log, repos, service.Config{})
+	router := httptransport.NewRouter(log, services, testAdminToken)
 
 	srv := httptest.NewServer(router)
 	t.Cleanup(srv.Close)
@@ -190,6 +193,395 @@ func TestE2E_CreatePRAndStats(t *testing.T) {
 	}
 }
 
+func TestE2E_DeactivateTeamHandsReviewsToFallbackTeam(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	baseURL := srv.URL
+
+	teams := []map[string]any{
+		{
+			"team_name": "platform",
+			"members": []map[string]any{
+				{"user_id": "p1", "username": "Paul", "is_active": true},
+			},
+		},
+		{
+			"team_name": "backend",
+			"members": []map[string]any{
+				{"user_id": "u1", "username": "Alice", "is_active": true},
+				{"user_id": "u2", "username": "Bob", "is_active": true},
+				{"user_id": "u3", "username": "Carol", "is_active": true},
+			},
+		},
+	}
+	for _, team := range teams {
+		resp := doJSON(t, client, http.MethodPost, baseURL+"/team/add", team)
+		expectStatus(t, resp, http.StatusCreated)
+	}
+
+	resp := doJSON(t, client, http.MethodPost, baseURL+"/team/settings", map[string]any{
+		"team_name":      "backend",
+		"fallback_teams": []string{"platform"},
+	})
+	expectStatus(t, resp, http.StatusOK)
+
+	resp = doJSON(t, client, http.MethodPost, baseURL+"/pullRequest/create", map[string]any{
+		"pull_request_id":   "pr-e2e-2",
+		"pull_request_name": "E2E PR",
+		"author_id":         "u1",
+	})
+	expectStatus(t, resp, http.StatusCreated)
+
+	// Nobody in backend stays active, so one review goes to the fallback team
+	// and the other one is dropped.
+	resp = doJSON(t, client, http.MethodPost, baseURL+"/team/deactivateUsers", map[string]any{
+		"team_name": "backend",
+	})
+	expectStatus(t, resp, http.StatusOK)
+
+	var report struct {
+		DeactivatedUsers []string `json:"deactivated_users"`
+		Reassignments    []struct {
+			PullRequestID string `json:"pull_request_id"`
+			OldUserID     string `json:"old_user_id"`
+			NewUserID     string `json:"new_user_id"`
+			Dropped       bool   `json:"dropped"`
+		} `json:"reassignments"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatalf("decode deactivation report: %v", err)
+	}
+
+	if len(report.DeactivatedUsers) != 3 {
+		t.Fatalf("expected 3 deactivated users, got %v", report.DeactivatedUsers)
+	}
+	if len(report.Reassignments) != 2 {
+		t.Fatalf("expected 2 reassignments, got %+v", report.Reassignments)
+	}
+	var handedOver, dropped int
+	for _, ra := range report.Reassignments {
+		switch {
+		case ra.PullRequestID != "pr-e2e-2":
+			t.Fatalf("unexpected pull request in report: %+v", ra)
+		case ra.Dropped:
+			dropped++
+		case ra.NewUserID == "p1":
+			handedOver++
+		default:
+			t.Fatalf("unexpected reassignment: %+v", ra)
+		}
+	}
+	if handedOver != 1 || dropped != 1 {
+		t.Fatalf("expected one review handed to p1 and one dropped, got %+v", report.Reassignments)
+	}
+
+	resp = doJSON(t, client, http.MethodGet, baseURL+"/pullRequest/get?pull_request_id=pr-e2e-2", nil)
+	expectStatus(t, resp, http.StatusOK)
+
+	var got struct {
+		PR struct {
+			AssignedReviewers []string          `json:"assigned_reviewers"`
+			FallbackReviewers map[string]string `json:"fallback_reviewers"`
+		} `json:"pr"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("decode pull request: %v", err)
+	}
+	if len(got.PR.AssignedReviewers) != 1 || got.PR.AssignedReviewers[0] != "p1" {
+		t.Fatalf("expected p1 to be the only reviewer, got %v", got.PR.AssignedReviewers)
+	}
+	if got.PR.FallbackReviewers["p1"] != "platform" {
+		t.Fatalf("expected p1 to come from platform, got %v", got.PR.FallbackReviewers)
+	}
+}
+
+func TestE2E_ListUnderstaffedOpen(t *testing.T) {
+	srv, db := newTestServer(t)
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	baseURL := srv.URL
+
+	resp := doJSON(t, client, http.MethodPost, baseURL+"/team/add", map[string]any{
+		"team_name": "backend",
+		"members": []map[string]any{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+			{"user_id": "u3", "username": "Carol", "is_active": true},
+			{"user_id": "u4", "username": "Dave", "is_active": true},
+		},
+	})
+	expectStatus(t, resp, http.StatusCreated)
+
+	for _, id := range []string{"pr-open", "pr-closed", "pr-removed"} {
+		resp = doJSON(t, client, http.MethodPost, baseURL+"/pullRequest/create", map[string]any{
+			"pull_request_id":   id,
+			"pull_request_name": "E2E " + id,
+			"author_id":         "u1",
+		})
+		expectStatus(t, resp, http.StatusCreated)
+	}
+
+	resp = doJSON(t, client, http.MethodPost, baseURL+"/pullRequest/close", map[string]any{
+		"pull_request_id": "pr-closed",
+	})
+	expectStatus(t, resp, http.StatusOK)
+
+	resp = doJSON(t, client, http.MethodGet, baseURL+"/pullRequest/get?pull_request_id=pr-removed", nil)
+	expectStatus(t, resp, http.StatusOK)
+	var got struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("decode pull request: %v", err)
+	}
+	if len(got.PR.AssignedReviewers) == 0 {
+		t.Fatalf("expected pr-removed to have reviewers")
+	}
+	resp = doJSON(t, client, http.MethodPost, baseURL+"/pullRequest/removeReviewer", map[string]any{
+		"pull_request_id": "pr-removed",
+		"user_id":         got.PR.AssignedReviewers[0],
+	})
+	expectStatus(t, resp, http.StatusOK)
+
+	// Every pull request now has fewer reviewers than the team wants, but only
+	// the open one without a manual removal is up for top-up.
+	resp = doJSON(t, client, http.MethodPost, baseURL+"/team/settings", map[string]any{
+		"team_name":       "backend",
+		"reviewers_count": 3,
+	})
+	expectStatus(t, resp, http.StatusOK)
+
+	repos := repository.NewRepositories(db)
+	list, err := repos.PullRequests.ListUnderstaffedOpen(context.Background())
+	if err != nil {
+		t.Fatalf("ListUnderstaffedOpen: %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("expected one understaffed pull request, got %+v", list)
+	}
+	if list[0].PullRequestID != "pr-open" || list[0].ReviewersCount != 2 || list[0].ReviewersTarget != 3 {
+		t.Fatalf("unexpected understaffed pull request: %+v", list[0])
+	}
+}
+
+func TestE2E_DelegationSwapsReviewerForDelegate(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	baseURL := srv.URL
+
+	teams := []map[string]any{
+		{
+			"team_name": "platform",
+			"members": []map[string]any{
+				{"user_id": "p1", "username": "Paul", "is_active": true},
+			},
+		},
+		{
+			"team_name": "backend",
+			"members": []map[string]any{
+				{"user_id": "u1", "username": "Alice", "is_active": true},
+				{"user_id": "u2", "username": "Bob", "is_active": true},
+			},
+		},
+	}
+	for _, team := range teams {
+		resp := doJSON(t, client, http.MethodPost, baseURL+"/team/add", team)
+		expectStatus(t, resp, http.StatusCreated)
+	}
+
+	now := time.Now().UTC()
+	resp := doJSON(t, client, http.MethodPost, baseURL+"/users/addDelegation", map[string]any{
+		"from_user_id": "u2",
+		"to_user_id":   "p1",
+		"starts_at":    now.Add(-time.Minute),
+		"ends_at":      now.Add(time.Hour),
+	})
+	expectStatus(t, resp, http.StatusCreated)
+
+	var created struct {
+		Delegation struct {
+			DelegationID int64 `json:"delegation_id"`
+		} `json:"delegation"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("decode delegation: %v", err)
+	}
+
+	type reviewers struct {
+		PR struct {
+			AssignedReviewers  []string          `json:"assigned_reviewers"`
+			DelegatedReviewers map[string]string `json:"delegated_reviewers"`
+		} `json:"pr"`
+	}
+
+	resp = doJSON(t, client, http.MethodPost, baseURL+"/pullRequest/create", map[string]any{
+		"pull_request_id":   "pr-delegated",
+		"pull_request_name": "E2E delegated",
+		"author_id":         "u1",
+	})
+	expectStatus(t, resp, http.StatusCreated)
+
+	var delegated reviewers
+	if err := json.NewDecoder(resp.Body).Decode(&delegated); err != nil {
+		t.Fatalf("decode pull request: %v", err)
+	}
+	if len(delegated.PR.AssignedReviewers) != 1 || delegated.PR.AssignedReviewers[0] != "p1" {
+		t.Fatalf("expected p1 to review in place of u2, got %v", delegated.PR.AssignedReviewers)
+	}
+	if delegated.PR.DelegatedReviewers["p1"] != "u2" {
+		t.Fatalf("expected p1 to be delegated from u2, got %v", delegated.PR.DelegatedReviewers)
+	}
+
+	resp = doJSON(t, client, http.MethodPost, baseURL+"/users/cancelDelegation", map[string]any{
+		"delegation_id": created.Delegation.DelegationID,
+	})
+	expectStatus(t, resp, http.StatusOK)
+
+	resp = doJSON(t, client, http.MethodPost, baseURL+"/pullRequest/create", map[string]any{
+		"pull_request_id":   "pr-direct",
+		"pull_request_name": "E2E direct",
+		"author_id":         "u1",
+	})
+	expectStatus(t, resp, http.StatusCreated)
+
+	var direct reviewers
+	if err := json.NewDecoder(resp.Body).Decode(&direct); err != nil {
+		t.Fatalf("decode pull request: %v", err)
+	}
+	if len(direct.PR.AssignedReviewers) != 1 || direct.PR.AssignedReviewers[0] != "u2" {
+		t.Fatalf("expected u2 to review once the delegation is cancelled, got %v", direct.PR.AssignedReviewers)
+	}
+}
+
+func TestE2E_MergeRequiresApprovals(t *testing.T) {
+	srv, db := newTestServer(t)
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	baseURL := srv.URL
+
+	resp := doJSON(t, client, http.MethodPost, baseURL+"/team/add", map[string]any{
+		"team_name": "backend",
+		"members": []map[string]any{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+			{"user_id": "u3", "username": "Carol", "is_active": true},
+		},
+	})
+	expectStatus(t, resp, http.StatusCreated)
+
+	// Two reviewers are assigned, so three required approvals are capped at two.
+	resp = doJSON(t, client, http.MethodPost, baseURL+"/team/settings", map[string]any{
+		"team_name":          "backend",
+		"reviewers_count":    2,
+		"required_approvals": 3,
+	})
+	expectStatus(t, resp, http.StatusOK)
+
+	for _, id := range []string{"pr-approved", "pr-readded", "pr-forced"} {
+		resp = doJSON(t, client, http.MethodPost, baseURL+"/pullRequest/create", map[string]any{
+			"pull_request_id":   id,
+			"pull_request_name": "E2E " + id,
+			"author_id":         "u1",
+		})
+		expectStatus(t, resp, http.StatusCreated)
+	}
+
+	review := func(prID, userID string) {
+		t.Helper()
+		resp := doJSON(t, client, http.MethodPost, baseURL+"/pullRequest/review", map[string]any{
+			"pull_request_id": prID,
+			"user_id":         userID,
+			"decision":        "APPROVED",
+		})
+		expectStatus(t, resp, http.StatusOK)
+	}
+	merge := func(prID string, want int) {
+		t.Helper()
+		resp := doJSON(t, client, http.MethodPost, baseURL+"/pullRequest/merge", map[string]any{
+			"pull_request_id": prID,
+		})
+		expectStatus(t, resp, want)
+		if want != http.StatusConflict {
+			return
+		}
+		var body struct {
+			Error struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("decode merge error: %v", err)
+		}
+		if body.Error.Code != "NOT_APPROVED" {
+			t.Fatalf("merge %s: expected NOT_APPROVED, got %s", prID, body.Error.Code)
+		}
+	}
+
+	merge("pr-approved", http.StatusConflict)
+	review("pr-approved", "u2")
+	merge("pr-approved", http.StatusConflict)
+	review("pr-approved", "u3")
+	merge("pr-approved", http.StatusOK)
+
+	// An approval does not survive the reviewer being removed and added again.
+	review("pr-readded", "u2")
+	for _, path := range []string{"/pullRequest/removeReviewer", "/pullRequest/addReviewer"} {
+		resp = doJSON(t, client, http.MethodPost, baseURL+path, map[string]any{
+			"pull_request_id": "pr-readded",
+			"user_id":         "u2",
+		})
+		expectStatus(t, resp, http.StatusOK)
+	}
+	review("pr-readded", "u3")
+	merge("pr-readded", http.StatusConflict)
+
+	review("pr-forced", "u2")
+	body, err := json.Marshal(map[string]any{"pull_request_id": "pr-forced", "force": true})
+	if err != nil {
+		t.Fatalf("marshal body: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, baseURL+"/pullRequest/merge", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("do request: %v", err)
+	}
+	expectStatus(t, resp, http.StatusOK)
+
+	var approvals, required int
+	if err := db.Pool.QueryRow(context.Background(), `
+        SELECT approvals, required_approvals
+        FROM forced_merges
+        WHERE pull_request_id = $1
+    `, "pr-forced").Scan(&approvals, &required); err != nil {
+		t.Fatalf("get forced merge: %v", err)
+	}
+	if approvals != 1 || required != 2 {
+		t.Fatalf("expected forced merge with 1 of 2 approvals, got %d of %d", approvals, required)
+	}
+}
+
+// expectStatus fails the test if resp has another status code. The body is
+// closed when the test ends.
+func expectStatus(t *testing.T, resp *http.Response, want int) {
+	t.Helper()
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	if resp.StatusCode != want {
+		b, _ := io.ReadAll(resp.Body)
+		t.Fatalf("%s %s: expected status %d, got %d, body: %s",
+			resp.Request.Method, resp.Request.URL.Path, want, resp.StatusCode, string(b))
+	}
+}
+
 func migrationsPath(t *testing.T) string {
 	t.Helper()
 